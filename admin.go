@@ -16,9 +16,11 @@ package main
 
 import (
 	"fmt"
+	"html/template"
 	"log"
 	"net/http"
 	"sort"
+	"strconv"
 	"time"
 
 	"github.com/sushovande/qr-mixer-game/common"
@@ -36,18 +38,26 @@ type DisplayUser struct {
 	SurveyAnswers []bool
 	Level         int64
 	Health        int64
+	Points        int64
 	HasAl         bool
 	HasCu         bool
 	HasSn         bool
 	HasZn         bool
+	BingoLines    int64
+	BingoWon      bool
+	Target        string
+	Tags          int64
+	ChainStatus   string
+	Alive         bool
+	// Buddies lists the secret buddies picked for the player, one per SECRET_BUDDY question.
+	Buddies       []string
+	PokerHand     string
+	PokerHandName string
+	PokerScore    int64
+	// Handicap describes the player's adjustments from the roster, if any.
+	Handicap string
 }
 
-type ByLevel []DisplayUser
-
-func (a ByLevel) Len() int           { return len(a) }
-func (a ByLevel) Less(i, j int) bool { return a[i].Level < a[j].Level }
-func (a ByLevel) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
-
 func (env *Env) adminAllUsers(w http.ResponseWriter, r *http.Request) {
 	log.Println("Req: ", r.URL)
 	srs, err := AdminGetAllUserStates(env.GetDb())
@@ -60,6 +70,47 @@ func (env *Env) adminAllUsers(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	gc, err := env.cgo.GetGameConfig()
+	if common.Should500(err, w, "could not get the game settings") {
+		return
+	}
+	mode, err := env.CurrentGameMode()
+	if common.Should500(err, w, "could not get the game mode") {
+		return
+	}
+
+	qrm, err := env.cgo.GetQRMappings()
+	if common.Should500(err, w, "could not get the roster") {
+		return
+	}
+
+	// Warn the organizer about players stuck on a question that cannot be answered, and about
+	// survey questions that too few players can answer.
+	alerts := make([]LevelAlert, 0)
+	coverage := make([]SurveyCoverageRow, 0)
+	if _, isHunt := mode.(huntMode); isHunt {
+		sqs, err := env.cgo.GetGameQSet()
+		if common.Should500(err, w, "could not get the questions") {
+			return
+		}
+		alerts = MisconfiguredLevels(srs, sqs, opt)
+		coverage = SurveyCoverage(srs, sqs, opt, gc)
+	}
+
+	hasBuddies := false
+	hasHandicaps := false
+	chain := make(map[string]AssassinRow)
+	isAssassin := gc.GetGameMode() == GAME_MODE_ASSASSIN
+	if isAssassin {
+		ars, err := GetAllAssassins(env.GetDb())
+		if common.Should500(err, w, "could not get the target chain") {
+			return
+		}
+		for _, ar := range ars {
+			chain[ar.Username] = ar
+		}
+	}
+
 	numSurveyAns := len(opt.GetSurveyQuestions())
 
 	allU := make([]DisplayUser, 0)
@@ -70,6 +121,7 @@ func (env *Env) adminAllUsers(w http.ResponseWriter, r *http.Request) {
 		du.SurveyAnswers = make([]bool, numSurveyAns)
 		du.Level = u.State.GetUserLevel()
 		du.Health = u.State.GetLife()
+		du.Points = u.State.GetPoints()
 
 		for _, b := range u.UserInfo.SurveyAnswers {
 			if b.GetQuestionId()-1 < int64(numSurveyAns) {
@@ -81,6 +133,26 @@ func (env *Env) adminAllUsers(w http.ResponseWriter, r *http.Request) {
 		du.HasCu = u.State.GetHasCu()
 		du.HasSn = u.State.GetHasSn()
 		du.HasZn = u.State.GetHasZn()
+		du.BingoLines = u.State.GetBingoLines()
+		du.BingoWon = u.State.GetBingoWon()
+		ph := EvaluatePokerHand(u.State.GetPokerHand())
+		du.PokerHand = PokerHandString(u.State.GetPokerHand())
+		du.PokerHandName = ph.Name
+		du.PokerScore = ph.Score
+		for _, b := range u.State.GetBuddies() {
+			du.Buddies = append(du.Buddies, fmt.Sprintf("Q%v: %v", b.GetQuestionId(), b.GetUsername()))
+			hasBuddies = true
+		}
+		du.Handicap = DescribeHandicap(qrm.LookupByUsername(u.Username).GetHandicap(), u.State)
+		if len(du.Handicap) > 0 {
+			hasHandicaps = true
+		}
+		if ar, ok := chain[u.Username]; ok {
+			du.Target = ar.Target
+			du.Tags = ar.Tags
+			du.Alive = ar.Status == qrpb.AssassinStatus_ASSASSIN_ALIVE
+			du.ChainStatus = assassinStatusText(ar)
+		}
 
 		allU = append(allU, du)
 	}
@@ -90,14 +162,31 @@ func (env *Env) adminAllUsers(w http.ResponseWriter, r *http.Request) {
 		SurveyQNames[i] = fmt.Sprintf("SQ%v", (i + 1))
 	}
 
-	sort.Sort(sort.Reverse(ByLevel(allU)))
+	isBingo := gc.GetGameMode() == GAME_MODE_BINGO
+	sort.SliceStable(allU, func(i, j int) bool { return mode.RanksAhead(&allU[i], &allU[j]) })
 
 	rd := struct {
-		SurveyQ []string
-		Users   []DisplayUser
+		SurveyQ      []string
+		Users        []DisplayUser
+		IsBingo      bool
+		IsAssassin   bool
+		HasBuddies   bool
+		IsPoker      bool
+		HasHandicaps bool
+		FlagHandicap bool
+		Alerts       []LevelAlert
+		Coverage     []SurveyCoverageRow
 	}{
-		SurveyQ: SurveyQNames,
-		Users:   allU,
+		SurveyQ:      SurveyQNames,
+		Users:        allU,
+		IsBingo:      isBingo,
+		IsAssassin:   isAssassin,
+		HasBuddies:   hasBuddies,
+		IsPoker:      gc.GetGameMode() == GAME_MODE_POKER || gc.GetPoker().GetAfterHunt(),
+		HasHandicaps: hasHandicaps,
+		FlagHandicap: gc.GetFlagHandicaps(),
+		Alerts:       alerts,
+		Coverage:     coverage,
 	}
 	common.RenderTemplate(w, env.tem, "adminallusers.html", rd)
 
@@ -203,21 +292,42 @@ func (env *Env) adminRenderQuestions(w http.ResponseWriter, r *http.Request) {
 	log.Println("Req: ", r.URL)
 
 	surveyq, err := env.cgo.GetSurveySet()
-	if err != nil {
-		common.Should500(err, w, "could not read survey")
+	if common.Should500(err, w, "could not read survey") {
+		return
 	}
 
 	gqset, err := env.cgo.GetGameQSet()
-	if err != nil {
-		common.Should500(err, w, "could not read static")
+	if common.Should500(err, w, "could not read static") {
+		return
+	}
+
+	gc, err := env.cgo.GetGameConfig()
+	if common.Should500(err, w, "could not read game config") {
+		return
+	}
+
+	def, err := env.CurrentGameDefinition()
+	if common.Should500(err, w, "could not read the saved game") {
+		return
+	}
+
+	srs, err := AdminGetAllUserStates(env.db)
+	if common.Should500(err, w, "could not fetch the players") {
+		return
 	}
 
 	qns := struct {
 		SurveyQuestions string
 		GameQuestions   string
+		GameConfig      string
+		Report          *ValidationReport
+		Variants        []LevelVariants
 	}{
 		SurveyQuestions: prototext.Format(surveyq),
 		GameQuestions:   prototext.Format(gqset),
+		GameConfig:      prototext.Format(gc),
+		Report:          ValidateGame(def),
+		Variants:        QuestionVariantUsage(srs, gqset),
 	}
 
 	common.RenderTemplate(w, env.tem, "adminquestions.html", qns)
@@ -229,15 +339,25 @@ func (env *Env) adminSaveQuestions(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	def, err := env.CurrentGameDefinition()
+	if common.Should500(err, w, "could not read the saved game") {
+		return
+	}
+	parts := make([]string, 0)
+	// Whatever the sanitizer strips is saved without it, and listed in the report.
+	stripped := make([]ValidationIssue, 0)
+
 	surveyq := r.FormValue("survey")
 	if len(surveyq) > 0 {
 		var sset qrpb.SurveySet
 		if common.Should500(prototext.Unmarshal([]byte(surveyq), &sset), w, "proto parse error survey") {
 			return
 		}
-		if common.Should500(env.cgo.SetSurveySet(&sset), w, "error saving survey qn") {
-			return
+		for _, l := range SanitizeSurvey(&sset) {
+			stripped = append(stripped, ValidationIssue{Part: PART_SURVEY, Message: "stripped from " + l})
 		}
+		def.Survey = &sset
+		parts = append(parts, PART_SURVEY)
 	}
 
 	gqsetfv := r.FormValue("gameq")
@@ -246,12 +366,51 @@ func (env *Env) adminSaveQuestions(w http.ResponseWriter, r *http.Request) {
 		if common.Should500(prototext.Unmarshal([]byte(gqsetfv), &gqset), w, "proto parse error static qn") {
 			return
 		}
-		if common.Should500(env.cgo.SetGameQSet(&gqset), w, "error saving static qn") {
+		for _, l := range SanitizeQuestions(&gqset) {
+			stripped = append(stripped, ValidationIssue{Part: PART_QUESTIONS, Message: "stripped from " + l})
+		}
+		def.Questions = &gqset
+		parts = append(parts, PART_QUESTIONS)
+	}
+
+	gcfv := r.FormValue("gameconfig")
+	if len(gcfv) > 0 {
+		var gc qrpb.GameConfig
+		if common.Should500(prototext.Unmarshal([]byte(gcfv), &gc), w, "proto parse error game config") {
 			return
 		}
+		def.Config = &gc
+		parts = append(parts, PART_CONFIG)
 	}
 
-	fmt.Fprint(w, "ok")
+	// Only the parts being saved can block the save, so the roster and questions can be fixed in
+	// either order.
+	vr := ValidateGame(def)
+	vr.Warnings = append(stripped, vr.Warnings...)
+	if vr.HasErrorsIn(parts...) {
+		common.Should500(fmt.Errorf("invalid game: %v errors", len(vr.Errors)), w, vr.String())
+		return
+	}
+
+	for _, p := range parts {
+		switch p {
+		case PART_SURVEY:
+			err = env.cgo.SetSurveySet(def.Survey)
+		case PART_QUESTIONS:
+			err = env.cgo.SetGameQSet(def.Questions)
+		case PART_CONFIG:
+			err = env.cgo.SetGameConfig(def.Config)
+		}
+		if common.Should500(err, w, "error saving the "+p) {
+			return
+		}
+	}
+	// Turning on auto_backup partway through the game swaps in backups right away.
+	if _, err := env.CheckSurveyCoverage(); common.Should500(err, w, "saved, but could not check the survey coverage") {
+		return
+	}
+
+	fmt.Fprint(w, "ok\n"+vr.String())
 }
 
 func (env *Env) adminUpdateUser(w http.ResponseWriter, r *http.Request) {
@@ -282,25 +441,60 @@ func (env *Env) adminUpdateUser(w http.ResponseWriter, r *http.Request) {
 func (env *Env) adminRenderManagerUsers(w http.ResponseWriter, r *http.Request) {
 	log.Println("Req: ", r.URL)
 
-	userTSV := "Name\tUsername\tqrcode\tcardsuit\tcardrank\n"
 	qrm, err := env.cgo.GetQRMappings()
 	if common.Should500(err, w, "could not get list of users") {
 		return
 	}
 
+	// The handicap and digital columns are only shown once someone needs them.
+	withHandicaps := false
+	withDigital := false
+	joinLinks := make([]JoinLinkRow, 0)
 	for _, k := range qrm.mappings.GetQrMappings() {
-		userTSV += fmt.Sprintf("%v\t%v\t%v\t%v\t%v\n",
+		if k.Handicap != nil {
+			withHandicaps = true
+		}
+		if k.GetDigitalOnly() {
+			withDigital = true
+			joinLinks = append(joinLinks, JoinLinkRow{Name: k.GetDisplayName(), Link: JoinLink(k)})
+		}
+	}
+
+	userTSV := "Name\tUsername\tqrcode\tcardsuit\tcardrank"
+	if withHandicaps {
+		userTSV += "\textralives\tnopenalty\thelper\thelperscans"
+	}
+	if withDigital {
+		userTSV += "\tdigital"
+	}
+	userTSV += "\n"
+	for _, k := range qrm.mappings.GetQrMappings() {
+		userTSV += fmt.Sprintf("%v\t%v\t%v\t%v\t%v",
 			k.GetDisplayName(),
 			k.GetUsername(),
 			k.GetQrcode(),
 			k.GetCardSuit(),
 			k.GetCardRank())
+		if withHandicaps {
+			h := k.GetHandicap()
+			userTSV += fmt.Sprintf("\t%v\t%v\t%v\t%v",
+				h.GetExtraLives(),
+				h.GetNoPenalty(),
+				h.GetHelper(),
+				h.GetHelperScans())
+		}
+		if withDigital {
+			userTSV += fmt.Sprintf("\t%v", k.GetDigitalOnly())
+		}
+		userTSV += "\n"
 	}
 
 	uss := struct {
-		UserTSV string
+		UserTSV   string
+		JoinLinks []JoinLinkRow
 	}{
-		UserTSV: userTSV,
+		UserTSV:   userTSV,
+		JoinLinks: joinLinks,
 	}
 
 	common.RenderTemplate(w, env.tem, "adminmanageusers.html", uss)
@@ -323,11 +517,24 @@ func (env *Env) adminSaveUserQrMapping(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	def, err := env.CurrentGameDefinition()
+	if common.Should500(err, w, "could not read the saved game") {
+		return
+	}
+	def.Roster = &qrset
+	// Questions that name players missing from the new roster are reported, but do not block the
+	// save, so a new roster can be uploaded before the questions are updated to match.
+	vr := ValidateGame(def)
+	if vr.HasErrorsIn(PART_ROSTER) {
+		common.Should500(fmt.Errorf("invalid roster: %v errors", len(vr.Errors)), w, vr.String())
+		return
+	}
+
 	if common.Should500(env.cgo.SetQRMappings(&QRMappings{mappings: &qrset}), w, "could not store user set") {
 		return
 	}
 
-	fmt.Fprint(w, "ok")
+	fmt.Fprint(w, "ok\n"+vr.String())
 }
 
 func (env *Env) adminRenderPrintBadges(w http.ResponseWriter, r *http.Request) {
@@ -338,7 +545,139 @@ func (env *Env) adminRenderPrintBadges(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	common.RenderTemplate(w, env.tem, "adminprintbadges.html", qrm.mappings)
+	// Digital-only players show their badge on their phone instead.
+	common.RenderTemplate(w, env.tem, "adminprintbadges.html", PrintableMappings(qrm.mappings))
+}
+
+func (env *Env) adminRenderPhotoReview(w http.ResponseWriter, r *http.Request) {
+	log.Println("Req: ", r.URL)
+	prs, err := GetPendingPhotos(env.GetDb())
+	if common.Should500(err, w, "could not fetch the photos to review") {
+		return
+	}
+
+	sqs, err := env.cgo.GetGameQSet()
+	if common.Should500(err, w, "could not read the game questions") {
+		return
+	}
+
+	type StrPhoto struct {
+		ID       int64
+		Username string
+		Updated  string
+		Question template.HTML
+	}
+
+	kol, err := time.LoadLocation("Asia/Kolkata")
+	if common.Should500(err, w, "could not make a timezone") {
+		return
+	}
+
+	photos := make([]StrPhoto, 0)
+	for _, v := range prs {
+		// Show the variant of the question this player was given.
+		sr, err := GetUserStateByUsername(env.GetDb(), v.Username)
+		if common.Should500(err, w, "could not fetch the player") {
+			return
+		}
+		variant := ""
+		if sr != nil {
+			variant, _ = VariantFor(sr.State, v.QuestionID)
+		}
+		photos = append(photos, StrPhoto{
+			ID:       v.ID,
+			Username: v.Username,
+			Updated:  time.Unix(v.Updated/1000000, 0).In(kol).Format("2006-01-02 3:04:05 PM"),
+			Question: template.HTML(GetQuestionVariant(sqs, v.QuestionID, variant).GetQuestionHtml()),
+		})
+	}
+
+	common.RenderTemplate(w, env.tem, "adminphotoreview.html", photos)
+}
+
+func (env *Env) adminGetPhoto(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
+	if common.Should500(err, w, "could not parse the photo id") {
+		return
+	}
+
+	pr, err := GetPhotoByID(env.GetDb(), id)
+	if common.Should500(err, w, "could not fetch the photo") {
+		return
+	}
+	if pr == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", pr.ContentType)
+	w.Write(pr.Photo)
+}
+
+func (env *Env) adminReviewPhoto(w http.ResponseWriter, r *http.Request) {
+	log.Println("Req: ", r.URL)
+	if common.Should500(r.ParseForm(), w, "error parsing data for photo review") {
+		return
+	}
+
+	id, err := strconv.ParseInt(r.FormValue("id"), 10, 64)
+	if common.Should500(err, w, "could not parse the photo id") {
+		return
+	}
+	approved := r.FormValue("approve") == "true"
+
+	pr, err := GetPhotoByID(env.GetDb(), id)
+	if common.Should500(err, w, "could not fetch the photo") {
+		return
+	}
+	if pr == nil || pr.Status != qrpb.PhotoStatus_PHOTO_PENDING {
+		common.Should500(fmt.Errorf("photo %v is not pending review", id), w, "this photo has already been reviewed")
+		return
+	}
+
+	sr, err := GetUserStateByUsername(env.GetDb(), pr.Username)
+	if common.Should500(err, w, "could not fetch details for this user") {
+		return
+	}
+
+	// Only move the player if they are still waiting on the level this photo was taken for.
+	if sr != nil && sr.State.GetPhotoPending() && sr.State.GetUserLevel() == pr.QuestionID {
+		h, err := env.HandicapFor(sr.Username)
+		if common.Should500(err, w, "could not read the player's handicap") {
+			return
+		}
+		stepResult, err := env.StepPhotoReview(sr.State, approved, h)
+		if common.Should500(err, w, "could not apply the review") {
+			return
+		}
+
+		lr := NewLogRow()
+		lr.Username = sr.Username
+		lr.Updated = time.Now().UnixNano() / 1000
+		lr.GameLog = &qrpb.ActionLog{
+			OldState:      sr.State,
+			TimestampUsec: proto.Int64(lr.Updated),
+			ClueShortName: proto.String(fmt.Sprintf("photo-%v", pr.ID)),
+			Result:        &stepResult.actionResult,
+			Type:          qrpb.ActionLog_ACTION_PHOTO_REVIEW.Enum(),
+		}
+		if common.Should500(UpdateUserDetailsWithProto(env.GetDb(), sr.UserInfo, stepResult.newState), w, "error saving data") {
+			return
+		}
+		if common.Should500(AddActionLog(env.GetDb(), &lr), w, "could not log the review") {
+			return
+		}
+	}
+
+	status := qrpb.PhotoStatus_PHOTO_REJECTED
+	if approved {
+		status = qrpb.PhotoStatus_PHOTO_APPROVED
+	}
+	if common.Should500(SetPhotoStatus(env.GetDb(), pr.ID, status), w, "could not save the review") {
+		return
+	}
+
+	fmt.Fprint(w, "ok")
 }
 
 func MarshalTextString(m proto.Message) string {
@@ -42,8 +42,11 @@ func (env *Env) adminGetCard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	suit := words[0]
-	rankString := strings.TrimSuffix(words[1], ".svg")
+	env.renderCardSVG(w, words[0], strings.TrimSuffix(words[1], ".svg"))
+}
+
+// renderCardSVG draws a playing card. suit is a CardSuit name and rankString is its card_rank.
+func (env *Env) renderCardSVG(w http.ResponseWriter, suit string, rankString string) {
 	colorString := "#000"
 
 	suitUnicode := "♠"
@@ -0,0 +1,120 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/sushovande/qr-mixer-game/qrpb"
+
+	proto "google.golang.org/protobuf/proto"
+)
+
+const DEFAULT_WRONG_PENALTY int64 = 1
+
+// AnswerRules are the penalty, reward and attempt limit that apply to one question.
+type AnswerRules struct {
+	Penalty     int64
+	Reward      *qrpb.QuestionReward
+	MaxAttempts int64
+}
+
+// RulesFor works out the rules for the question, falling back to the game-wide defaults for
+// anything the question does not set.
+func RulesFor(sq *qrpb.GameQuestion, gc *qrpb.GameConfig) AnswerRules {
+	d := gc.GetQuestionDefaults()
+	rules := AnswerRules{
+		Penalty:     DEFAULT_WRONG_PENALTY,
+		Reward:      d.GetReward(),
+		MaxAttempts: d.GetMaxAttempts(),
+	}
+	if d != nil && d.WrongPenalty != nil {
+		rules.Penalty = d.GetWrongPenalty()
+	}
+	if sq == nil {
+		return rules
+	}
+	if sq.WrongPenalty != nil {
+		rules.Penalty = sq.GetWrongPenalty()
+	}
+	if sq.Reward != nil {
+		rules.Reward = sq.GetReward()
+	}
+	if sq.MaxAttempts != nil {
+		rules.MaxAttempts = sq.GetMaxAttempts()
+	}
+	return rules
+}
+
+// AnswerCorrect moves the player on to the next question and hands out the reward.
+func AnswerCorrect(result *StepResponse, rules AnswerRules) {
+	gs := result.newState
+	gs.UserLevel = proto.Int64(gs.GetUserLevel() + 1)
+	gs.WrongAttempts = nil
+	gs.RouteProgress = nil
+	result.actionString = "Correct!"
+	result.actionResult = *qrpb.ActionLog_RESULT_PROGRESS.Enum()
+
+	r := rules.Reward
+	if r.GetPoints() != 0 {
+		gs.Points = proto.Int64(gs.GetPoints() + r.GetPoints())
+	}
+	if r.GetLives() != 0 {
+		gs.Life = proto.Int64(gs.GetLife() + r.GetLives())
+	}
+	if len(r.GetToken()) > 0 {
+		GrantMetal(result, r.GetToken())
+	}
+}
+
+// AnswerWrong takes away the penalty, and skips to the next question once the player runs out of attempts.
+func AnswerWrong(result *StepResponse, rules AnswerRules) {
+	gs := result.newState
+	gs.Life = proto.Int64(gs.GetLife() - rules.Penalty)
+	gs.WrongAttempts = proto.Int64(gs.GetWrongAttempts() + 1)
+	if rules.Penalty > 0 {
+		result.actionString = "Lost a Life!"
+		if rules.Penalty > 1 {
+			result.actionString = fmt.Sprintf("Lost %v Lives!", rules.Penalty)
+		}
+		result.actionResult = *qrpb.ActionLog_RESULT_LOST_LIFE.Enum()
+	} else {
+		result.actionString = "Not Quite!"
+		result.actionResult = *qrpb.ActionLog_RESULT_WRONG_ANSWER.Enum()
+	}
+
+	if rules.MaxAttempts > 0 && gs.GetWrongAttempts() >= rules.MaxAttempts && gs.GetLife() > 0 {
+		gs.UserLevel = proto.Int64(gs.GetUserLevel() + 1)
+		gs.WrongAttempts = nil
+		gs.RouteProgress = nil
+		result.actionString = "Skipped!"
+		result.actionResult = *qrpb.ActionLog_RESULT_SKIPPED.Enum()
+	}
+}
+
+// CheckAnswerRules returns an error if a penalty, reward or attempt limit makes no sense.
+// what names the question or the defaults in the error message.
+func CheckAnswerRules(what string, penalty *int64, reward *qrpb.QuestionReward, maxAttempts *int64) error {
+	if penalty != nil && *penalty < 0 {
+		return fmt.Errorf("%v: wrong_penalty cannot be negative", what)
+	}
+	if maxAttempts != nil && *maxAttempts < 0 {
+		return fmt.Errorf("%v: max_attempts cannot be negative", what)
+	}
+	if t := reward.GetToken(); len(t) > 0 && !ListHasString([]string{"al", "cu", "sn", "zn"}, t) {
+		return fmt.Errorf("%v: unknown reward token %q, expected al, cu, sn or zn", what, t)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/sushovande/qr-mixer-game/qrpb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestRulesFor(t *testing.T) {
+	r := RulesFor(&qrpb.GameQuestion{}, &qrpb.GameConfig{})
+	if r.Penalty != 1 || r.MaxAttempts != 0 || r.Reward != nil {
+		t.Errorf("expected one life and no limit by default. got: %+v", r)
+	}
+
+	gc := &qrpb.GameConfig{QuestionDefaults: &qrpb.QuestionDefaults{
+		WrongPenalty: proto.Int64(2),
+		MaxAttempts:  proto.Int64(4),
+		Reward:       &qrpb.QuestionReward{Points: proto.Int64(10)},
+	}}
+	r = RulesFor(&qrpb.GameQuestion{}, gc)
+	if r.Penalty != 2 || r.MaxAttempts != 4 || r.Reward.GetPoints() != 10 {
+		t.Errorf("expected the game defaults. got: %+v", r)
+	}
+
+	r = RulesFor(&qrpb.GameQuestion{WrongPenalty: proto.Int64(0), MaxAttempts: proto.Int64(0)}, gc)
+	if r.Penalty != 0 || r.MaxAttempts != 0 || r.Reward.GetPoints() != 10 {
+		t.Errorf("expected the question to override the defaults with zero. got: %+v", r)
+	}
+}
+
+func TestQuestionPenaltyAndReward(t *testing.T) {
+	env, err := createEnv(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer env.db.Close()
+	setupSynthetic(env.cgo, 10)
+	sqs, _ := env.cgo.GetGameQSet()
+	// Q2 is hard: free to get wrong, skipped after two tries.
+	sqs.GameQuestions[1].WrongPenalty = proto.Int64(0)
+	sqs.GameQuestions[1].MaxAttempts = proto.Int64(2)
+	// Q3 is easy: a wrong answer costs two lives, a right one pays a life and points.
+	sqs.GameQuestions[2].Type = qrpb.GQType_USERNAME_LIST.Enum()
+	sqs.GameQuestions[2].AnsUsernames = []string{"username-3", "username-4"}
+	sqs.GameQuestions[2].WrongPenalty = proto.Int64(2)
+	sqs.GameQuestions[2].Reward = &qrpb.QuestionReward{Points: proto.Int64(5), Lives: proto.Int64(1), Token: proto.String("zn")}
+	env.cgo.SetGameQSet(sqs)
+
+	u1 := GetSyntheticStateRow(1, 2)
+	mr, _ := env.Step(u1.State, "qrcode-9")
+	if mr.actionString != "Not Quite!" || mr.newState.GetLife() != 3 || mr.newState.GetWrongAttempts() != 1 {
+		t.Errorf("expected a free wrong answer. got: %v %v", mr.actionString, mr.newState)
+	}
+	mr, _ = env.Step(mr.newState, "qrcode-9")
+	if mr.actionString != "Skipped!" || mr.newState.GetUserLevel() != 3 || mr.newState.WrongAttempts != nil {
+		t.Errorf("expected to skip after two attempts. got: %v %v", mr.actionString, mr.newState)
+	}
+
+	wrong, _ := env.Step(mr.newState, "qrcode-9")
+	if wrong.actionString != "Lost 2 Lives!" || wrong.newState.GetLife() != 1 {
+		t.Errorf("expected to lose two lives. got: %v %v", wrong.actionString, wrong.newState)
+	}
+	mr, _ = env.Step(mr.newState, "qrcode-3")
+	if mr.actionString != "Correct!" || mr.newState.GetLife() != 4 || mr.newState.GetPoints() != 5 || !mr.newState.GetHasZn() {
+		t.Errorf("expected the reward. got: %v %v", mr.actionString, mr.newState)
+	}
+
+	f := callController("POST", "/saveQuestions", "gameconfig="+`question_defaults: { wrong_penalty: -1 }`, nil, env.adminSaveQuestions)
+	if f.statuscode != http.StatusInternalServerError {
+		t.Errorf("expected a negative penalty to be rejected. got: %v", f.resptext)
+	}
+	f = callController("POST", "/saveQuestions", "gameq="+`game_questions: { question_id: 1 type: ANY_PERSON reward: { token: "gold" } }`, nil, env.adminSaveQuestions)
+	if f.statuscode != http.StatusInternalServerError {
+		t.Errorf("expected an unknown token to be rejected. got: %v", f.resptext)
+	}
+}
@@ -0,0 +1,498 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/sushovande/qr-mixer-game/common"
+	"github.com/sushovande/qr-mixer-game/qrpb"
+
+	proto "google.golang.org/protobuf/proto"
+)
+
+const GAME_MODE_ASSASSIN = "assassin"
+
+// assassinMode is a last-one-standing game where every player hunts the next one in a hidden chain.
+type assassinMode struct{}
+
+func init() {
+	RegisterGameMode(GAME_MODE_ASSASSIN, assassinMode{})
+}
+
+func (assassinMode) InitialState(env *Env, username string) (*qrpb.GameState, error) {
+	return env.NewGameState()
+}
+
+func (assassinMode) Step(env *Env, u *StateRow, answer string) (StepResponse, error) {
+	return env.StepAssassin(u, answer)
+}
+
+func (assassinMode) Clue(env *Env, u *StateRow) (ClueData, error) {
+	clue, err := env.RenderAssassinClue(u)
+	return ClueData{HTML: clue}, err
+}
+
+func (assassinMode) RanksAhead(a, b *DisplayUser) bool {
+	if a.Alive != b.Alive {
+		return a.Alive
+	}
+	return a.Tags > b.Tags
+}
+
+// Prepare puts the player in the chain, including players who registered before the game
+// switched to assassin. The chain lives in its own table, so the state never changes.
+func (assassinMode) Prepare(env *Env, u *StateRow) (bool, error) {
+	return false, env.AssassinJoin(u.Username)
+}
+
+// AssassinRow is a player's place in the target chain of the assassin game mode.
+type AssassinRow struct {
+	Username string
+	// Target is the username this player has to find. It is empty once the player is
+	// the last one standing, or is out of the game.
+	Target   string
+	Status   qrpb.AssassinStatus
+	Tags     int64
+	TaggedBy string
+}
+
+// queryer is satisfied by both *sql.DB and *sql.Tx.
+type queryer interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// MaybeCreateAssassinTable creates the target chain table in the db if it didn't exist
+func MaybeCreateAssassinTable(db *sql.DB) error {
+	const createStmt = `
+	CREATE TABLE IF NOT EXISTS assassins (
+		username TEXT PRIMARY KEY,
+		target TEXT,
+		status INT,
+		tags INT,
+		taggedby TEXT
+	);
+	`
+	_, err := db.Exec(createStmt)
+	if err != nil {
+		log.Printf("Db-err: %v\n", err)
+		return err
+	}
+	return nil
+}
+
+// GetAssassin returns the chain entry for the given player, or nil if they haven't joined the chain.
+func GetAssassin(db queryer, username string) (*AssassinRow, error) {
+	const getStmt = `SELECT username, target, status, tags, taggedby FROM assassins WHERE username=?`
+	rows, err := db.Query(getStmt, username)
+	if err != nil {
+		log.Printf("Db-err: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+	if rows.Next() {
+		var ar AssassinRow
+		var status int32
+		if err = rows.Scan(&ar.Username, &ar.Target, &status, &ar.Tags, &ar.TaggedBy); err != nil {
+			return nil, err
+		}
+		ar.Status = qrpb.AssassinStatus(status)
+		return &ar, nil
+	}
+	return nil, nil
+}
+
+// GetAllAssassins returns the whole chain, including players who are out.
+func GetAllAssassins(db queryer) ([]AssassinRow, error) {
+	const getStmt = `SELECT username, target, status, tags, taggedby FROM assassins`
+	rows, err := db.Query(getStmt)
+	if err != nil {
+		log.Printf("Db-err: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+	reply := make([]AssassinRow, 0)
+	for rows.Next() {
+		var ar AssassinRow
+		var status int32
+		if err = rows.Scan(&ar.Username, &ar.Target, &status, &ar.Tags, &ar.TaggedBy); err != nil {
+			return nil, err
+		}
+		ar.Status = qrpb.AssassinStatus(status)
+		reply = append(reply, ar)
+	}
+	return reply, nil
+}
+
+func putAssassin(db queryer, ar *AssassinRow) error {
+	const upsertStmt = `INSERT OR REPLACE INTO assassins VALUES(?,?,?,?,?)`
+	_, err := db.Exec(upsertStmt, ar.Username, ar.Target, int32(ar.Status), ar.Tags, ar.TaggedBy)
+	return err
+}
+
+// getHunterOf returns the alive player whose target is the given username, if any.
+func getHunterOf(db queryer, username string) (*AssassinRow, error) {
+	const getStmt = `SELECT username FROM assassins WHERE target=? AND status=?`
+	rows, err := db.Query(getStmt, username, int32(qrpb.AssassinStatus_ASSASSIN_ALIVE))
+	if err != nil {
+		return nil, err
+	}
+	var hunter string
+	found := rows.Next()
+	if found {
+		err = rows.Scan(&hunter)
+	}
+	rows.Close()
+	if err != nil || !found {
+		return nil, err
+	}
+	return GetAssassin(db, hunter)
+}
+
+// withChainTx runs f in a transaction, and serializes it with every other change to the chain.
+func (env *Env) withChainTx(f func(tx *sql.Tx) error) error {
+	env.chainMu.Lock()
+	defer env.chainMu.Unlock()
+	tx, err := env.db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := f(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// AssassinJoin adds a player to the target chain, splicing them in after a random player who is
+// still in the game. Players who already joined are left alone.
+func (env *Env) AssassinJoin(username string) error {
+	return env.withChainTx(func(tx *sql.Tx) error {
+		me, err := GetAssassin(tx, username)
+		if err != nil || me != nil {
+			return err
+		}
+		all, err := GetAllAssassins(tx)
+		if err != nil {
+			return err
+		}
+		alive := make([]AssassinRow, 0)
+		for _, a := range all {
+			if a.Status == qrpb.AssassinStatus_ASSASSIN_ALIVE {
+				alive = append(alive, a)
+			}
+		}
+
+		me = &AssassinRow{Username: username, Status: qrpb.AssassinStatus_ASSASSIN_ALIVE}
+		if len(alive) == 0 {
+			return putAssassin(tx, me)
+		}
+
+		hunter := alive[env.rnd.Intn(len(alive))]
+		me.Target = hunter.Target
+		if len(me.Target) == 0 {
+			// The hunter was alone in the chain, so the two of them target each other.
+			me.Target = hunter.Username
+		}
+		hunter.Target = username
+		if err := putAssassin(tx, &hunter); err != nil {
+			return err
+		}
+		return putAssassin(tx, me)
+	})
+}
+
+// AssassinTag records the hunter tagging their target. The hunter inherits the target's target.
+// It returns false without changing anything if scanned is not the hunter's current target.
+func (env *Env) AssassinTag(hunter, scanned string) (bool, error) {
+	tagged := false
+	err := env.withChainTx(func(tx *sql.Tx) error {
+		var err error
+		tagged, err = assassinTag(tx, hunter, scanned)
+		return err
+	})
+	return tagged, err
+}
+
+// assassinTag is AssassinTag within the caller's chain transaction.
+func assassinTag(tx *sql.Tx, hunter, scanned string) (bool, error) {
+	h, err := GetAssassin(tx, hunter)
+	if err != nil || h == nil {
+		return false, err
+	}
+	if h.Status != qrpb.AssassinStatus_ASSASSIN_ALIVE || len(h.Target) == 0 || h.Target != scanned {
+		return false, nil
+	}
+	v, err := GetAssassin(tx, scanned)
+	if err != nil {
+		return false, err
+	}
+	if v == nil {
+		return false, fmt.Errorf("target %v is not in the chain", scanned)
+	}
+
+	h.Target = v.Target
+	if h.Target == h.Username {
+		h.Target = ""
+	}
+	h.Tags++
+	v.Target = ""
+	v.Status = qrpb.AssassinStatus_ASSASSIN_TAGGED
+	v.TaggedBy = hunter
+	if err := putAssassin(tx, h); err != nil {
+		return false, err
+	}
+	return true, putAssassin(tx, v)
+}
+
+// AssassinRemove takes a player out of the chain, for example when they leave the event early.
+// Whoever was hunting them inherits their target.
+func (env *Env) AssassinRemove(username string, status qrpb.AssassinStatus) error {
+	return env.withChainTx(func(tx *sql.Tx) error {
+		return assassinRemove(tx, username, status)
+	})
+}
+
+// assassinRemove is AssassinRemove within the caller's chain transaction.
+func assassinRemove(tx *sql.Tx, username string, status qrpb.AssassinStatus) error {
+	me, err := GetAssassin(tx, username)
+	if err != nil || me == nil {
+		return err
+	}
+	if me.Status != qrpb.AssassinStatus_ASSASSIN_ALIVE {
+		return nil
+	}
+	hunter, err := getHunterOf(tx, username)
+	if err != nil {
+		return err
+	}
+	if hunter != nil {
+		hunter.Target = me.Target
+		if hunter.Target == hunter.Username {
+			hunter.Target = ""
+		}
+		if err := putAssassin(tx, hunter); err != nil {
+			return err
+		}
+	}
+	me.Target = ""
+	me.Status = status
+	return putAssassin(tx, me)
+}
+
+// AssassinReset clears the target chain so a new game starts from scratch. Players join the new
+// chain the next time they load the game.
+func (env *Env) AssassinReset() error {
+	return env.withChainTx(func(tx *sql.Tx) error {
+		_, err := tx.Exec(`DELETE FROM assassins`)
+		return err
+	})
+}
+
+// AssassinTimeUp returns true if the organizer set an end time and it has passed.
+func AssassinTimeUp(ac *qrpb.AssassinConfig, now time.Time) bool {
+	if len(ac.GetEndTime()) == 0 {
+		return false
+	}
+	end, err := time.Parse(time.RFC3339, ac.GetEndTime())
+	if err != nil {
+		log.Printf("could not parse assassin end_time: %v", err)
+		return false
+	}
+	return now.After(end)
+}
+
+// StepAssassin returns the GameState resulting from the player scanning someone in the assassin game mode.
+func (env *Env) StepAssassin(u *StateRow, answer string) (StepResponse, error) {
+	scanned, rejection, err := env.ResolveScan(answer, time.Now())
+	if err != nil {
+		return StepResponse{}, err
+	}
+	gc, err := env.cgo.GetGameConfig()
+	if err != nil {
+		return StepResponse{}, err
+	}
+
+	old := u.State
+	result := NewStepResponse()
+	result.actionString = "Lost a Life!"
+	result.actionResult = *qrpb.ActionLog_RESULT_LOST_LIFE.Enum()
+	result.scannedClue = scanned.GetUsername()
+	result.newState = proto.Clone(old).(*qrpb.GameState)
+
+	if len(rejection) > 0 {
+		stepRejected(&result, rejection)
+		return result, nil
+	}
+
+	if err := env.AssassinJoin(u.Username); err != nil {
+		return StepResponse{}, err
+	}
+	me, err := GetAssassin(env.db, u.Username)
+	if err != nil {
+		return StepResponse{}, err
+	}
+
+	if me.Status != qrpb.AssassinStatus_ASSASSIN_ALIVE {
+		result.actionString = "You Are Out!"
+		result.actionResult = *qrpb.ActionLog_RESULT_ALREADY_DEAD.Enum()
+		return result, nil
+	}
+	if len(me.Target) == 0 {
+		result.actionString = "Last One Standing!"
+		result.actionResult = *qrpb.ActionLog_RESULT_ALREADY_VICTORIOUS.Enum()
+		return result, nil
+	}
+	if AssassinTimeUp(gc.GetAssassin(), time.Now()) {
+		result.actionString = "Time's Up!"
+		result.actionResult = *qrpb.ActionLog_RESULT_IGNORED.Enum()
+		return result, nil
+	}
+
+	// The chain only changes when the move is saved, in the same transaction.
+	if me.Target == result.scannedClue {
+		result.actionString = "Tagged!"
+		result.actionResult = *qrpb.ActionLog_RESULT_TAGGED_TARGET.Enum()
+		hunter, victim := u.Username, result.scannedClue
+		result.applyTx = func(tx *sql.Tx) error {
+			tagged, err := assassinTag(tx, hunter, victim)
+			if err != nil {
+				return err
+			}
+			if !tagged {
+				return fmt.Errorf("%v is no longer the target of %v", victim, hunter)
+			}
+			lr := NewLogRow()
+			lr.Username = victim
+			lr.Updated = time.Now().UnixNano() / 1000
+			lr.GameLog = &qrpb.ActionLog{
+				TimestampUsec: proto.Int64(lr.Updated),
+				ClueShortName: proto.String(hunter),
+				Result:        qrpb.ActionLog_RESULT_WAS_TAGGED.Enum(),
+				Type:          qrpb.ActionLog_ACTION_TAGGED.Enum(),
+			}
+			return AddActionLog(tx, &lr)
+		}
+		return result, nil
+	}
+
+	h, err := env.HandicapFor(u.Username)
+	if err != nil {
+		return StepResponse{}, err
+	}
+	if !gc.GetAssassin().GetWrongTagCostsLife() || h.GetNoPenalty() {
+		result.actionString = "Not Quite!"
+		result.actionResult = *qrpb.ActionLog_RESULT_WRONG_ANSWER.Enum()
+		return result, nil
+	}
+
+	result.newState.Life = proto.Int64(old.GetLife() - 1)
+	if result.newState.GetLife() <= 0 {
+		result.actionString = "Dead!"
+		result.actionResult = *qrpb.ActionLog_RESULT_ALREADY_DEAD.Enum()
+		username := u.Username
+		result.applyTx = func(tx *sql.Tx) error {
+			return assassinRemove(tx, username, qrpb.AssassinStatus_ASSASSIN_DROPPED)
+		}
+	}
+	return result, nil
+}
+
+// RenderAssassinClue returns the HTML telling the player who to find next.
+func (env *Env) RenderAssassinClue(u *StateRow) (string, error) {
+	gc, err := env.cgo.GetGameConfig()
+	if err != nil {
+		return "", err
+	}
+	qrm, err := env.cgo.GetQRMappings()
+	if err != nil {
+		return "", err
+	}
+	me, err := GetAssassin(env.db, u.Username)
+	if err != nil {
+		return "", err
+	}
+	if me == nil {
+		me = &AssassinRow{}
+	}
+
+	name := func(username string) string {
+		if m := qrm.LookupByUsername(username); m != nil && len(m.GetDisplayName()) > 0 {
+			return m.GetDisplayName()
+		}
+		return username
+	}
+	rd := struct {
+		Me       *AssassinRow
+		Target   string
+		TaggedBy string
+		TimeUp   bool
+	}{
+		Me:       me,
+		Target:   name(me.Target),
+		TaggedBy: name(me.TaggedBy),
+		TimeUp:   AssassinTimeUp(gc.GetAssassin(), time.Now()),
+	}
+
+	var buf bytes.Buffer
+	err = env.tem.ExecuteTemplate(&buf, "assassinclue.html", rd)
+	return buf.String(), err
+}
+
+// assassinStatusText describes a player's place in the chain for the admin pages.
+func assassinStatusText(ar AssassinRow) string {
+	switch ar.Status {
+	case qrpb.AssassinStatus_ASSASSIN_ALIVE:
+		if len(ar.Target) == 0 {
+			return "Last one standing"
+		}
+		return "Alive"
+	case qrpb.AssassinStatus_ASSASSIN_TAGGED:
+		return fmt.Sprintf("Tagged by %v", ar.TaggedBy)
+	case qrpb.AssassinStatus_ASSASSIN_DROPPED:
+		return "Out"
+	}
+	return ""
+}
+
+func (env *Env) adminAssassinDrop(w http.ResponseWriter, r *http.Request) {
+	log.Println("Req: ", r.URL)
+	if common.Should500(r.ParseForm(), w, "error parsing data for drop player") {
+		return
+	}
+	username := r.FormValue("username")
+	if len(username) == 0 {
+		common.Should500(fmt.Errorf("empty username received"), w, "empty username received")
+		return
+	}
+	if common.Should500(env.AssassinRemove(username, qrpb.AssassinStatus_ASSASSIN_DROPPED), w, "could not drop the player") {
+		return
+	}
+	fmt.Fprint(w, "ok")
+}
+
+func (env *Env) adminAssassinReset(w http.ResponseWriter, r *http.Request) {
+	log.Println("Req: ", r.URL)
+	if common.Should500(env.AssassinReset(), w, "could not reset the chain") {
+		return
+	}
+	fmt.Fprint(w, "ok")
+}
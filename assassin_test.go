@@ -0,0 +1,285 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sushovande/qr-mixer-game/qrpb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// checkChain verifies that the alive players form a single cycle.
+func checkChain(t *testing.T, env *Env, wantAlive int) {
+	t.Helper()
+	all, err := GetAllAssassins(env.db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targets := make(map[string]string)
+	for _, a := range all {
+		if a.Status == qrpb.AssassinStatus_ASSASSIN_ALIVE {
+			targets[a.Username] = a.Target
+		}
+	}
+	if len(targets) != wantAlive {
+		t.Fatalf("expected %v players alive. got: %v", wantAlive, targets)
+	}
+	if wantAlive == 1 {
+		for _, tg := range targets {
+			if tg != "" {
+				t.Errorf("expected the last player standing to have no target. got: %v", tg)
+			}
+		}
+		return
+	}
+	var start string
+	for u := range targets {
+		start = u
+		break
+	}
+	seen := 0
+	for u := start; ; {
+		seen++
+		u = targets[u]
+		if _, ok := targets[u]; !ok {
+			t.Fatalf("chain leads to a player who is out: %v in %v", u, targets)
+		}
+		if u == start {
+			break
+		}
+		if seen > wantAlive {
+			t.Fatalf("chain does not loop back: %v", targets)
+		}
+	}
+	if seen != wantAlive {
+		t.Errorf("expected one cycle through all %v players. got a cycle of %v: %v", wantAlive, seen, targets)
+	}
+}
+
+func TestAssassinChain(t *testing.T) {
+	env, err := createEnv(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer env.db.Close()
+
+	for i := 1; i <= 5; i++ {
+		if err := env.AssassinJoin(fmt.Sprintf("username-%v", i)); err != nil {
+			t.Fatal(err)
+		}
+		checkChain(t, env, i)
+	}
+	// Joining twice does nothing.
+	env.AssassinJoin("username-1")
+	checkChain(t, env, 5)
+
+	me, _ := GetAssassin(env.db, "username-1")
+	tagged, err := env.AssassinTag("username-1", me.Target)
+	if err != nil || !tagged {
+		t.Fatalf("expected to tag the target. got: %v, %v", tagged, err)
+	}
+	checkChain(t, env, 4)
+
+	tagged, _ = env.AssassinTag("username-1", "username-1")
+	if tagged {
+		t.Errorf("expected scanning someone other than the target not to tag")
+	}
+
+	if err := env.AssassinRemove("username-1", qrpb.AssassinStatus_ASSASSIN_DROPPED); err != nil {
+		t.Fatal(err)
+	}
+	checkChain(t, env, 3)
+
+	for n := 3; n > 1; n-- {
+		all, _ := GetAllAssassins(env.db)
+		for _, a := range all {
+			if a.Status == qrpb.AssassinStatus_ASSASSIN_ALIVE {
+				env.AssassinTag(a.Username, a.Target)
+				break
+			}
+		}
+		checkChain(t, env, n-1)
+	}
+}
+
+func TestAssassinGame(t *testing.T) {
+	env, err := createEnv(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer env.db.Close()
+	setupSynthetic(env.cgo, 4)
+	env.cgo.SetGameConfig(&qrpb.GameConfig{GameMode: proto.String(GAME_MODE_ASSASSIN)})
+
+	cookies := make([]http.Cookie, 0)
+	for i := 1; i <= 3; i++ {
+		ck := http.Cookie{Name: "sid", Value: fmt.Sprintf("cookie-%v", i), Expires: time.Now().Add(24 * 30 * time.Hour)}
+		callController("POST", "/submitsurvey", fmt.Sprintf("qr=qrcode-%v&dqans1=true&dqans2=false", i), &ck, env.submitSurvey)
+		cookies = append(cookies, ck)
+	}
+	checkChain(t, env, 3)
+
+	f := callController("GET", "/game", "", &cookies[0], env.gameHandler)
+	if !strings.Contains(f.resptext, "Your target is") {
+		t.Errorf("expected the game page to show the target. got: %v", f.resptext)
+	}
+
+	me, _ := GetAssassin(env.db, "username-1")
+	wrong := "username-2"
+	if me.Target == wrong {
+		wrong = "username-3"
+	}
+
+	var mr MoveResponse
+	f = callController("POST", "/makemove", "answer="+strings.Replace(wrong, "username", "qrcode", 1), &cookies[0], env.makeMove)
+	json.Unmarshal([]byte(f.resptext), &mr)
+	if mr.GameArtifacts["action"] != "Not Quite!" || mr.State.GetLife() != STARTING_LIFE {
+		t.Errorf("expected a wrong scan to cost nothing by default. got: %v", f.resptext)
+	}
+
+	mr = MoveResponse{}
+	f = callController("POST", "/makemove", "answer="+strings.Replace(me.Target, "username", "qrcode", 1), &cookies[0], env.makeMove)
+	json.Unmarshal([]byte(f.resptext), &mr)
+	if mr.GameArtifacts["action"] != "Tagged!" {
+		t.Errorf("expected to tag the target. got: %v", f.resptext)
+	}
+	checkChain(t, env, 2)
+
+	victim, _ := GetAssassin(env.db, me.Target)
+	if victim.Status != qrpb.AssassinStatus_ASSASSIN_TAGGED || victim.TaggedBy != "username-1" {
+		t.Errorf("expected the target to be marked as tagged. got: %+v", victim)
+	}
+	logs, _ := GetAllLogsForUser(env.db, me.Target)
+	if len(logs) != 1 || logs[0].GameLog.GetResult() != qrpb.ActionLog_RESULT_WAS_TAGGED {
+		t.Errorf("expected the target's log to record the tag. got: %v", logs)
+	}
+
+	f = callController("POST", "/dropPlayer", "username="+wrong, nil, env.adminAssassinDrop)
+	if f.resptext != "ok" {
+		t.Errorf("expected the drop to succeed. got: %v", f.resptext)
+	}
+	checkChain(t, env, 1)
+
+	mr = MoveResponse{}
+	f = callController("POST", "/makemove", "answer=qrcode-4", &cookies[0], env.makeMove)
+	json.Unmarshal([]byte(f.resptext), &mr)
+	if mr.GameArtifacts["action"] != "Last One Standing!" {
+		t.Errorf("expected the last player to have won. got: %v", f.resptext)
+	}
+
+	f = callController("GET", "/allUsers", "", nil, env.adminAllUsers)
+	if !strings.Contains(f.resptext, "Tags") || !strings.Contains(f.resptext, "Last one standing") {
+		t.Errorf("expected the leaderboard to show the chain. got: %v", f.resptext)
+	}
+
+	f = callController("POST", "/resetChain", "", nil, env.adminAssassinReset)
+	if f.resptext != "ok" {
+		t.Errorf("expected the reset to succeed. got: %v", f.resptext)
+	}
+	if all, _ := GetAllAssassins(env.db); len(all) != 0 {
+		t.Errorf("expected the reset to clear the chain. got: %v", all)
+	}
+	for i := range cookies {
+		callController("GET", "/game", "", &cookies[i], env.gameHandler)
+	}
+	checkChain(t, env, 3)
+}
+
+func TestAssassinWrongTagCostsLife(t *testing.T) {
+	env, err := createEnv(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer env.db.Close()
+	setupSynthetic(env.cgo, 4)
+	env.cgo.SetGameConfig(&qrpb.GameConfig{
+		GameMode: proto.String(GAME_MODE_ASSASSIN),
+		Assassin: &qrpb.AssassinConfig{WrongTagCostsLife: proto.Bool(true)},
+	})
+
+	cookies := make([]http.Cookie, 0)
+	for i := 1; i <= 3; i++ {
+		ck := http.Cookie{Name: "sid", Value: fmt.Sprintf("cookie-%v", i), Expires: time.Now().Add(24 * 30 * time.Hour)}
+		callController("POST", "/submitsurvey", fmt.Sprintf("qr=qrcode-%v&dqans1=true&dqans2=false", i), &ck, env.submitSurvey)
+		cookies = append(cookies, ck)
+	}
+
+	me, _ := GetAssassin(env.db, "username-1")
+	wrong := "qrcode-2"
+	if me.Target == "username-2" {
+		wrong = "qrcode-3"
+	}
+	var mr MoveResponse
+	f := callController("POST", "/makemove", "answer="+wrong, &cookies[0], env.makeMove)
+	json.Unmarshal([]byte(f.resptext), &mr)
+	if mr.GameArtifacts["action"] != "Lost a Life!" || mr.State.GetLife() != STARTING_LIFE-1 {
+		t.Errorf("expected a wrong scan to cost a life. got: %v", f.resptext)
+	}
+	checkChain(t, env, 3)
+
+	for i := int64(1); i < STARTING_LIFE; i++ {
+		mr = MoveResponse{}
+		f = callController("POST", "/makemove", "answer="+wrong, &cookies[0], env.makeMove)
+		json.Unmarshal([]byte(f.resptext), &mr)
+	}
+	if mr.GameArtifacts["action"] != "Dead!" {
+		t.Errorf("expected the last life to take the player out. got: %v", f.resptext)
+	}
+	if me, _ = GetAssassin(env.db, "username-1"); me.Status != qrpb.AssassinStatus_ASSASSIN_DROPPED {
+		t.Errorf("expected the player to leave the chain. got: %+v", me)
+	}
+	checkChain(t, env, 2)
+}
+
+func TestAssassinNoPenalty(t *testing.T) {
+	env, err := createEnv(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer env.db.Close()
+	setupSynthetic(env.cgo, 4)
+	env.cgo.SetGameConfig(&qrpb.GameConfig{
+		GameMode: proto.String(GAME_MODE_ASSASSIN),
+		Assassin: &qrpb.AssassinConfig{WrongTagCostsLife: proto.Bool(true)},
+	})
+	qrm, _ := env.cgo.GetQRMappings()
+	qrm.LookupByUsername("username-1").Handicap = &qrpb.PlayerHandicap{NoPenalty: proto.Bool(true)}
+
+	cookies := make([]http.Cookie, 0)
+	for i := 1; i <= 3; i++ {
+		ck := http.Cookie{Name: "sid", Value: fmt.Sprintf("cookie-%v", i), Expires: time.Now().Add(24 * 30 * time.Hour)}
+		callController("POST", "/submitsurvey", fmt.Sprintf("qr=qrcode-%v&dqans1=true&dqans2=false", i), &ck, env.submitSurvey)
+		cookies = append(cookies, ck)
+	}
+
+	me, _ := GetAssassin(env.db, "username-1")
+	wrong := "qrcode-2"
+	if me.Target == "username-2" {
+		wrong = "qrcode-3"
+	}
+	var mr MoveResponse
+	f := callController("POST", "/makemove", "answer="+wrong, &cookies[0], env.makeMove)
+	json.Unmarshal([]byte(f.resptext), &mr)
+	if mr.GameArtifacts["action"] != "Not Quite!" || mr.State.GetLife() != STARTING_LIFE {
+		t.Errorf("expected no penalty for a wrong scan. got: %v", f.resptext)
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sushovande/qr-mixer-game/qrpb"
+
+	proto "google.golang.org/protobuf/proto"
+)
+
+// CLUE_REFRESH_SEC is how often an open game page asks for its clue again, while hints can be
+// revealed over time.
+const CLUE_REFRESH_SEC = 30
+
+// StuckStats is how long a player has been on their current level, and how many wrong answers
+// they have given there.
+type StuckStats struct {
+	// Since is when the player reached the level. It is zero if the logs don't say.
+	Since      time.Time
+	WrongScans int64
+}
+
+// MarkLevelReached records now as when the player reached their current level, unless that is
+// already recorded. It returns true if gs was changed.
+func MarkLevelReached(gs *qrpb.GameState, now time.Time) bool {
+	if gs.ReachedUsec != nil && gs.GetReachedLevel() == gs.GetUserLevel() {
+		return false
+	}
+	gs.ReachedLevel = proto.Int64(gs.GetUserLevel())
+	gs.ReachedUsec = proto.Int64(now.UnixMicro())
+	return true
+}
+
+// StuckStatsFor works out how stuck the player is on their current level from their state.
+func StuckStatsFor(gs *qrpb.GameState) StuckStats {
+	st := StuckStats{WrongScans: gs.GetWrongAttempts()}
+	if gs.ReachedUsec != nil && gs.GetReachedLevel() == gs.GetUserLevel() {
+		st.Since = time.UnixMicro(gs.GetReachedUsec())
+	}
+	return st
+}
+
+// HintsDue returns how many hints a player with the given stats should see.
+func HintsDue(ah *qrpb.AutoHintConfig, st StuckStats, now time.Time, available int) int64 {
+	var due int64
+	if ah.GetAfterSeconds() > 0 && !st.Since.IsZero() {
+		due = int64(now.Sub(st.Since).Seconds()) / ah.GetAfterSeconds()
+	}
+	if ah.GetAfterWrongScans() > 0 {
+		if n := st.WrongScans / ah.GetAfterWrongScans(); n > due {
+			due = n
+		}
+	}
+	if due > int64(available) {
+		due = int64(available)
+	}
+	return due
+}
+
+// RevealedHints returns how many hints of the current question the player has been shown.
+func RevealedHints(gs *qrpb.GameState) int64 {
+	if gs.GetHintLevel() != gs.GetUserLevel() {
+		return 0
+	}
+	return gs.GetHintsRevealed()
+}
+
+// MaybeRevealHint reveals the next hints of the player's current question if they have been stuck
+// for long enough, and records each one in the logs. It returns true if u.State was changed. The
+// hints are saved here, but the caller still has to save the rest of u.State, see prepareAndSave.
+func (env *Env) MaybeRevealHint(u *StateRow, now time.Time) (bool, error) {
+	gc, err := env.cgo.GetGameConfig()
+	if err != nil {
+		return false, err
+	}
+	ah := gc.GetAutoHint()
+	if ah.GetAfterSeconds() <= 0 && ah.GetAfterWrongScans() <= 0 {
+		return false, nil
+	}
+	marked := MarkLevelReached(u.State, now)
+	sqs, err := env.cgo.GetGameQSet()
+	if err != nil {
+		return marked, err
+	}
+	sq := GetQuestionForPlayer(sqs, u.State)
+	unlock, locked := QuestionLockedUntil(sq, now)
+	if locked {
+		return marked, nil
+	}
+	revealed := RevealedHints(u.State)
+	if int64(len(sq.GetHints())) <= revealed {
+		return marked, nil
+	}
+
+	st := StuckStatsFor(u.State)
+	// Time spent waiting for the question to unlock does not count as being stuck.
+	if st.Since.Before(unlock) {
+		st.Since = unlock
+	}
+	due := HintsDue(ah, st, now, len(sq.GetHints()))
+	if due <= revealed {
+		return marked, nil
+	}
+
+	// Two requests for the same player can both find a hint due, so the hints are saved on the
+	// latest state, and only the request whose save goes through logs them.
+	level := u.State.GetUserLevel()
+	var old *qrpb.GameState
+	shown := false
+	saved, err := PatchUserState(env.db, u.Username, func(gs *qrpb.GameState) bool {
+		if gs.GetUserLevel() != level {
+			return false
+		}
+		if RevealedHints(gs) >= due {
+			shown = true
+			return false
+		}
+		old = proto.Clone(gs).(*qrpb.GameState)
+		MarkLevelReached(gs, now)
+		gs.HintLevel = proto.Int64(level)
+		gs.HintsRevealed = proto.Int64(due)
+		return true
+	})
+	if err != nil {
+		return marked, err
+	}
+	if !saved && !shown {
+		return marked, nil
+	}
+	u.State.HintLevel = proto.Int64(level)
+	u.State.HintsRevealed = proto.Int64(due)
+	if !saved {
+		return true, nil
+	}
+
+	for n := RevealedHints(old) + 1; n <= due; n++ {
+		lr := NewLogRow()
+		lr.Username = u.Username
+		lr.Updated = now.UnixNano() / 1000
+		lr.GameLog = &qrpb.ActionLog{
+			OldState:      old,
+			TimestampUsec: proto.Int64(lr.Updated),
+			ClueShortName: proto.String(fmt.Sprintf("hint-%v", n)),
+			Result:        qrpb.ActionLog_RESULT_HINT_REVEALED.Enum(),
+			Type:          qrpb.ActionLog_ACTION_HINT.Enum(),
+		}
+		if err := AddActionLog(env.db, &lr); err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+// ClueRefreshSec returns how often the game page should refresh its clue, or 0 if hints are never
+// revealed over time.
+func ClueRefreshSec(gc *qrpb.GameConfig) int64 {
+	if gc.GetAutoHint().GetAfterSeconds() <= 0 {
+		return 0
+	}
+	return CLUE_REFRESH_SEC
+}
+
+// HintsHTML shows the hints revealed so far under an organizer nudge banner. It returns an empty
+// string if no hints have been revealed on the current question.
+func HintsHTML(sq *qrpb.GameQuestion, gs *qrpb.GameState) string {
+	n := RevealedHints(gs)
+	if n > int64(len(sq.GetHints())) {
+		n = int64(len(sq.GetHints()))
+	}
+	if n <= 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString(`<div class="nudge"><p class="nudge-banner">📣 Organizer nudge</p><ul>`)
+	for _, h := range sq.GetHints()[:n] {
+		fmt.Fprintf(&sb, "<li>%v</li>", h)
+	}
+	sb.WriteString("</ul></div>")
+	return sb.String()
+}
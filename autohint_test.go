@@ -0,0 +1,195 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sushovande/qr-mixer-game/qrpb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestHintsDue(t *testing.T) {
+	now := time.Now()
+	ah := &qrpb.AutoHintConfig{AfterSeconds: proto.Int64(300), AfterWrongScans: proto.Int64(3)}
+	st := StuckStats{Since: now.Add(-11 * time.Minute), WrongScans: 1}
+	if n := HintsDue(ah, st, now, 5); n != 2 {
+		t.Errorf("expected two hints after 11 minutes. got: %v", n)
+	}
+	st = StuckStats{Since: now.Add(-time.Minute), WrongScans: 7}
+	if n := HintsDue(ah, st, now, 5); n != 2 {
+		t.Errorf("expected two hints after 7 wrong scans. got: %v", n)
+	}
+	if n := HintsDue(ah, st, now, 1); n != 1 {
+		t.Errorf("expected no more hints than the question has. got: %v", n)
+	}
+	if n := HintsDue(&qrpb.AutoHintConfig{AfterSeconds: proto.Int64(1)}, StuckStats{}, now, 5); n != 0 {
+		t.Errorf("expected no time-based hints without a start time. got: %v", n)
+	}
+}
+
+func TestAutoHint(t *testing.T) {
+	env, err := createEnv(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer env.db.Close()
+	setupSynthetic(env.cgo, 10)
+	sqs, _ := env.cgo.GetGameQSet()
+	sqs.GameQuestions[1].Hints = []string{"Look near the door.", "They wear a hat."}
+	env.cgo.SetGameQSet(sqs)
+	env.cgo.SetGameConfig(&qrpb.GameConfig{AutoHint: &qrpb.AutoHintConfig{
+		AfterSeconds:    proto.Int64(600),
+		AfterWrongScans: proto.Int64(2),
+	}})
+
+	ck := http.Cookie{Name: "sid", Value: "cookie-1", Expires: time.Now().Add(24 * 30 * time.Hour)}
+	callController("POST", "/submitsurvey", "qr=qrcode-1&dqans1=true&dqans2=false", &ck, env.submitSurvey)
+
+	var mr MoveResponse
+	callController("POST", "/makemove", "answer=qrcode-1", &ck, env.makeMove)
+	f := callController("POST", "/makemove", "answer=qrcode-9", &ck, env.makeMove)
+	json.Unmarshal([]byte(f.resptext), &mr)
+	if strings.Contains(mr.PortHTML, "Organizer nudge") {
+		t.Errorf("expected no hint after one wrong scan. got: %v", mr.PortHTML)
+	}
+	mr = MoveResponse{}
+	f = callController("POST", "/makemove", "answer=qrcode-9", &ck, env.makeMove)
+	json.Unmarshal([]byte(f.resptext), &mr)
+	if !strings.Contains(mr.PortHTML, "Organizer nudge") || !strings.Contains(mr.PortHTML, "Look near the door.") || strings.Contains(mr.PortHTML, "hat") {
+		t.Errorf("expected the first hint after two wrong scans. got: %v", mr.PortHTML)
+	}
+
+	// Twenty minutes in, the second hint is due. Two page views at once both show it, but it is
+	// only logged once.
+	u1, _ := GetUserStateByUsername(env.db, "username-1")
+	again, _ := GetUserStateByUsername(env.db, "username-1")
+	later := time.Now().Add(21 * time.Minute)
+	for _, u := range []*StateRow{u1, again} {
+		changed, err := env.MaybeRevealHint(u, later)
+		if err != nil || !changed || RevealedHints(u.State) != 2 {
+			t.Errorf("expected the second hint after twenty minutes. got: %v, %v, %v", changed, err, u.State)
+		}
+	}
+	UpdateUserDetails(env.db, u1)
+
+	mr = MoveResponse{}
+	f = callController("POST", "/makemove", "answer=qrcode-3", &ck, env.makeMove)
+	json.Unmarshal([]byte(f.resptext), &mr)
+	if mr.GameArtifacts["action"] != "Correct!" || strings.Contains(mr.PortHTML, "Organizer nudge") {
+		t.Errorf("expected the next question without hints. got: %v", f.resptext)
+	}
+
+	logs, _ := GetAllLogsForUser(env.db, "username-1")
+	hints := 0
+	var solve *qrpb.ActionLog
+	for _, lr := range logs {
+		if lr.GameLog.GetType() == qrpb.ActionLog_ACTION_HINT {
+			hints++
+		}
+		if lr.GameLog.GetResult() == qrpb.ActionLog_RESULT_PROGRESS && lr.GameLog.GetOldState().GetUserLevel() == 2 {
+			solve = lr.GameLog
+		}
+	}
+	if hints != 2 {
+		t.Errorf("expected both hints to be logged. got: %v", hints)
+	}
+	// The old state of the solve shows the player had been nudged.
+	if RevealedHints(solve.GetOldState()) != 2 {
+		t.Errorf("expected the solve to be recorded as nudged. got: %v", solve)
+	}
+}
+
+func TestClueRefreshRevealsHint(t *testing.T) {
+	env, err := createEnv(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer env.db.Close()
+	setupSynthetic(env.cgo, 10)
+	sqs, _ := env.cgo.GetGameQSet()
+	sqs.GameQuestions[0].Hints = []string{"Look near the door."}
+	env.cgo.SetGameQSet(sqs)
+	gc := &qrpb.GameConfig{AutoHint: &qrpb.AutoHintConfig{AfterSeconds: proto.Int64(600)}}
+	env.cgo.SetGameConfig(gc)
+	if ClueRefreshSec(gc) != CLUE_REFRESH_SEC || ClueRefreshSec(&qrpb.GameConfig{}) != 0 {
+		t.Errorf("expected the game page to refresh only while hints come with time")
+	}
+
+	ck := http.Cookie{Name: "sid", Value: "cookie-1", Expires: time.Now().Add(24 * 30 * time.Hour)}
+	callController("POST", "/submitsurvey", "qr=qrcode-1&dqans1=true&dqans2=false", &ck, env.submitSurvey)
+	u1, _ := GetUserStateByUsername(env.db, "username-1")
+	if StuckStatsFor(u1.State).Since.IsZero() {
+		t.Fatalf("expected registering to record when the player reached level 1. got: %v", u1.State)
+	}
+
+	// Eleven minutes later, the open page picks up the hint without a move.
+	u1.State.ReachedUsec = proto.Int64(time.Now().Add(-11 * time.Minute).UnixMicro())
+	UpdateUserDetails(env.db, u1)
+	f := callController("GET", "/clue", "", &ck, env.refreshClue)
+	var mr MoveResponse
+	if err := json.Unmarshal([]byte(f.resptext), &mr); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(mr.PortHTML, "Look near the door.") {
+		t.Errorf("expected the refreshed clue to have the hint. got: %v", f.resptext)
+	}
+	if _, ok := mr.GameArtifacts["action"]; ok {
+		t.Errorf("expected no action on a refresh. got: %v", mr.GameArtifacts)
+	}
+}
+
+func TestHintKeepsConcurrentMove(t *testing.T) {
+	env, err := createEnv(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer env.db.Close()
+	setupSynthetic(env.cgo, 10)
+	sqs, _ := env.cgo.GetGameQSet()
+	sqs.GameQuestions[0].Hints = []string{"Look near the door."}
+	env.cgo.SetGameQSet(sqs)
+	env.cgo.SetGameConfig(&qrpb.GameConfig{AutoHint: &qrpb.AutoHintConfig{AfterSeconds: proto.Int64(600)}})
+
+	ck := http.Cookie{Name: "sid", Value: "cookie-1"}
+	callController("POST", "/submitsurvey", "qr=qrcode-1&dqans1=true&dqans2=false", &ck, env.submitSurvey)
+	u, _ := GetUserStateByUsername(env.db, "username-1")
+	u.State.ReachedLevel = proto.Int64(1)
+	u.State.ReachedUsec = proto.Int64(time.Now().Add(-11*time.Minute).UnixNano() / 1000)
+	UpdateUserDetails(env.db, u)
+
+	// A clue refresh reads the player, then a wrong scan is saved before the hint is revealed.
+	stale, _ := GetUserStateByUsername(env.db, "username-1")
+	moved, _ := GetUserStateByUsername(env.db, "username-1")
+	moved.State.Life = proto.Int64(moved.State.GetLife() - 1)
+	moved.State.WrongAttempts = proto.Int64(1)
+	UpdateUserDetails(env.db, moved)
+
+	if err := env.prepareAndSave(huntMode{}, stale); err != nil {
+		t.Fatal(err)
+	}
+	saved, _ := GetUserStateByUsername(env.db, "username-1")
+	if saved.State.GetLife() != moved.State.GetLife() || saved.State.GetWrongAttempts() != 1 || RevealedHints(saved.State) != 1 {
+		t.Errorf("expected the hint to be saved without undoing the wrong scan. got: %v", saved.State)
+	}
+	if !proto.Equal(stale.State, saved.State) {
+		t.Errorf("expected the refreshed player to have the saved state. got: %v, want %v", stale.State, saved.State)
+	}
+}
@@ -0,0 +1,359 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/sushovande/qr-mixer-game/qrpb"
+
+	proto "google.golang.org/protobuf/proto"
+)
+
+const GAME_MODE_BINGO = "bingo"
+const DEFAULT_BINGO_CARD_SIZE int64 = 3
+
+// bingoMode gives every player a card of traits to fill by scanning matching people.
+type bingoMode struct{}
+
+func init() {
+	RegisterGameMode(GAME_MODE_BINGO, bingoMode{})
+}
+
+func (bingoMode) InitialState(env *Env, username string) (*qrpb.GameState, error) {
+	gs, err := env.NewGameState()
+	if err != nil {
+		return nil, err
+	}
+	cells, err := env.NewBingoCard(username)
+	if err != nil {
+		return nil, err
+	}
+	gs.BingoCells = cells
+	return gs, nil
+}
+
+func (bingoMode) Step(env *Env, u *StateRow, answer string) (StepResponse, error) {
+	return env.StepBingo(u, answer)
+}
+
+func (bingoMode) Clue(env *Env, u *StateRow) (ClueData, error) {
+	clue, err := env.RenderBingoCard(u.State)
+	return ClueData{HTML: clue}, err
+}
+
+func (bingoMode) RanksAhead(a, b *DisplayUser) bool {
+	if a.BingoWon != b.BingoWon {
+		return a.BingoWon
+	}
+	return a.BingoLines > b.BingoLines
+}
+
+// Prepare gives a card to players who registered before the game switched to bingo.
+func (bingoMode) Prepare(env *Env, u *StateRow) (bool, error) {
+	if len(u.State.GetBingoCells()) > 0 {
+		return false, nil
+	}
+	cells, err := env.NewBingoCard(u.Username)
+	if err != nil {
+		return false, err
+	}
+	u.State.BingoCells = cells
+	return true, nil
+}
+
+// StepBingo returns the GameState resulting from the player scanning someone in the bingo game mode.
+func (env *Env) StepBingo(u *StateRow, answer string) (StepResponse, error) {
+	gc, err := env.cgo.GetGameConfig()
+	if err != nil {
+		return StepResponse{}, err
+	}
+
+	old := u.State
+	result := NewStepResponse()
+	result.actionResult = *qrpb.ActionLog_RESULT_IGNORED.Enum()
+	scanned, rejection, err := env.ResolveScan(answer, time.Now())
+	if err != nil {
+		return StepResponse{}, err
+	}
+	result.scannedClue = scanned.GetUsername()
+	result.newState = proto.Clone(old).(*qrpb.GameState)
+
+	if len(rejection) > 0 {
+		stepRejected(&result, rejection)
+		return result, nil
+	}
+
+	if old.GetBingoWon() {
+		result.actionString = "Already Victorious!"
+		result.actionResult = *qrpb.ActionLog_RESULT_ALREADY_VICTORIOUS.Enum()
+		return result, nil
+	}
+
+	if len(result.newState.BingoCells) == 0 {
+		cells, err := env.NewBingoCard(u.Username)
+		if err != nil {
+			return StepResponse{}, err
+		}
+		result.newState.BingoCells = cells
+	}
+
+	if scanned == nil {
+		result.actionString = "Unknown Badge!"
+		return result, nil
+	}
+	if scanned.GetUsername() == u.Username {
+		result.actionString = "That's You!"
+		return result, nil
+	}
+	for _, c := range result.newState.BingoCells {
+		if c.GetFilledBy() == scanned.GetUsername() {
+			result.actionString = "Already On Your Card!"
+			return result, nil
+		}
+	}
+
+	gu, err := GetUserInfoByUsername(env.db, scanned.GetUsername())
+	if err != nil {
+		return StepResponse{}, err
+	}
+
+	var filled *qrpb.BingoCell
+	for _, c := range result.newState.BingoCells {
+		if len(c.GetFilledBy()) == 0 && BingoTraitMatches(c.Trait, scanned, gu) {
+			filled = c
+			break
+		}
+	}
+	if filled == nil {
+		result.actionString = "No Match!"
+		return result, nil
+	}
+
+	filled.FilledBy = proto.String(scanned.GetUsername())
+	result.newState.BingoLines = proto.Int64(CountBingoLines(result.newState.BingoCells))
+	result.actionString = "Square Filled!"
+	result.actionResult = *qrpb.ActionLog_RESULT_BINGO_CELL.Enum()
+
+	won := result.newState.GetBingoLines() > 0
+	if gc.GetBingo().GetBlackoutToWin() {
+		won = CountFilledBingoCells(result.newState.BingoCells) == len(result.newState.BingoCells)
+	}
+	if won {
+		result.newState.BingoWon = proto.Bool(true)
+		result.actionString = "Bingo!"
+		result.actionResult = *qrpb.ActionLog_RESULT_PROGRESS.Enum()
+	}
+	return result, nil
+}
+
+// NewBingoCard draws a fresh bingo card for the given player.
+func (env *Env) NewBingoCard(username string) ([]*qrpb.BingoCell, error) {
+	gc, err := env.cgo.GetGameConfig()
+	if err != nil {
+		return nil, err
+	}
+	pool := gc.GetBingo().GetTraits()
+	if len(pool) == 0 {
+		ss, err := env.cgo.GetSurveySet()
+		if err != nil {
+			return nil, err
+		}
+		pool = DefaultBingoTraits(ss)
+	}
+	return GenerateBingoCard(BingoCardSize(gc.GetBingo()), pool, username, env.rnd), nil
+}
+
+// GenerateBingoCard fills a size x size card with traits drawn at random from the pool. Traits are
+// only repeated if the pool is too small, and traits that only the player themselves can satisfy are skipped.
+func GenerateBingoCard(size int64, pool []*qrpb.BingoTrait, username string, rnd *rand.Rand) []*qrpb.BingoCell {
+	usable := make([]*qrpb.BingoTrait, 0)
+	for _, t := range pool {
+		if len(t.GetUsernames()) == 1 && t.GetUsernames()[0] == username {
+			continue
+		}
+		usable = append(usable, t)
+	}
+
+	cells := make([]*qrpb.BingoCell, 0)
+	if len(usable) == 0 {
+		return cells
+	}
+	order := rnd.Perm(len(usable))
+	for i := int64(0); i < size*size; i++ {
+		t := usable[order[int(i)%len(order)]]
+		cells = append(cells, &qrpb.BingoCell{Trait: proto.Clone(t).(*qrpb.BingoTrait)})
+	}
+	return cells
+}
+
+// DefaultBingoTraits generates traits from the survey questions and the badge cards.
+func DefaultBingoTraits(ss *qrpb.SurveySet) []*qrpb.BingoTrait {
+	traits := make([]*qrpb.BingoTrait, 0)
+	for _, q := range ss.GetSurveyQuestions() {
+		traits = append(traits, &qrpb.BingoTrait{
+			Text:                proto.String(fmt.Sprintf("Said yes to: %v", q.GetQuestionText())),
+			SurveyId:            proto.Int64(q.GetQuestionId()),
+			SurveyTrueIsCorrect: proto.Bool(true),
+		})
+		traits = append(traits, &qrpb.BingoTrait{
+			Text:                proto.String(fmt.Sprintf("Said no to: %v", q.GetQuestionText())),
+			SurveyId:            proto.Int64(q.GetQuestionId()),
+			SurveyTrueIsCorrect: proto.Bool(false),
+		})
+	}
+	suits := []qrpb.CardSuit{qrpb.CardSuit_SPADES, qrpb.CardSuit_HEARTS, qrpb.CardSuit_CLUBS, qrpb.CardSuit_DIAMONDS}
+	symbols := []string{"♠", "♥", "♣", "♦"}
+	for i, s := range suits {
+		traits = append(traits, &qrpb.BingoTrait{
+			Text:     proto.String(fmt.Sprintf("Has a %v card", symbols[i])),
+			CardSuit: s.Enum(),
+		})
+	}
+	traits = append(traits, &qrpb.BingoTrait{
+		Text:     proto.String("Has an Ace"),
+		CardRank: proto.Int64(1),
+	})
+	return traits
+}
+
+// BingoTraitMatches returns true if the person with the given badge and survey answers qualifies
+// for the trait. gu may be nil if the person has not registered, in which case survey traits never match.
+func BingoTraitMatches(t *qrpb.BingoTrait, m *qrpb.QRMapping, gu *qrpb.GUser) bool {
+	if len(t.GetUsernames()) > 0 && !ListHasString(t.GetUsernames(), m.GetUsername()) {
+		return false
+	}
+	if t.SurveyId != nil {
+		if gu == nil || getSurveyResponse(gu, t.GetSurveyId()) != t.GetSurveyTrueIsCorrect() {
+			return false
+		}
+	}
+	if t.CardSuit != nil && m.GetCardSuit() != t.GetCardSuit() {
+		return false
+	}
+	if t.CardRank != nil && m.GetCardRank() != t.GetCardRank() {
+		return false
+	}
+	return true
+}
+
+// BingoCardSize returns the number of rows (and columns) on a bingo card.
+func BingoCardSize(bc *qrpb.BingoConfig) int64 {
+	if bc.GetCardSize() <= 0 {
+		return DEFAULT_BINGO_CARD_SIZE
+	}
+	return bc.GetCardSize()
+}
+
+// BingoCardSizeOf returns the number of rows (and columns) on a card that was already drawn. A card
+// keeps the size it was drawn with, even if card_size changes later.
+func BingoCardSizeOf(cells []*qrpb.BingoCell) int64 {
+	return int64(math.Sqrt(float64(len(cells))))
+}
+
+// CountBingoLines returns the number of complete rows, columns and diagonals on the card.
+func CountBingoLines(cells []*qrpb.BingoCell) int64 {
+	size := BingoCardSizeOf(cells)
+	if size == 0 || int64(len(cells)) != size*size {
+		return 0
+	}
+	filled := func(r, c int64) bool { return len(cells[r*size+c].GetFilledBy()) > 0 }
+
+	var lines int64
+	diag, anti := true, true
+	for i := int64(0); i < size; i++ {
+		row, col := true, true
+		for j := int64(0); j < size; j++ {
+			row = row && filled(i, j)
+			col = col && filled(j, i)
+		}
+		if row {
+			lines++
+		}
+		if col {
+			lines++
+		}
+		diag = diag && filled(i, i)
+		anti = anti && filled(i, size-1-i)
+	}
+	if diag {
+		lines++
+	}
+	if anti {
+		lines++
+	}
+	return lines
+}
+
+// CountFilledBingoCells returns how many squares on the card have been filled.
+func CountFilledBingoCells(cells []*qrpb.BingoCell) int {
+	n := 0
+	for _, c := range cells {
+		if len(c.GetFilledBy()) > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// RenderBingoCard returns the HTML for the player's bingo card.
+func (env *Env) RenderBingoCard(gs *qrpb.GameState) (string, error) {
+	gc, err := env.cgo.GetGameConfig()
+	if err != nil {
+		return "", err
+	}
+	qrm, err := env.cgo.GetQRMappings()
+	if err != nil {
+		return "", err
+	}
+
+	type BingoSquare struct {
+		Text     string
+		FilledBy string
+	}
+	size := BingoCardSizeOf(gs.GetBingoCells())
+	rows := make([][]BingoSquare, 0)
+	for i, c := range gs.GetBingoCells() {
+		if int64(i)%size == 0 {
+			rows = append(rows, make([]BingoSquare, 0))
+		}
+		sq := BingoSquare{Text: c.GetTrait().GetText()}
+		if len(c.GetFilledBy()) > 0 {
+			sq.FilledBy = c.GetFilledBy()
+			if m := qrm.LookupByUsername(c.GetFilledBy()); m != nil {
+				sq.FilledBy = m.GetDisplayName()
+			}
+		}
+		rows[len(rows)-1] = append(rows[len(rows)-1], sq)
+	}
+
+	var buf bytes.Buffer
+	err = env.tem.ExecuteTemplate(&buf, "bingocard.html", struct {
+		Rows     [][]BingoSquare
+		Lines    int64
+		Won      bool
+		Blackout bool
+	}{
+		Rows:     rows,
+		Lines:    gs.GetBingoLines(),
+		Won:      gs.GetBingoWon(),
+		Blackout: gc.GetBingo().GetBlackoutToWin(),
+	})
+	return buf.String(), err
+}
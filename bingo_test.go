@@ -0,0 +1,143 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sushovande/qr-mixer-game/qrpb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCountBingoLines(t *testing.T) {
+	cells := make([]*qrpb.BingoCell, 9)
+	for i := range cells {
+		cells[i] = &qrpb.BingoCell{}
+	}
+	fill := func(idx ...int) {
+		for _, i := range idx {
+			cells[i].FilledBy = proto.String("someone")
+		}
+	}
+
+	fill(0, 1)
+	if n := CountBingoLines(cells); n != 0 {
+		t.Errorf("expected no lines. got: %v", n)
+	}
+	fill(2)
+	if n := CountBingoLines(cells); n != 1 {
+		t.Errorf("expected the top row. got: %v", n)
+	}
+	fill(4, 6)
+	if n := CountBingoLines(cells); n != 2 {
+		t.Errorf("expected the top row and the anti-diagonal. got: %v", n)
+	}
+	fill(3, 5, 7, 8)
+	if n := CountBingoLines(cells); n != 8 {
+		t.Errorf("expected every line on a full card. got: %v", n)
+	}
+}
+
+func TestGenerateBingoCard(t *testing.T) {
+	pool := []*qrpb.BingoTrait{
+		{Text: proto.String("me"), Usernames: []string{"username-1"}},
+		{Text: proto.String("hearts"), CardSuit: qrpb.CardSuit_HEARTS.Enum()},
+		{Text: proto.String("aces"), CardRank: proto.Int64(1)},
+	}
+	cells := GenerateBingoCard(2, pool, "username-1", rand.New(rand.NewSource(1)))
+	if len(cells) != 4 {
+		t.Fatalf("expected a 2x2 card. got %v cells", len(cells))
+	}
+	for _, c := range cells {
+		if c.GetTrait().GetText() == "me" {
+			t.Errorf("expected the player not to get a square only they can fill")
+		}
+	}
+}
+
+func TestBingoGame(t *testing.T) {
+	env, err := createEnv(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer env.db.Close()
+	setupSynthetic(env.cgo, 12)
+
+	// Every square can only be filled by one particular person, so the card is predictable.
+	traits := make([]*qrpb.BingoTrait, 0)
+	for i := 2; i <= 10; i++ {
+		traits = append(traits, &qrpb.BingoTrait{
+			Text:      proto.String(fmt.Sprintf("trait-%v", i)),
+			Usernames: []string{fmt.Sprintf("username-%v", i)},
+		})
+	}
+	env.cgo.SetGameConfig(&qrpb.GameConfig{
+		GameMode: proto.String(GAME_MODE_BINGO),
+		Bingo:    &qrpb.BingoConfig{CardSize: proto.Int64(3), Traits: traits},
+	})
+
+	ck1 := http.Cookie{Name: "sid", Value: "cookie-1", Expires: time.Now().Add(24 * 30 * time.Hour)}
+	callController("POST", "/submitsurvey", "qr=qrcode-1&dqans1=true&dqans2=false", &ck1, env.submitSurvey)
+	u1, _ := GetUserStateByUsername(env.db, "username-1")
+	if len(u1.State.GetBingoCells()) != 9 {
+		t.Fatalf("expected a 3x3 card at registration. got %v cells", len(u1.State.GetBingoCells()))
+	}
+
+	f := callController("GET", "/game", "", &ck1, env.gameHandler)
+	if !strings.Contains(f.resptext, "bingocard") {
+		t.Errorf("expected the game page to show the bingo card. got: %v", f.resptext)
+	}
+
+	var mr MoveResponse
+	f = callController("POST", "/makemove", "answer=qrcode-1", &ck1, env.makeMove)
+	json.Unmarshal([]byte(f.resptext), &mr)
+	if mr.GameArtifacts["action"] != "That's You!" {
+		t.Errorf("expected scanning yourself to do nothing. got: %v", mr.GameArtifacts["action"])
+	}
+	f = callController("POST", "/makemove", "answer=qrcode-11", &ck1, env.makeMove)
+	json.Unmarshal([]byte(f.resptext), &mr)
+	if mr.GameArtifacts["action"] != "No Match!" || mr.State.GetLife() != STARTING_LIFE {
+		t.Errorf("expected no match without a penalty. got: %v", f.resptext)
+	}
+
+	// The card keeps the size it was drawn with.
+	env.cgo.SetGameConfig(&qrpb.GameConfig{
+		GameMode: proto.String(GAME_MODE_BINGO),
+		Bingo:    &qrpb.BingoConfig{CardSize: proto.Int64(4), Traits: traits},
+	})
+
+	// Fill the first row of the card.
+	for _, c := range u1.State.GetBingoCells()[0:3] {
+		qr := strings.Replace(c.GetTrait().GetUsernames()[0], "username", "qrcode", 1)
+		f = callController("POST", "/makemove", "answer="+qr, &ck1, env.makeMove)
+	}
+	mr = MoveResponse{}
+	json.Unmarshal([]byte(f.resptext), &mr)
+	if mr.GameArtifacts["action"] != "Bingo!" || !mr.State.GetBingoWon() || mr.State.GetBingoLines() != 1 {
+		t.Errorf("expected to win with one line. got: %v", f.resptext)
+	}
+
+	f = callController("GET", "/allUsers", "", nil, env.adminAllUsers)
+	if !strings.Contains(f.resptext, "Bingo Lines") {
+		t.Errorf("expected the leaderboard to show bingo lines. got: %v", f.resptext)
+	}
+}
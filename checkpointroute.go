@@ -0,0 +1,91 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+
+	"github.com/sushovande/qr-mixer-game/qrpb"
+
+	proto "google.golang.org/protobuf/proto"
+)
+
+// StepCheckpointRoute applies a scan on a CHECKPOINT_ROUTE question to the result.
+func StepCheckpointRoute(result *StepResponse, sq *qrpb.GameQuestion, old *qrpb.GameState, rules AnswerRules) {
+	route := sq.GetRouteUsernames()
+	if len(route) == 0 {
+		result.actionString = "This route has no checkpoints!"
+		result.actionResult = *qrpb.ActionLog_RESULT_IGNORED.Enum()
+		return
+	}
+	progress := old.GetRouteProgress()
+	if progress < 0 || progress >= int64(len(route)) {
+		progress = 0
+	}
+
+	if route[progress] == result.scannedClue {
+		progress++
+		if progress == int64(len(route)) {
+			AnswerCorrect(result, rules)
+			return
+		}
+		result.newState.RouteProgress = proto.Int64(progress)
+		result.actionString = fmt.Sprintf("Checkpoint %v of %v!", progress, len(route))
+		result.actionResult = *qrpb.ActionLog_RESULT_CHECKPOINT.Enum()
+		return
+	}
+
+	// Only after the next checkpoint, since a route can visit the same person twice.
+	if ListHasString(route[:progress], result.scannedClue) {
+		result.actionString = "Already Checked In!"
+		result.actionResult = *qrpb.ActionLog_RESULT_IGNORED.Enum()
+		return
+	}
+
+	if sq.GetRouteMistakeCostsLife() {
+		AnswerWrong(result, rules)
+		return
+	}
+	result.newState.RouteProgress = nil
+	result.actionString = "Wrong Order! Start Again."
+	result.actionResult = *qrpb.ActionLog_RESULT_ROUTE_RESET.Enum()
+}
+
+// RouteProgressHTML lists the checkpoints the player has already scanned on a CHECKPOINT_ROUTE question.
+// It returns an empty string for every other question type.
+func RouteProgressHTML(sq *qrpb.GameQuestion, gs *qrpb.GameState, qrm *QRMappings) string {
+	if sq.GetType() != qrpb.GQType_CHECKPOINT_ROUTE {
+		return ""
+	}
+	route := sq.GetRouteUsernames()
+	progress := gs.GetRouteProgress()
+	if progress > int64(len(route)) {
+		progress = int64(len(route))
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<div class="routeprogress"><p>Checkpoints: %v of %v</p><ul>`, progress, len(route))
+	for _, u := range route[:progress] {
+		name := u
+		if m := qrm.LookupByUsername(u); m != nil && len(m.GetDisplayName()) > 0 {
+			name = m.GetDisplayName()
+		}
+		fmt.Fprintf(&sb, "<li>✅ %v</li>", template.HTMLEscapeString(name))
+	}
+	sb.WriteString("</ul></div>")
+	return sb.String()
+}
@@ -0,0 +1,138 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sushovande/qr-mixer-game/qrpb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// setupRoute switches Q2 to a route through username-4, username-5 and username-6.
+func setupRoute(env *Env, costsLife bool) {
+	sqs, _ := env.cgo.GetGameQSet()
+	sqs.GameQuestions[1].Type = qrpb.GQType_CHECKPOINT_ROUTE.Enum()
+	sqs.GameQuestions[1].AnsUsernames = []string{}
+	sqs.GameQuestions[1].RouteUsernames = []string{"username-4", "username-5", "username-6"}
+	sqs.GameQuestions[1].RouteMistakeCostsLife = proto.Bool(costsLife)
+	env.cgo.SetGameQSet(sqs)
+}
+
+func TestCheckpointRouteResets(t *testing.T) {
+	env, err := createEnv(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer env.db.Close()
+	setupSynthetic(env.cgo, 10)
+	setupRoute(env, false)
+	u1 := GetSyntheticStateRow(1, 2)
+
+	mr, err := env.Step(u1.State, "qrcode-4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mr.newState.GetRouteProgress() != 1 || mr.newState.GetUserLevel() != 2 {
+		t.Errorf("expected to tick the first checkpoint. got: %v", mr.newState)
+	}
+
+	// Scanning the same checkpoint again does nothing.
+	mr, _ = env.Step(mr.newState, "qrcode-4")
+	if mr.newState.GetRouteProgress() != 1 || mr.actionResult != qrpb.ActionLog_RESULT_IGNORED {
+		t.Errorf("expected a repeat scan to be ignored. got: %v", mr.newState)
+	}
+
+	// Skipping ahead resets the route without costing a life.
+	mr, _ = env.Step(mr.newState, "qrcode-6")
+	if mr.newState.GetRouteProgress() != 0 || mr.newState.GetLife() != 3 {
+		t.Errorf("expected the route to reset. got: %v", mr.newState)
+	}
+	if mr.actionResult != qrpb.ActionLog_RESULT_ROUTE_RESET {
+		t.Errorf("expected a reset result. got: %v", mr.actionResult)
+	}
+
+	state := mr.newState
+	for _, qr := range []string{"qrcode-4", "qrcode-5", "qrcode-6"} {
+		mr, _ = env.Step(state, qr)
+		state = mr.newState
+	}
+	if mr.actionString != "Correct!" || state.GetUserLevel() != 3 || state.RouteProgress != nil {
+		t.Errorf("expected to finish the route. got: %v, %v", mr.actionString, state)
+	}
+}
+
+func TestCheckpointRouteCostsLife(t *testing.T) {
+	env, err := createEnv(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer env.db.Close()
+	setupSynthetic(env.cgo, 10)
+	setupRoute(env, true)
+	u1 := GetSyntheticStateRow(1, 2)
+
+	mr, _ := env.Step(u1.State, "qrcode-4")
+	mr, _ = env.Step(mr.newState, "qrcode-6")
+	if mr.newState.GetRouteProgress() != 1 || mr.newState.GetLife() != 2 {
+		t.Errorf("expected to keep progress and lose a life. got: %v", mr.newState)
+	}
+
+	qrm, _ := env.cgo.GetQRMappings()
+	sqs, _ := env.cgo.GetGameQSet()
+	ht := RouteProgressHTML(GetQuestionByIndex(sqs, 2), mr.newState, qrm)
+	if !strings.Contains(ht, "1 of 3") || !strings.Contains(ht, "name-4") || strings.Contains(ht, "name-5") {
+		t.Errorf("expected only the first checkpoint to be ticked. got: %v", ht)
+	}
+}
+
+func TestCheckpointRouteVisitsTwice(t *testing.T) {
+	env, err := createEnv(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer env.db.Close()
+	setupSynthetic(env.cgo, 10)
+	setupRoute(env, false)
+	sqs, _ := env.cgo.GetGameQSet()
+	sqs.GameQuestions[1].RouteUsernames = []string{"username-4", "username-5", "username-4"}
+	env.cgo.SetGameQSet(sqs)
+
+	state := GetSyntheticStateRow(1, 2).State
+	var mr StepResponse
+	for _, qr := range []string{"qrcode-4", "qrcode-4", "qrcode-5", "qrcode-4"} {
+		mr, _ = env.Step(state, qr)
+		state = mr.newState
+	}
+	if mr.actionString != "Correct!" || state.GetUserLevel() != 3 {
+		t.Errorf("expected the second visit to username-4 to finish the route. got: %v, %v", mr.actionString, state)
+	}
+}
+
+func TestRouteProgressClearedOnNewLevel(t *testing.T) {
+	for _, step := range []func(*StepResponse){
+		func(r *StepResponse) { AnswerCorrect(r, AnswerRules{}) },
+		func(r *StepResponse) { AnswerWrong(r, AnswerRules{MaxAttempts: 1}) },
+	} {
+		r := NewStepResponse()
+		r.newState = &qrpb.GameState{UserLevel: proto.Int64(2), Life: proto.Int64(3), RouteProgress: proto.Int64(2)}
+		step(&r)
+		if r.newState.GetUserLevel() != 3 || r.newState.RouteProgress != nil {
+			t.Errorf("expected the route progress to go with the old level. got: %v", r.newState)
+		}
+	}
+}
@@ -44,5 +44,11 @@ func MaybeCreateTables(db *sql.DB) error {
 	if err := MaybeCreateOptionsTable(db); err != nil {
 		return err
 	}
+	if err := MaybeCreateAssassinTable(db); err != nil {
+		return err
+	}
+	if err := MaybeCreatePhotoTable(db); err != nil {
+		return err
+	}
 	return nil
 }
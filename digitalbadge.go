@@ -0,0 +1,119 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+
+	"github.com/sushovande/qr-mixer-game/common"
+	"github.com/sushovande/qr-mixer-game/qrpb"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// PrintableMappings returns the roster without the digital-only players, who get no printed badge.
+func PrintableMappings(qrset *qrpb.QRMappingSet) *qrpb.QRMappingSet {
+	printable := &qrpb.QRMappingSet{}
+	for _, m := range qrset.GetQrMappings() {
+		if !m.GetDigitalOnly() {
+			printable.QrMappings = append(printable.QrMappings, m)
+		}
+	}
+	return printable
+}
+
+// JoinLinkRow is a digital-only player and the link they register with.
+type JoinLinkRow struct {
+	Name string
+	Link string
+}
+
+// JoinLink is the path a digital-only player opens to register without scanning a badge.
+func JoinLink(m *qrpb.QRMapping) string {
+	return "/confirmname?qr=" + url.QueryEscape(m.GetQrcode())
+}
+
+// badgePlayer looks up the player from their cookie, along with their roster entry. It writes the
+// error response and returns false if it could not.
+func (env *Env) badgePlayer(w http.ResponseWriter, r *http.Request) (*StateRow, *qrpb.QRMapping, bool) {
+	ck, err := r.Cookie("sid")
+	if err != nil {
+		common.RespondHTTP401(w)
+		return nil, nil, false
+	}
+	u, err := GetUserStateByCookie(env.db, ck.Value)
+	if err != nil || u == nil {
+		common.RespondHTTP401(w)
+		return nil, nil, false
+	}
+	qrm, err := env.cgo.GetQRMappings()
+	if common.Should500(err, w, "Could not fetch the qr code mappings") {
+		return nil, nil, false
+	}
+	m := qrm.LookupByUsername(u.Username)
+	if m == nil {
+		common.Should500(fmt.Errorf("%v is not in the roster", u.Username), w, "You are no longer on the list of players.")
+		return nil, nil, false
+	}
+	return u, m, true
+}
+
+// myBadge renders the player's badge on their phone, for people without a printed one.
+func (env *Env) myBadge(w http.ResponseWriter, r *http.Request) {
+	log.Println("Req: ", r.URL)
+	u, m, ok := env.badgePlayer(w, r)
+	if !ok {
+		return
+	}
+	gc, err := env.cgo.GetGameConfig()
+	if common.Should500(err, w, "There was a problem reading the game settings, maybe try again?") {
+		return
+	}
+	common.RenderTemplate(w, env.tem, "mybadge.html", struct {
+		U         *StateRow
+		M         *qrpb.QRMapping
+		PrintedOK bool
+	}{
+		U:         u,
+		M:         m,
+		PrintedOK: acceptsPrinted(gc),
+	})
+}
+
+// myBadgeImage is the QR code of the player's badge.
+func (env *Env) myBadgeImage(w http.ResponseWriter, r *http.Request) {
+	_, m, ok := env.badgePlayer(w, r)
+	if !ok {
+		return
+	}
+	png, err := qrcode.Encode(m.GetQrcode(), qrcode.High, 450)
+	if common.Should500(err, w, "could not encode qrcode") {
+		return
+	}
+	w.Header().Set("Content-Type", "image/png")
+	w.Write(png)
+}
+
+// myBadgeCard is the playing card on the player's badge.
+func (env *Env) myBadgeCard(w http.ResponseWriter, r *http.Request) {
+	_, m, ok := env.badgePlayer(w, r)
+	if !ok {
+		return
+	}
+	env.renderCardSVG(w, m.GetCardSuit().String(), fmt.Sprint(m.GetCardRank()))
+}
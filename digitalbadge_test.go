@@ -0,0 +1,94 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/sushovande/qr-mixer-game/qrpb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestDigitalBadges(t *testing.T) {
+	env, err := createEnv(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer env.db.Close()
+	setupSynthetic(env.cgo, 3)
+	qrm, _ := env.cgo.GetQRMappings()
+	qrm.mappings.GetQrMappings()[1].DigitalOnly = proto.Bool(true)
+	env.cgo.SetQRMappings(qrm)
+
+	printable := PrintableMappings(qrm.mappings)
+	if len(printable.GetQrMappings()) != 2 {
+		t.Fatalf("expected the digital-only player to be skipped. got: %v", printable)
+	}
+	for _, m := range printable.GetQrMappings() {
+		if m.GetUsername() == "username-2" {
+			t.Errorf("expected username-2 not to get a printed badge")
+		}
+	}
+	if link := JoinLink(qrm.mappings.GetQrMappings()[1]); link != "/confirmname?qr=qrcode-2" {
+		t.Errorf("unexpected join link: %v", link)
+	}
+
+	f := callController("GET", "/mybadge", "", nil, env.myBadge)
+	if f.statuscode != http.StatusUnauthorized {
+		t.Errorf("expected the badge page to need a login. got: %v", f.statuscode)
+	}
+
+	u2 := GetSyntheticStateRow(2, 1)
+	AddUser(env.db, u2)
+	ck := http.Cookie{Name: "sid", Value: u2.Cookie}
+	f = callController("GET", "/mybadge", "", &ck, env.myBadge)
+	if !strings.Contains(f.resptext, `src="/mybadge.png"`) || !strings.Contains(f.resptext, "username-2@") {
+		t.Errorf("expected the badge page. got: %v", f.resptext)
+	}
+	f = callController("GET", "/mybadge.png", "", &ck, env.myBadgeImage)
+	if f.statuscode != http.StatusOK {
+		t.Errorf("expected the badge image. got: %v", f.statuscode)
+	}
+	f = callController("GET", "/mybadgecard.svg", "", &ck, env.myBadgeCard)
+	if f.statuscode != http.StatusOK || !strings.Contains(f.resptext, "<svg") {
+		t.Errorf("expected the card image. got: %v %v", f.statuscode, f.resptext)
+	}
+
+	// Only players without a printed badge are offered one on their phone.
+	f = callController("GET", "/game", "", &ck, env.gameHandler)
+	if !strings.Contains(f.resptext, `href="/mybadge"`) {
+		t.Errorf("expected the digital-only player to get a badge link. got: %v", f.resptext)
+	}
+	u1 := GetSyntheticStateRow(1, 1)
+	AddUser(env.db, u1)
+	f = callController("GET", "/game", "", &http.Cookie{Name: "sid", Value: u1.Cookie}, env.gameHandler)
+	if strings.Contains(f.resptext, `href="/mybadge"`) {
+		t.Errorf("expected no badge link for a player with a printed badge. got: %v", f.resptext)
+	}
+
+	// When only phone codes count, the printed code is not shown.
+	env.cgo.SetGameConfig(&qrpb.GameConfig{BadgeCodes: qrpb.BadgeCodes_ROTATING_ONLY.Enum()})
+	f = callController("GET", "/mybadge", "", &ck, env.myBadge)
+	if strings.Contains(f.resptext, `src="/mybadge.png"`) || !strings.Contains(f.resptext, `href="/mycode"`) {
+		t.Errorf("expected a link to the rotating code instead. got: %v", f.resptext)
+	}
+	f = callController("GET", "/game", "", &ck, env.gameHandler)
+	if strings.Contains(f.resptext, `href="/mybadge"`) || !strings.Contains(f.resptext, `href="/mycode"`) {
+		t.Errorf("expected the game page to offer only the rotating code. got: %v", f.resptext)
+	}
+}
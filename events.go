@@ -0,0 +1,250 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sushovande/qr-mixer-game/common"
+	"github.com/sushovande/qr-mixer-game/qrpb"
+
+	proto "google.golang.org/protobuf/proto"
+)
+
+// EventEffects are the modifiers of every global event running at some moment.
+type EventEffects struct {
+	DoubleTokenChance bool
+	FreeWrongScans    bool
+	LifePerCorrect    bool
+}
+
+// ActiveEvents returns the events that are running at the given time.
+func ActiveEvents(es *qrpb.GlobalEventSet, now time.Time) []*qrpb.GlobalEvent {
+	active := make([]*qrpb.GlobalEvent, 0)
+	usec := now.UnixMicro()
+	for _, e := range es.GetEvents() {
+		if e.GetStartUsec() <= usec && usec < e.GetEndUsec() {
+			active = append(active, e)
+		}
+	}
+	return active
+}
+
+// EffectsOf combines the modifiers of the given events.
+func EffectsOf(active []*qrpb.GlobalEvent) EventEffects {
+	var fx EventEffects
+	for _, e := range active {
+		switch e.GetKind() {
+		case qrpb.GlobalEventKind_DOUBLE_TOKEN_CHANCE:
+			fx.DoubleTokenChance = true
+		case qrpb.GlobalEventKind_FREE_WRONG_SCANS:
+			fx.FreeWrongScans = true
+		case qrpb.GlobalEventKind_LIFE_PER_CORRECT:
+			fx.LifePerCorrect = true
+		}
+	}
+	return fx
+}
+
+// Apply returns the answer rules as changed by the events.
+func (fx EventEffects) Apply(rules AnswerRules) AnswerRules {
+	if fx.FreeWrongScans {
+		rules.Penalty = 0
+	}
+	if fx.LifePerCorrect {
+		r := &qrpb.QuestionReward{}
+		if rules.Reward != nil {
+			r = proto.Clone(rules.Reward).(*qrpb.QuestionReward)
+		}
+		r.Lives = proto.Int64(r.GetLives() + 1)
+		rules.Reward = r
+	}
+	return rules
+}
+
+// CurrentEventEffects returns the modifiers of the events running right now.
+func (env *Env) CurrentEventEffects() (EventEffects, error) {
+	es, err := env.cgo.GetGlobalEvents()
+	if err != nil {
+		return EventEffects{}, err
+	}
+	return EffectsOf(ActiveEvents(es, time.Now())), nil
+}
+
+// DefaultEventMessage is the banner text for an event the organizer did not write one for.
+func DefaultEventMessage(kind qrpb.GlobalEventKind) string {
+	switch kind {
+	case qrpb.GlobalEventKind_DOUBLE_TOKEN_CHANCE:
+		return "Tokens are twice as likely right now!"
+	case qrpb.GlobalEventKind_FREE_WRONG_SCANS:
+		return "Wrong scans are free right now!"
+	case qrpb.GlobalEventKind_LIFE_PER_CORRECT:
+		return "Every correct answer gives you a life right now!"
+	}
+	return ""
+}
+
+// EventBanner is the text shown to players about the given events. It is empty if there are none.
+func EventBanner(active []*qrpb.GlobalEvent) string {
+	msgs := make([]string, 0, len(active))
+	for _, e := range active {
+		msgs = append(msgs, e.GetMessage())
+	}
+	return strings.Join(msgs, " ")
+}
+
+// currentEventBanner returns the banner for the events running right now.
+func (env *Env) currentEventBanner() (string, error) {
+	es, err := env.cgo.GetGlobalEvents()
+	if err != nil {
+		return "", err
+	}
+	return EventBanner(ActiveEvents(es, time.Now())), nil
+}
+
+// StartEvent runs a new event from now for the given duration.
+func (env *Env) StartEvent(kind qrpb.GlobalEventKind, d time.Duration, message string, now time.Time) error {
+	if _, ok := qrpb.GlobalEventKind_name[int32(kind)]; !ok || kind == qrpb.GlobalEventKind_GLOBAL_EVENT_UNSPECIFIED {
+		return fmt.Errorf("unknown event kind %v", kind)
+	}
+	if d <= 0 {
+		return fmt.Errorf("the event must last a while, got %v", d)
+	}
+	if len(strings.TrimSpace(message)) == 0 {
+		message = DefaultEventMessage(kind)
+	}
+
+	es, err := env.cgo.GetGlobalEvents()
+	if err != nil {
+		return err
+	}
+	es = proto.Clone(es).(*qrpb.GlobalEventSet)
+	es.Events = append(es.Events, &qrpb.GlobalEvent{
+		Kind:      kind.Enum(),
+		StartUsec: proto.Int64(now.UnixMicro()),
+		EndUsec:   proto.Int64(now.Add(d).UnixMicro()),
+		Message:   proto.String(message),
+	})
+	return env.cgo.SetGlobalEvents(es)
+}
+
+// StopEvent ends the event at the given index of the event set early.
+func (env *Env) StopEvent(index int, now time.Time) error {
+	es, err := env.cgo.GetGlobalEvents()
+	if err != nil {
+		return err
+	}
+	if index < 0 || index >= len(es.GetEvents()) {
+		return fmt.Errorf("there is no event %v", index)
+	}
+	es = proto.Clone(es).(*qrpb.GlobalEventSet)
+	e := es.Events[index]
+	if e.GetEndUsec() > now.UnixMicro() {
+		e.EndUsec = proto.Int64(now.UnixMicro())
+	}
+	return env.cgo.SetGlobalEvents(es)
+}
+
+func (env *Env) adminRenderEvents(w http.ResponseWriter, r *http.Request) {
+	log.Println("Req: ", r.URL)
+	es, err := env.cgo.GetGlobalEvents()
+	if common.Should500(err, w, "could not read the events") {
+		return
+	}
+
+	kol, err := time.LoadLocation("Asia/Kolkata")
+	if common.Should500(err, w, "could not make a timezone") {
+		return
+	}
+
+	type StrEvent struct {
+		Index   int
+		Kind    string
+		Message string
+		Start   string
+		End     string
+		Running bool
+	}
+
+	now := time.Now()
+	events := make([]StrEvent, 0)
+	// Newest first.
+	for i := len(es.GetEvents()) - 1; i >= 0; i-- {
+		e := es.GetEvents()[i]
+		events = append(events, StrEvent{
+			Index:   i,
+			Kind:    e.GetKind().String(),
+			Message: e.GetMessage(),
+			Start:   time.UnixMicro(e.GetStartUsec()).In(kol).Format("2006-01-02 3:04:05 PM"),
+			End:     time.UnixMicro(e.GetEndUsec()).In(kol).Format("2006-01-02 3:04:05 PM"),
+			Running: e.GetStartUsec() <= now.UnixMicro() && now.UnixMicro() < e.GetEndUsec(),
+		})
+	}
+
+	kinds := make([]string, 0)
+	for k := int32(1); k < int32(len(qrpb.GlobalEventKind_name)); k++ {
+		kinds = append(kinds, qrpb.GlobalEventKind(k).String())
+	}
+
+	renderData := struct {
+		Events []StrEvent
+		Kinds  []string
+	}{events, kinds}
+	common.RenderTemplate(w, env.tem, "adminevents.html", renderData)
+}
+
+func (env *Env) adminStartEvent(w http.ResponseWriter, r *http.Request) {
+	log.Println("Req: ", r.URL)
+	if common.Should500(r.ParseForm(), w, "error parsing data for the event") {
+		return
+	}
+
+	kind, ok := qrpb.GlobalEventKind_value[r.FormValue("kind")]
+	if !ok {
+		common.Should500(fmt.Errorf("unknown event kind %q", r.FormValue("kind")), w, "pick a kind of event")
+		return
+	}
+	minutes, err := strconv.ParseInt(r.FormValue("minutes"), 10, 64)
+	if common.Should500(err, w, "could not parse how many minutes the event lasts") {
+		return
+	}
+
+	err = env.StartEvent(qrpb.GlobalEventKind(kind), time.Duration(minutes)*time.Minute, r.FormValue("message"), time.Now())
+	if common.Should500(err, w, "could not start the event") {
+		return
+	}
+	fmt.Fprint(w, "ok")
+}
+
+func (env *Env) adminStopEvent(w http.ResponseWriter, r *http.Request) {
+	log.Println("Req: ", r.URL)
+	if common.Should500(r.ParseForm(), w, "error parsing data for the event") {
+		return
+	}
+
+	index, err := strconv.Atoi(r.FormValue("index"))
+	if common.Should500(err, w, "could not parse the event index") {
+		return
+	}
+	if common.Should500(env.StopEvent(index, time.Now()), w, "could not stop the event") {
+		return
+	}
+	fmt.Fprint(w, "ok")
+}
@@ -0,0 +1,99 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sushovande/qr-mixer-game/qrpb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestEventEffects(t *testing.T) {
+	now := time.Now()
+	es := &qrpb.GlobalEventSet{Events: []*qrpb.GlobalEvent{
+		{
+			Kind:      qrpb.GlobalEventKind_FREE_WRONG_SCANS.Enum(),
+			StartUsec: proto.Int64(now.Add(-time.Minute).UnixMicro()),
+			EndUsec:   proto.Int64(now.Add(time.Minute).UnixMicro()),
+		},
+		{
+			Kind:      qrpb.GlobalEventKind_LIFE_PER_CORRECT.Enum(),
+			StartUsec: proto.Int64(now.Add(-time.Hour).UnixMicro()),
+			EndUsec:   proto.Int64(now.Add(-time.Minute).UnixMicro()),
+		},
+	}}
+
+	fx := EffectsOf(ActiveEvents(es, now))
+	if !fx.FreeWrongScans || fx.LifePerCorrect || fx.DoubleTokenChance {
+		t.Errorf("expected only the running event to apply. got: %+v", fx)
+	}
+
+	r := EventEffects{FreeWrongScans: true, LifePerCorrect: true}.Apply(AnswerRules{Penalty: 2})
+	if r.Penalty != 0 || r.Reward.GetLives() != 1 {
+		t.Errorf("expected no penalty and an extra life. got: %+v", r)
+	}
+}
+
+func TestGlobalEventsInGame(t *testing.T) {
+	env, err := createEnv(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer env.db.Close()
+	setupSynthetic(env.cgo, 10)
+
+	f := callController("POST", "/startEvent", "kind=FREE_WRONG_SCANS&minutes=10&message=Free+for+all", nil, env.adminStartEvent)
+	if f.statuscode != http.StatusOK {
+		t.Fatalf("expected the event to start. got: %v", f.resptext)
+	}
+	f = callController("POST", "/startEvent", "kind=LIFE_PER_CORRECT&minutes=0", nil, env.adminStartEvent)
+	if f.statuscode != http.StatusInternalServerError {
+		t.Errorf("expected an event with no duration to be rejected. got: %v", f.resptext)
+	}
+
+	u1 := GetSyntheticStateRow(1, 2)
+	mr, _ := env.Step(u1.State, "qrcode-9")
+	if mr.actionString != "Not Quite!" || mr.newState.GetLife() != 3 {
+		t.Errorf("expected a free wrong scan. got: %v %v", mr.actionString, mr.newState)
+	}
+
+	// The event survives a restart.
+	env.cgo = CreateCachedGameOptions(env.db)
+	if banner, _ := env.currentEventBanner(); banner != "Free for all" {
+		t.Errorf("expected the event banner after a restart. got: %q", banner)
+	}
+
+	f = callController("POST", "/stopEvent", "index=0", nil, env.adminStopEvent)
+	if f.statuscode != http.StatusOK {
+		t.Fatalf("expected the event to stop. got: %v", f.resptext)
+	}
+	mr, _ = env.Step(u1.State, "qrcode-9")
+	if mr.actionString != "Lost a Life!" || mr.newState.GetLife() != 2 {
+		t.Errorf("expected wrong scans to cost a life again. got: %v %v", mr.actionString, mr.newState)
+	}
+
+	env.StartEvent(qrpb.GlobalEventKind_LIFE_PER_CORRECT, time.Minute, "", time.Now())
+	mr, _ = env.Step(u1.State, "qrcode-2")
+	if mr.actionString != "Correct!" || mr.newState.GetLife() != 4 {
+		t.Errorf("expected a life for a correct scan. got: %v %v", mr.actionString, mr.newState)
+	}
+	if banner, _ := env.currentEventBanner(); banner != DefaultEventMessage(qrpb.GlobalEventKind_LIFE_PER_CORRECT) {
+		t.Errorf("expected the default banner. got: %q", banner)
+	}
+}
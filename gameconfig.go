@@ -0,0 +1,107 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sushovande/qr-mixer-game/qrpb"
+)
+
+// Defaults for the settings a game config leaves unset.
+const (
+	STARTING_LEVEL          int64 = 1
+	STARTING_LIFE           int64 = 5
+	DEFAULT_COOKIE_VALIDITY       = 24 * 30 * time.Hour
+	DEFAULT_MAX_HEARTS      int64 = 5
+	// MAX_HEARTS_LIMIT keeps the row of hearts on one line of a phone screen.
+	MAX_HEARTS_LIMIT int64 = 20
+)
+
+// StartingLife returns how many lives a new player gets.
+func StartingLife(gc *qrpb.GameConfig) int64 {
+	if gc != nil && gc.StartingLife != nil {
+		return gc.GetStartingLife()
+	}
+	return STARTING_LIFE
+}
+
+// StartingLevel returns the question a new player starts on.
+func StartingLevel(gc *qrpb.GameConfig) int64 {
+	if gc != nil && gc.StartingLevel != nil {
+		return gc.GetStartingLevel()
+	}
+	return STARTING_LEVEL
+}
+
+// CookieValidity returns how long a player stays logged in.
+func CookieValidity(gc *qrpb.GameConfig) time.Duration {
+	if gc != nil && gc.CookieValidityHours != nil {
+		return time.Duration(gc.GetCookieValidityHours()) * time.Hour
+	}
+	return DEFAULT_COOKIE_VALIDITY
+}
+
+// CacheTTLSec returns how many seconds the game options are cached for.
+func CacheTTLSec(gc *qrpb.GameConfig) float64 {
+	if gc != nil && gc.CacheTtlSec != nil {
+		return float64(gc.GetCacheTtlSec())
+	}
+	return CACHE_TTL_SEC
+}
+
+// MaxHearts returns how many hearts the game page shows.
+func MaxHearts(gc *qrpb.GameConfig) int64 {
+	if gc != nil && gc.MaxHearts != nil {
+		return gc.GetMaxHearts()
+	}
+	return DEFAULT_MAX_HEARTS
+}
+
+// HeartNumbers returns 1 to MaxHearts, for ranging over in templates.
+func HeartNumbers(gc *qrpb.GameConfig) []int64 {
+	hearts := make([]int64, 0)
+	for i := int64(1); i <= MaxHearts(gc); i++ {
+		hearts = append(hearts, i)
+	}
+	return hearts
+}
+
+// CheckGameSettings returns an error if a starting value or limit in the game config makes no sense.
+func CheckGameSettings(gc *qrpb.GameConfig) error {
+	if StartingLife(gc) < 1 {
+		return fmt.Errorf("starting_life must be at least 1, got %v", gc.GetStartingLife())
+	}
+	if StartingLevel(gc) < 1 {
+		return fmt.Errorf("starting_level must be at least 1, got %v", gc.GetStartingLevel())
+	}
+	if gc.CookieValidityHours != nil && gc.GetCookieValidityHours() < 1 {
+		return fmt.Errorf("cookie_validity_hours must be at least 1, got %v", gc.GetCookieValidityHours())
+	}
+	if gc.CacheTtlSec != nil && gc.GetCacheTtlSec() < 0 {
+		return fmt.Errorf("cache_ttl_sec cannot be negative, got %v", gc.GetCacheTtlSec())
+	}
+	if h := MaxHearts(gc); h < 1 || h > MAX_HEARTS_LIMIT {
+		return fmt.Errorf("max_hearts must be between 1 and %v, got %v", MAX_HEARTS_LIMIT, h)
+	}
+	if gc.RotatingCodePeriodSec != nil && gc.GetRotatingCodePeriodSec() < MIN_ROTATING_CODE_PERIOD_SEC {
+		return fmt.Errorf("rotating_code_period_sec must be at least %v, got %v", MIN_ROTATING_CODE_PERIOD_SEC, gc.GetRotatingCodePeriodSec())
+	}
+	if StartingLife(gc) > MaxHearts(gc) {
+		return fmt.Errorf("starting_life (%v) is more than the page can show, raise max_hearts", StartingLife(gc))
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sushovande/qr-mixer-game/qrpb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestGameSettingDefaults(t *testing.T) {
+	var gc *qrpb.GameConfig
+	if StartingLife(gc) != 5 || StartingLevel(gc) != 1 || MaxHearts(gc) != 5 || CookieValidity(gc) != 30*24*time.Hour || CacheTTLSec(gc) != 60 {
+		t.Errorf("expected the old constants as defaults")
+	}
+	if err := CheckGameSettings(&qrpb.GameConfig{}); err != nil {
+		t.Errorf("expected an empty config to be valid. got: %v", err)
+	}
+
+	bad := []*qrpb.GameConfig{
+		{StartingLife: proto.Int64(0)},
+		{StartingLevel: proto.Int64(0)},
+		{CookieValidityHours: proto.Int64(0)},
+		{CacheTtlSec: proto.Int64(-1)},
+		{MaxHearts: proto.Int64(50)},
+		{StartingLife: proto.Int64(8)},
+	}
+	for _, gc := range bad {
+		if CheckGameSettings(gc) == nil {
+			t.Errorf("expected %v to be rejected", gc)
+		}
+	}
+	if err := CheckGameSettings(&qrpb.GameConfig{StartingLife: proto.Int64(8), MaxHearts: proto.Int64(8)}); err != nil {
+		t.Errorf("expected more hearts to allow more lives. got: %v", err)
+	}
+}
+
+func TestConfiguredNewPlayer(t *testing.T) {
+	env, err := createEnv(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer env.db.Close()
+	setupGlobals(env.cgo)
+
+	f := callController("POST", "/saveQuestions", "gameconfig="+url.QueryEscape(`starting_life: 3 max_hearts: 2`), nil, env.adminSaveQuestions)
+	if f.statuscode != http.StatusInternalServerError {
+		t.Errorf("expected more lives than hearts to be rejected. got: %v", f.resptext)
+	}
+	f = callController("POST", "/saveQuestions", "gameconfig="+url.QueryEscape(`starting_life: 7 starting_level: 2 max_hearts: 8 cookie_validity_hours: 6`), nil, env.adminSaveQuestions)
+	if f.statuscode != http.StatusOK {
+		t.Fatalf("expected the settings to be saved. got: %v", f.resptext)
+	}
+
+	f = callController("GET", "/survey?qr=qrcode-1", "", nil, env.survey)
+	if f.cookie == nil || time.Until(f.cookie.Expires) > 7*time.Hour {
+		t.Errorf("expected the cookie to last six hours. got: %v", f.cookie)
+	}
+
+	postData := fmt.Sprintf("qr=%v&dqans1=true&dqans2=false", url.QueryEscape("qrcode-1"))
+	ck := http.Cookie{Name: "sid", Value: "foo-foo"}
+	callController("POST", "/submitsurvey", postData, &ck, env.submitSurvey)
+	sr, err := GetUserStateByCookie(env.db, "foo-foo")
+	if err != nil || sr == nil {
+		t.Fatalf("expected the player to be registered. got: %v %v", sr, err)
+	}
+	if sr.State.GetLife() != 7 || sr.State.GetUserLevel() != 2 {
+		t.Errorf("expected the configured starting state. got: %v", sr.State)
+	}
+
+	f = callController("GET", "/game", "", &ck, env.gameHandler)
+	if strings.Count(f.resptext, `class="heart heart-active"`) != 7 || strings.Count(f.resptext, `class="heart heart-dead"`) != 1 {
+		t.Errorf("expected seven of eight hearts. got: %v", f.resptext)
+	}
+}
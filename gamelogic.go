@@ -15,17 +15,27 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"math/rand"
+	"time"
 
 	"github.com/sushovande/qr-mixer-game/qrpb"
 
 	proto "google.golang.org/protobuf/proto"
 )
 
+// errNotRegistered is the error of a scan that needed the scanned person to have registered.
+var errNotRegistered = errors.New("not yet registered in the game")
+
 // Step returns the GameState resulting from the action at the current GameState
 func (env *Env) Step(old *qrpb.GameState, answer string) (StepResponse, error) {
-	qrm, err := env.cgo.GetQRMappings()
+	return env.StepWithHandicap(old, answer, nil)
+}
+
+// StepWithHandicap is Step for a player with the given handicap, which may be nil.
+func (env *Env) StepWithHandicap(old *qrpb.GameState, answer string, h *qrpb.PlayerHandicap) (StepResponse, error) {
+	scanned, rejection, err := env.ResolveScan(answer, time.Now())
 	if err != nil {
 		return StepResponse{}, err
 	}
@@ -34,13 +44,16 @@ func (env *Env) Step(old *qrpb.GameState, answer string) (StepResponse, error) {
 	result := NewStepResponse()
 	result.actionString = "Lost a Life!"
 	result.actionResult = *qrpb.ActionLog_RESULT_LOST_LIFE.Enum()
-	result.scannedClue = qrm.LookupByQrCode(answer).GetUsername()
+	result.scannedClue = scanned.GetUsername()
 	result.newState = proto.Clone(old).(*qrpb.GameState)
 
+	if len(rejection) > 0 {
+		stepRejected(&result, rejection)
+		return result, nil
+	}
+
 	// ENDGAME logic
-	if old.GetUserLevel() == 22 {
-		result.actionString = "Already Victorious!"
-		result.actionResult = *qrpb.ActionLog_RESULT_ALREADY_VICTORIOUS.Enum()
+	if StepTerminal(&result, old) {
 		return result, nil
 	}
 
@@ -54,10 +67,10 @@ func (env *Env) Step(old *qrpb.GameState, answer string) (StepResponse, error) {
 		}
 		if gs == nil {
 			// scanned someone who is not yet registered?
-			return StepResponse{}, fmt.Errorf("you tried to get metals from someone who is not yet registered in the game")
+			return StepResponse{}, fmt.Errorf("you tried to get metals from someone who is %w", errNotRegistered)
 		}
 
-		if GrabMetalFromSomeone(&result, gs.State) {
+		if GrabMetalFromSomeone(&result, gs.State, env.rnd) {
 			result.actionString = "Grabbed Metal!"
 			result.actionResult = *qrpb.ActionLog_RESULT_GRABBED_METAL.Enum()
 
@@ -78,16 +91,36 @@ func (env *Env) Step(old *qrpb.GameState, answer string) (StepResponse, error) {
 		return StepResponse{}, err
 	}
 
-	sq := GetQuestionByIndex(sqs, old.GetUserLevel())
+	gc, err := env.cgo.GetGameConfig()
+	if err != nil {
+		return StepResponse{}, err
+	}
+
+	fx, err := env.CurrentEventEffects()
+	if err != nil {
+		return StepResponse{}, err
+	}
+
+	ss, err := env.cgo.GetSurveySet()
+	if err != nil {
+		return StepResponse{}, err
+	}
+
+	sq := GetQuestionForPlayer(sqs, old)
+	if StepMisconfigured(&result, sq, ss, old.GetUserLevel()) {
+		return result, nil
+	}
+	rules := ApplyHandicap(fx.Apply(RulesFor(sq, gc)), h)
+
+	if StepLocked(&result, sq, time.Now()) {
+		return result, nil
+	}
 
 	if *sq.Type == qrpb.GQType_USERNAME_LIST {
 		if ListHasString(sq.AnsUsernames, result.scannedClue) {
-			// TODO: check if this level arithmetic is desirable
-			result.newState.UserLevel = proto.Int64(old.GetUserLevel() + 1)
-			result.actionString = "Correct!"
-			result.actionResult = *qrpb.ActionLog_RESULT_PROGRESS.Enum()
+			AnswerCorrect(&result, rules)
 		} else {
-			result.newState.Life = proto.Int64(old.GetLife() - 1)
+			AnswerWrong(&result, rules)
 		}
 	} else if *sq.Type == qrpb.GQType_SURVEY_ANS {
 		gu, err := GetUserInfoByUsername(env.db, result.scannedClue)
@@ -96,23 +129,33 @@ func (env *Env) Step(old *qrpb.GameState, answer string) (StepResponse, error) {
 		}
 		if gu == nil {
 			// scanned someone who is not yet registered?
-			return StepResponse{}, fmt.Errorf("you scanned someone who is not yet registered in the game")
+			return StepResponse{}, fmt.Errorf("you scanned someone who is %w", errNotRegistered)
 		}
 		if getSurveyResponse(gu, sq.GetSurveyId()) == sq.GetSurveyTrueIsCorrect() {
-			result.newState.UserLevel = proto.Int64(old.GetUserLevel() + 1)
-			result.actionString = "Correct!"
-			result.actionResult = *qrpb.ActionLog_RESULT_PROGRESS.Enum()
+			AnswerCorrect(&result, rules)
 		} else {
-			result.newState.Life = proto.Int64(old.GetLife() - 1)
+			AnswerWrong(&result, rules)
 		}
 	} else if *sq.Type == qrpb.GQType_ANY_PERSON {
 		// TODO: if at all needed, remove the ability to scan inanimate objects at this time.
-		result.newState.UserLevel = proto.Int64(old.GetUserLevel() + 1)
-		result.actionString = "Correct!"
-		result.actionResult = *qrpb.ActionLog_RESULT_PROGRESS.Enum()
+		AnswerCorrect(&result, rules)
+	} else if *sq.Type == qrpb.GQType_TEXT_ANSWER {
+		// Scanning a badge does not count as an attempt on a typed answer.
+		result.actionString = "Type your answer instead!"
+		result.actionResult = *qrpb.ActionLog_RESULT_IGNORED.Enum()
+	} else if *sq.Type == qrpb.GQType_CHECKPOINT_ROUTE {
+		StepCheckpointRoute(&result, sq, old, rules)
+	} else if *sq.Type == qrpb.GQType_SECRET_BUDDY {
+		StepSecretBuddy(&result, sq, old, rules)
+	} else if *sq.Type == qrpb.GQType_PHOTO_PROOF {
+		result.actionString = "Upload a photo instead!"
+		if old.GetPhotoPending() {
+			result.actionString = "Your photo is still being reviewed."
+		}
+		result.actionResult = *qrpb.ActionLog_RESULT_IGNORED.Enum()
 	}
 
-	MaybeGrantMetal(&result)
+	MaybeGrantMetal(&result, fx.DoubleTokenChance, env.rnd)
 
 	if result.newState.GetLife() <= 0 {
 		result.actionString = "Dead!"
@@ -120,12 +163,14 @@ func (env *Env) Step(old *qrpb.GameState, answer string) (StepResponse, error) {
 		result.newState.UserLevel = proto.Int64(-1)
 	}
 
-	result.levelClue = GetQuestionByIndex(sqs, *result.newState.UserLevel).GetQuestionHtml()
 	return result, nil
 }
 
-func MaybeGrantMetal(result *StepResponse) {
-	if result.actionResult != *qrpb.ActionLog_RESULT_PROGRESS.Enum() {
+// MaybeGrantMetal sometimes hands out a token when the player moves on to one of the token levels.
+// doubleChance doubles the odds, as during a DOUBLE_TOKEN_CHANCE event.
+func MaybeGrantMetal(result *StepResponse, doubleChance bool, rnd *rand.Rand) {
+	// Players who skip a question still need their tokens for the endgame.
+	if result.actionResult != *qrpb.ActionLog_RESULT_PROGRESS.Enum() && result.actionResult != *qrpb.ActionLog_RESULT_SKIPPED.Enum() {
 		return
 	}
 
@@ -137,16 +182,16 @@ func MaybeGrantMetal(result *StepResponse) {
 
 		// choose a metal to grant
 		metal := "al"
-		if rand.Float32() > 0.5 {
+		if rnd.Float32() > 0.5 {
 			metal = "cu"
 		}
 
 		if *result.newState.UserLevel == 8 {
-			if rand.Float32() > 0.4 {
+			if tokenChance(rnd, 0.6, doubleChance) {
 				GrantMetal(result, metal)
 			}
 		} else if *result.newState.UserLevel == 9 {
-			if rand.Float32() > 0.7 {
+			if tokenChance(rnd, 0.3, doubleChance) {
 				GrantMetal(result, metal)
 			}
 		} else if *result.newState.UserLevel == 10 {
@@ -162,16 +207,16 @@ func MaybeGrantMetal(result *StepResponse) {
 
 		// choose a metal to grant
 		metal := "sn"
-		if rand.Float32() > 0.5 {
+		if rnd.Float32() > 0.5 {
 			metal = "zn"
 		}
 
 		if *result.newState.UserLevel == 18 {
-			if rand.Float32() > 0.4 {
+			if tokenChance(rnd, 0.6, doubleChance) {
 				GrantMetal(result, metal)
 			}
 		} else if *result.newState.UserLevel == 19 {
-			if rand.Float32() > 0.7 {
+			if tokenChance(rnd, 0.3, doubleChance) {
 				GrantMetal(result, metal)
 			}
 		} else if *result.newState.UserLevel == 20 {
@@ -180,9 +225,17 @@ func MaybeGrantMetal(result *StepResponse) {
 	}
 }
 
+// tokenChance returns true with probability p, or twice that if doubled.
+func tokenChance(rnd *rand.Rand, p float32, doubled bool) bool {
+	if doubled {
+		p *= 2
+	}
+	return rnd.Float32() < p
+}
+
 // GrabMetalFromSomeone grabs a shared metal from another user in the endgame.
 // Returns true if a metal was grabbed.
-func GrabMetalFromSomeone(result *StepResponse, gs *qrpb.GameState) bool {
+func GrabMetalFromSomeone(result *StepResponse, gs *qrpb.GameState, rnd *rand.Rand) bool {
 	grabbable := make([]string, 0)
 	if gs.GetHasAl() && !result.newState.GetHasAl() {
 		grabbable = append(grabbable, "al")
@@ -200,7 +253,7 @@ func GrabMetalFromSomeone(result *StepResponse, gs *qrpb.GameState) bool {
 	if len(grabbable) == 0 {
 		return false
 	}
-	w := rand.Intn(len(grabbable))
+	w := rnd.Intn(len(grabbable))
 	GrantMetal(result, grabbable[w])
 	return true
 }
@@ -222,13 +275,26 @@ func GrantMetal(result *StepResponse, metal string) {
 	}
 }
 
-func GetQuestionByIndex(sqs *qrpb.GameQSet, n int64) *qrpb.GameQuestion {
-	for _, q := range sqs.GameQuestions {
-		if q.GetQuestionId() == n {
-			return q
+// AnswerTypeFor tells the player page how the current question should be answered,
+// when it is not answered by scanning.
+func AnswerTypeFor(sq *qrpb.GameQuestion, gs *qrpb.GameState) string {
+	switch sq.GetType() {
+	case qrpb.GQType_TEXT_ANSWER:
+		return "text"
+	case qrpb.GQType_PHOTO_PROOF:
+		if gs.GetPhotoPending() {
+			return "photopending"
 		}
+		return "photo"
 	}
-	return nil
+	return ""
+}
+
+// GetQuestionByIndex returns the question asked at level n to players who have not been given a
+// variant, which is its backup once that has been swapped in. Use GetQuestionForPlayer for a
+// particular player.
+func GetQuestionByIndex(sqs *qrpb.GameQSet, n int64) *qrpb.GameQuestion {
+	return GetQuestionVariant(sqs, n, "")
 }
 
 func GetSurveyQuestionByIndex(gopt *qrpb.SurveySet, n int64) *qrpb.SurveyQuestion {
@@ -0,0 +1,261 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/sushovande/qr-mixer-game/qrpb"
+
+	proto "google.golang.org/protobuf/proto"
+)
+
+// GAME_MODE_HUNT is the regular treasure hunt. An empty game_mode also means the hunt.
+const GAME_MODE_HUNT = "hunt"
+
+// GameMode is a set of rules for the game. Register an implementation with RegisterGameMode, and
+// organizers can then pick it with the game_mode field of the game configuration.
+type GameMode interface {
+	// InitialState returns the state of a player who has just registered.
+	InitialState(env *Env, username string) (*qrpb.GameState, error)
+	// Step returns the result of the player scanning the given QR code.
+	Step(env *Env, u *StateRow, answer string) (StepResponse, error)
+	// Clue returns what to show on the player's game page.
+	Clue(env *Env, u *StateRow) (ClueData, error)
+	// RanksAhead reports whether player a should be listed above player b on the admin leaderboard.
+	RanksAhead(a, b *DisplayUser) bool
+}
+
+// PlayerPreparer is an optional hook for a GameMode. Prepare is called after a player registers,
+// before their game page is shown, and after each of their moves, so the mode can bring the
+// player's state up to date, for example when the organizer switches modes partway through an
+// event. It returns true if u.State was changed and needs to be saved.
+type PlayerPreparer interface {
+	Prepare(env *Env, u *StateRow) (bool, error)
+}
+
+// FormAnswerer is an optional hook for a GameMode whose clues can be answered on the game page, by
+// typing the answer or sending a photo, instead of by scanning. The answer endpoints turn players
+// away when the current mode does not have it.
+type FormAnswerer interface {
+	// StepText returns the result of the player typing the given answer.
+	StepText(env *Env, u *StateRow, answer string) (StepResponse, error)
+	// StepPhoto returns the result of the player sending a photo.
+	StepPhoto(env *Env, u *StateRow) (StepResponse, error)
+}
+
+// ClueData is what the game page shows for the player's current state.
+type ClueData struct {
+	// HTML is the clue itself.
+	HTML string
+	// AnswerType tells the page how the clue should be answered, when it is not answered by
+	// scanning. See AnswerTypeFor.
+	AnswerType string
+}
+
+var gameModes = make(map[string]GameMode)
+
+// RegisterGameMode makes a game mode available under the given name. It is meant to be called
+// from an init function.
+func RegisterGameMode(name string, m GameMode) {
+	if _, dup := gameModes[name]; dup {
+		panic(fmt.Sprintf("game mode %q registered twice", name))
+	}
+	gameModes[name] = m
+}
+
+// LookupGameMode returns the game mode registered under the given name.
+func LookupGameMode(name string) (GameMode, bool) {
+	if len(name) == 0 {
+		name = GAME_MODE_HUNT
+	}
+	m, ok := gameModes[name]
+	return m, ok
+}
+
+// GameModeNames returns the names of every registered game mode, sorted.
+func GameModeNames() []string {
+	names := make([]string, 0, len(gameModes))
+	for n := range gameModes {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CurrentGameMode returns the game mode chosen in the game configuration.
+func (env *Env) CurrentGameMode() (GameMode, error) {
+	gc, err := env.cgo.GetGameConfig()
+	if err != nil {
+		return nil, err
+	}
+	m, ok := LookupGameMode(gc.GetGameMode())
+	if !ok {
+		return nil, fmt.Errorf("unknown game mode %q", gc.GetGameMode())
+	}
+	return m, nil
+}
+
+// preparePlayer runs the PlayerPreparer hook of the game mode, if it has one.
+func (env *Env) preparePlayer(m GameMode, u *StateRow) (bool, error) {
+	if p, ok := m.(PlayerPreparer); ok {
+		return p.Prepare(env, u)
+	}
+	return false, nil
+}
+
+// prepareAndSave runs preparePlayer and saves what it changed. Only the changed fields are written,
+// on the player's latest state, so a move saved while the page was being prepared is kept. u.State
+// is the saved state afterwards.
+func (env *Env) prepareAndSave(m GameMode, u *StateRow) error {
+	for i := 0; i < PATCH_STATE_RETRIES; i++ {
+		before := proto.Clone(u.State).(*qrpb.GameState)
+		changed, err := env.preparePlayer(m, u)
+		if err != nil || !changed {
+			return err
+		}
+		var latest *qrpb.GameState
+		saved, err := PatchUserState(env.db, u.Username, func(gs *qrpb.GameState) bool {
+			latest = gs
+			// Preparing for a level the player has since left would undo their move.
+			if gs.GetUserLevel() != before.GetUserLevel() {
+				return false
+			}
+			CopyChangedFields(gs, before, u.State)
+			return true
+		})
+		if err != nil || latest == nil {
+			return err
+		}
+		u.State = latest
+		if saved {
+			return nil
+		}
+	}
+	return fmt.Errorf("the state of %v kept changing, try again", u.Username)
+}
+
+// NewGameState returns the state every game mode starts a player with unless it needs something else.
+func (env *Env) NewGameState() (*qrpb.GameState, error) {
+	gc, err := env.cgo.GetGameConfig()
+	if err != nil {
+		return nil, err
+	}
+	return &qrpb.GameState{
+		Life:      proto.Int64(StartingLife(gc)),
+		UserLevel: proto.Int64(StartingLevel(gc)),
+	}, nil
+}
+
+// huntMode is the regular treasure hunt of numbered questions.
+type huntMode struct{}
+
+func init() {
+	RegisterGameMode(GAME_MODE_HUNT, huntMode{})
+}
+
+func (huntMode) InitialState(env *Env, username string) (*qrpb.GameState, error) {
+	return env.NewGameState()
+}
+
+func (m huntMode) Step(env *Env, u *StateRow, answer string) (StepResponse, error) {
+	gc, err := env.cgo.GetGameConfig()
+	if err != nil {
+		return StepResponse{}, err
+	}
+	if PlaysPoker(m, gc, u.State) {
+		return env.StepPoker(u, answer)
+	}
+	h, err := env.HandicapFor(u.Username)
+	if err != nil {
+		return StepResponse{}, err
+	}
+	return env.StepWithHandicap(u.State, answer, h)
+}
+
+func (huntMode) StepText(env *Env, u *StateRow, answer string) (StepResponse, error) {
+	h, err := env.HandicapFor(u.Username)
+	if err != nil {
+		return StepResponse{}, err
+	}
+	return env.StepTextWithHandicap(u.State, answer, h)
+}
+
+func (huntMode) StepPhoto(env *Env, u *StateRow) (StepResponse, error) {
+	return env.StepPhotoUpload(u.State)
+}
+
+func (m huntMode) Clue(env *Env, u *StateRow) (ClueData, error) {
+	gc, err := env.cgo.GetGameConfig()
+	if err != nil {
+		return ClueData{}, err
+	}
+	if PlaysPoker(m, gc, u.State) {
+		clue, err := env.RenderPokerHand(u.State)
+		return ClueData{HTML: clue}, err
+	}
+	sqs, err := env.cgo.GetGameQSet()
+	if err != nil {
+		return ClueData{}, err
+	}
+	qrm, err := env.cgo.GetQRMappings()
+	if err != nil {
+		return ClueData{}, err
+	}
+
+	qn := GetQuestionForPlayer(sqs, u.State)
+	if clue, ok, err := env.huntStateClue(u.State, qn); ok || err != nil {
+		return clue, err
+	}
+	if t, locked := QuestionLockedUntil(qn, time.Now()); locked {
+		return ClueData{HTML: LockedClueHTML(t)}, nil
+	}
+	qhtml, err := env.PersonalizeQuestion(u, qn.GetQuestionHtml())
+	if err != nil {
+		return ClueData{}, err
+	}
+	return ClueData{
+		HTML:       qhtml + RouteProgressHTML(qn, u.State, qrm) + BuddyClueHTML(qn, u.State) + HintsHTML(qn, u.State),
+		AnswerType: AnswerTypeFor(qn, u.State),
+	}, nil
+}
+
+func (huntMode) RanksAhead(a, b *DisplayUser) bool {
+	if a.Level != b.Level {
+		return a.Level > b.Level
+	}
+	// Only players in the poker endgame hold cards.
+	if a.PokerScore != b.PokerScore {
+		return a.PokerScore > b.PokerScore
+	}
+	return a.Points > b.Points
+}
+
+// Prepare gives the player a variant of their question when the level has several, picks a secret
+// buddy when the player reaches a SECRET_BUDDY question, and reveals hints to players who are stuck.
+func (huntMode) Prepare(env *Env, u *StateRow) (bool, error) {
+	varied, err := env.MaybeAssignVariant(u)
+	if err != nil {
+		return false, err
+	}
+	assigned, err := env.MaybeAssignBuddy(u)
+	if err != nil {
+		return false, err
+	}
+	hinted, err := env.MaybeRevealHint(u, time.Now())
+	return varied || assigned || hinted, err
+}
@@ -0,0 +1,103 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sushovande/qr-mixer-game/qrpb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// countingMode is a game where every scan moves the player up a level.
+type countingMode struct{}
+
+func (countingMode) InitialState(env *Env, username string) (*qrpb.GameState, error) {
+	return &qrpb.GameState{Life: proto.Int64(1), UserLevel: proto.Int64(100)}, nil
+}
+
+func (countingMode) Step(env *Env, u *StateRow, answer string) (StepResponse, error) {
+	result := NewStepResponse()
+	result.newState = proto.Clone(u.State).(*qrpb.GameState)
+	result.newState.UserLevel = proto.Int64(u.State.GetUserLevel() + 1)
+	result.actionString = "Counted!"
+	result.actionResult = *qrpb.ActionLog_RESULT_PROGRESS.Enum()
+	return result, nil
+}
+
+func (countingMode) Clue(env *Env, u *StateRow) (ClueData, error) {
+	return ClueData{HTML: fmt.Sprintf("<p>Count: %v</p>", u.State.GetUserLevel())}, nil
+}
+
+func (countingMode) RanksAhead(a, b *DisplayUser) bool {
+	return a.Level < b.Level
+}
+
+func init() {
+	RegisterGameMode("counting-test", countingMode{})
+}
+
+func TestCustomGameMode(t *testing.T) {
+	env, err := createEnv(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer env.db.Close()
+	setupSynthetic(env.cgo, 4)
+
+	f := callController("POST", "/saveQuestions", "gameconfig="+`game_mode: "no-such-mode"`, nil, env.adminSaveQuestions)
+	if f.statuscode != http.StatusInternalServerError {
+		t.Errorf("expected an unknown mode to be rejected. got: %v %v", f.statuscode, f.resptext)
+	}
+	f = callController("POST", "/saveQuestions", "gameconfig="+`game_mode: "counting-test"`, nil, env.adminSaveQuestions)
+	if f.statuscode != http.StatusOK {
+		t.Fatalf("expected the registered mode to be accepted. got: %v %v", f.statuscode, f.resptext)
+	}
+
+	ck := http.Cookie{Name: "sid", Value: "cookie-1", Expires: time.Now().Add(24 * 30 * time.Hour)}
+	callController("POST", "/submitsurvey", "qr=qrcode-1&dqans1=true&dqans2=false", &ck, env.submitSurvey)
+	u1, _ := GetUserStateByUsername(env.db, "username-1")
+	if u1.State.GetUserLevel() != 100 {
+		t.Errorf("expected the mode's initial state. got: %v", u1.State)
+	}
+
+	f = callController("GET", "/game", "", &ck, env.gameHandler)
+	if !strings.Contains(f.resptext, "Count: 100") {
+		t.Errorf("expected the mode's clue. got: %v", f.resptext)
+	}
+
+	var mr MoveResponse
+	f = callController("POST", "/makemove", "answer=qrcode-2", &ck, env.makeMove)
+	json.Unmarshal([]byte(f.resptext), &mr)
+	if mr.GameArtifacts["action"] != "Counted!" || mr.PortHTML != "<p>Count: 101</p>" {
+		t.Errorf("expected the mode's step. got: %v", f.resptext)
+	}
+}
+
+func TestHuntModeRanking(t *testing.T) {
+	m, ok := LookupGameMode("")
+	if !ok {
+		t.Fatal("expected an empty mode to mean the hunt")
+	}
+	if !m.RanksAhead(&DisplayUser{Level: 5}, &DisplayUser{Level: 3}) {
+		t.Errorf("expected higher levels to rank ahead in the hunt")
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"database/sql"
 	_ "embed"
 	"log"
+	"sync"
 	"time"
 
 	"github.com/sushovande/qr-mixer-game/qrpb"
@@ -26,6 +27,7 @@ import (
 	"google.golang.org/protobuf/proto"
 )
 
+// CACHE_TTL_SEC is how long options are cached unless the game config says otherwise.
 const CACHE_TTL_SEC float64 = 60
 
 //go:embed default-data/survey_questions.textproto
@@ -41,8 +43,17 @@ type CachedGameOptions struct {
 	surveySet     *qrpb.SurveySet
 	gameQuestions *qrpb.GameQSet
 	qrMappings    *QRMappings
+	gameConfig    *qrpb.GameConfig
+	globalEvents  *qrpb.GlobalEventSet
 	lastUpdated   time.Time
 	db            *sql.DB
+	// qsetMu serializes changes to the game questions, so one change can't overwrite another.
+	qsetMu sync.Mutex
+}
+
+// cacheTTLSec returns the cache lifetime set in the cached game config.
+func (v *CachedGameOptions) cacheTTLSec() float64 {
+	return CacheTTLSec(v.gameConfig)
 }
 
 type nullableGameOptionsRow struct {
@@ -76,7 +87,9 @@ func MaybeCreateOptionsTable(db *sql.DB) error {
 	const populateStmt = `INSERT OR IGNORE INTO gameoptions VALUES
 		('surveyset', ?),
 		('gqset', ?),
-		('qrmap', ?)`
+		('qrmap', ?),
+		('gameconfig', ?),
+		('globalevents', ?)`
 
 	sp, err := getHardcodedSurveySet()
 	if err != nil {
@@ -108,7 +121,17 @@ func MaybeCreateOptionsTable(db *sql.DB) error {
 		return err
 	}
 
-	_, err = db.Exec(populateStmt, sm, gm, qm)
+	cm, err := proto.Marshal(&qrpb.GameConfig{})
+	if err != nil {
+		return err
+	}
+
+	em, err := proto.Marshal(&qrpb.GlobalEventSet{})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(populateStmt, sm, gm, qm, cm, em)
 	return err
 }
 
@@ -120,7 +143,7 @@ func CreateCachedGameOptions(db *sql.DB) *CachedGameOptions {
 
 func (v *CachedGameOptions) GetSurveySet() (*qrpb.SurveySet, error) {
 	if v.surveySet != nil {
-		if time.Since(v.lastUpdated).Seconds() < CACHE_TTL_SEC {
+		if time.Since(v.lastUpdated).Seconds() < v.cacheTTLSec() {
 			return v.surveySet, nil
 		}
 	}
@@ -185,7 +208,7 @@ func getHardcodedSurveySet() (*qrpb.SurveySet, error) {
 
 func (v *CachedGameOptions) GetGameQSet() (*qrpb.GameQSet, error) {
 	if v.gameQuestions != nil {
-		if time.Since(v.lastUpdated).Seconds() < CACHE_TTL_SEC {
+		if time.Since(v.lastUpdated).Seconds() < v.cacheTTLSec() {
 			return v.gameQuestions, nil
 		}
 	}
@@ -200,6 +223,33 @@ func (v *CachedGameOptions) GetGameQSet() (*qrpb.GameQSet, error) {
 }
 
 func (v *CachedGameOptions) SetGameQSet(qrgo *qrpb.GameQSet) error {
+	v.qsetMu.Lock()
+	defer v.qsetMu.Unlock()
+	return v.setGameQSetLocked(qrgo)
+}
+
+// UpdateGameQSet applies f to a copy of the saved game questions, and saves the copy if f returns
+// true. The questions are read from the db rather than the cache, and no other change to them can
+// happen in between.
+func (v *CachedGameOptions) UpdateGameQSet(f func(sqs *qrpb.GameQSet) (bool, error)) error {
+	v.qsetMu.Lock()
+	defer v.qsetMu.Unlock()
+	sqs, err := v.getGameQSetFromDB()
+	if err != nil {
+		return err
+	}
+	updated := &qrpb.GameQSet{}
+	if sqs != nil {
+		updated = proto.Clone(sqs).(*qrpb.GameQSet)
+	}
+	changed, err := f(updated)
+	if err != nil || !changed {
+		return err
+	}
+	return v.setGameQSetLocked(updated)
+}
+
+func (v *CachedGameOptions) setGameQSetLocked(qrgo *qrpb.GameQSet) error {
 	v.gameQuestions = qrgo
 	v.lastUpdated = time.Now()
 	return v.SetGameQSetToDB(qrgo)
@@ -250,7 +300,7 @@ func getHardcodedGameQSet() (*qrpb.GameQSet, error) {
 
 func (v *CachedGameOptions) GetQRMappings() (*QRMappings, error) {
 	if v.qrMappings != nil {
-		if time.Since(v.lastUpdated).Seconds() < CACHE_TTL_SEC {
+		if time.Since(v.lastUpdated).Seconds() < v.cacheTTLSec() {
 			return v.qrMappings, nil
 		}
 	}
@@ -315,6 +365,130 @@ func getHardcodedQRMappings() (*QRMappings, error) {
 	return &QRMappings{mappings: &qrmp}, nil
 }
 
+// GetGameConfig returns the game-wide settings. It never returns a nil config without an error.
+func (v *CachedGameOptions) GetGameConfig() (*qrpb.GameConfig, error) {
+	if v.gameConfig != nil {
+		if time.Since(v.lastUpdated).Seconds() < v.cacheTTLSec() {
+			return v.gameConfig, nil
+		}
+	}
+
+	// options is null or stale. Try DB next
+	gc, err := v.getGameConfigFromDB()
+	if err != nil {
+		return nil, err
+	}
+	if gc == nil {
+		gc = &qrpb.GameConfig{}
+	}
+	v.gameConfig = gc
+	v.lastUpdated = time.Now()
+	return gc, nil
+}
+
+func (v *CachedGameOptions) SetGameConfig(gc *qrpb.GameConfig) error {
+	v.gameConfig = gc
+	v.lastUpdated = time.Now()
+	return v.setGameConfigToDB(gc)
+}
+
+func (v *CachedGameOptions) getGameConfigFromDB() (*qrpb.GameConfig, error) {
+	const getStmt = `SELECT key, value FROM gameoptions WHERE key='gameconfig'`
+	rows, err := v.db.Query(getStmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	if rows.Next() {
+		var r nullableGameOptionsRow
+		if err := rows.Scan(&r.Key, &r.Value); err != nil {
+			return nil, err
+		}
+		if len(r.Value) == 0 {
+			return nil, nil
+		}
+		var gc qrpb.GameConfig
+		if err := proto.Unmarshal(r.Value, &gc); err != nil {
+			return nil, err
+		}
+		return &gc, nil
+	}
+	return nil, nil
+}
+
+func (v *CachedGameOptions) setGameConfigToDB(gc *qrpb.GameConfig) error {
+	const upsertStmt = `
+		INSERT OR REPLACE INTO gameoptions VALUES('gameconfig', ?)`
+	sqlgo, err := proto.Marshal(gc)
+	if err != nil {
+		return err
+	}
+	_, err = v.db.Exec(upsertStmt, sqlgo)
+	return err
+}
+
+// GetGlobalEvents returns every event the organizers have started, including ones that are over.
+func (v *CachedGameOptions) GetGlobalEvents() (*qrpb.GlobalEventSet, error) {
+	if v.globalEvents != nil {
+		if time.Since(v.lastUpdated).Seconds() < v.cacheTTLSec() {
+			return v.globalEvents, nil
+		}
+	}
+
+	// options is null or stale. Try DB next
+	es, err := v.getGlobalEventsFromDB()
+	if err != nil {
+		return nil, err
+	}
+	if es == nil {
+		es = &qrpb.GlobalEventSet{}
+	}
+	v.globalEvents = es
+	v.lastUpdated = time.Now()
+	return es, nil
+}
+
+func (v *CachedGameOptions) SetGlobalEvents(es *qrpb.GlobalEventSet) error {
+	v.globalEvents = es
+	v.lastUpdated = time.Now()
+	return v.setGlobalEventsToDB(es)
+}
+
+func (v *CachedGameOptions) getGlobalEventsFromDB() (*qrpb.GlobalEventSet, error) {
+	const getStmt = `SELECT key, value FROM gameoptions WHERE key='globalevents'`
+	rows, err := v.db.Query(getStmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	if rows.Next() {
+		var r nullableGameOptionsRow
+		if err := rows.Scan(&r.Key, &r.Value); err != nil {
+			return nil, err
+		}
+		if len(r.Value) == 0 {
+			return nil, nil
+		}
+		var es qrpb.GlobalEventSet
+		if err := proto.Unmarshal(r.Value, &es); err != nil {
+			return nil, err
+		}
+		return &es, nil
+	}
+	return nil, nil
+}
+
+func (v *CachedGameOptions) setGlobalEventsToDB(es *qrpb.GlobalEventSet) error {
+	const upsertStmt = `
+		INSERT OR REPLACE INTO gameoptions VALUES('globalevents', ?)`
+	sqlgo, err := proto.Marshal(es)
+	if err != nil {
+		return err
+	}
+	_, err = v.db.Exec(upsertStmt, sqlgo)
+	return err
+}
+
 // RefreshMappings sets up the maps so that lookups can work.
 // Call this every time `mappings` is set.
 func (mp *QRMappings) RefreshMappings() {
@@ -15,6 +15,7 @@
 package main
 
 import (
+	"sync"
 	"testing"
 	"time"
 
@@ -137,6 +138,44 @@ func TestGameQSet(t *testing.T) {
 	}
 }
 
+func TestUpdateGameQSet(t *testing.T) {
+	db, _ := DbInit(":memory:")
+	defer db.Close()
+	cgo := CreateCachedGameOptions(db)
+	sqs, _ := cgo.GetGameQSet()
+	before := len(sqs.GetGameQuestions())
+
+	// Each change is made on the latest questions, so none of them is lost.
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			cgo.UpdateGameQSet(func(sqs *qrpb.GameQSet) (bool, error) {
+				sqs.GameQuestions = append(sqs.GameQuestions, &qrpb.GameQuestion{QuestionId: proto.Int64(int64(100 + i))})
+				return true, nil
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	sqs, _ = cgo.getGameQSetFromDB()
+	if len(sqs.GetGameQuestions()) != before+10 {
+		t.Errorf("expected all 10 changes to be saved. got: %v questions, had %v", len(sqs.GetGameQuestions()), before)
+	}
+
+	if err := cgo.UpdateGameQSet(func(sqs *qrpb.GameQSet) (bool, error) {
+		sqs.GameQuestions = nil
+		return false, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	sqs, _ = cgo.GetGameQSet()
+	if len(sqs.GetGameQuestions()) != before+10 {
+		t.Errorf("expected an unchanged update not to be saved. got: %v", len(sqs.GetGameQuestions()))
+	}
+}
+
 func TestSQAndGOTogether(t *testing.T) {
 	db, _ := DbInit(":memory:")
 	defer db.Close()
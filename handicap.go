@@ -0,0 +1,193 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/sushovande/qr-mixer-game/common"
+	"github.com/sushovande/qr-mixer-game/qrpb"
+
+	proto "google.golang.org/protobuf/proto"
+)
+
+// HandicapFor returns the handicap the roster gives the player, or nil if they have none.
+func (env *Env) HandicapFor(username string) (*qrpb.PlayerHandicap, error) {
+	qrm, err := env.cgo.GetQRMappings()
+	if err != nil {
+		return nil, err
+	}
+	return qrm.LookupByUsername(username).GetHandicap(), nil
+}
+
+// ApplyStartingHandicap gives a newly registered player their extra lives.
+func ApplyStartingHandicap(gs *qrpb.GameState, h *qrpb.PlayerHandicap) {
+	if h.GetExtraLives() > 0 {
+		gs.Life = proto.Int64(gs.GetLife() + h.GetExtraLives())
+	}
+}
+
+// ApplyHandicap returns the answer rules as changed by the player's handicap.
+func ApplyHandicap(rules AnswerRules, h *qrpb.PlayerHandicap) AnswerRules {
+	if h.GetNoPenalty() {
+		rules.Penalty = 0
+	}
+	return rules
+}
+
+// HelpedPlayers returns the players whose handicap names helper as their helper.
+func HelpedPlayers(qrm *QRMappings, helper string) []*qrpb.QRMapping {
+	helped := make([]*qrpb.QRMapping, 0)
+	for _, m := range qrm.mappings.GetQrMappings() {
+		if h := m.GetHandicap(); h.GetHelper() == helper && h.GetHelperScans() > 0 {
+			helped = append(helped, m)
+		}
+	}
+	return helped
+}
+
+// helpScan is the backend for a helper scanning a badge for a player they help. The scan is made
+// in the player's game, by the rules of the game mode, and the helper's page stays on their own
+// clue.
+func (env *Env) helpScan(w http.ResponseWriter, r *http.Request) {
+	log.Println("Req: ", r.URL)
+	ck, err := r.Cookie("sid")
+	if err != nil {
+		common.RespondHTTP401(w)
+		return
+	}
+
+	u, err := GetUserStateByCookie(env.db, ck.Value)
+	if err != nil || u == nil {
+		common.RespondHTTP401(w)
+		return
+	}
+
+	a := r.FormValue("answer")
+	helped := r.FormValue("for")
+	if len(a) == 0 || len(helped) == 0 {
+		http.NotFound(w, r)
+		return
+	}
+
+	qrm, err := env.cgo.GetQRMappings()
+	if common.Should500(err, w, "Could not fetch the qr code mappings") {
+		return
+	}
+	m := qrm.LookupByUsername(helped)
+	if m == nil || m.GetHandicap().GetHelper() != u.Username {
+		common.Should500(fmt.Errorf("%v is not a helper of %v", u.Username, helped), w, "You are not helping that player.")
+		return
+	}
+	pu, err := GetUserStateByUsername(env.db, helped)
+	if common.Should500(err, w, "could not find the player you are helping") {
+		return
+	}
+	if pu == nil {
+		common.Should500(fmt.Errorf("%v has not registered", helped), w, "The player you are helping has not started the game yet.")
+		return
+	}
+	name := m.GetDisplayName()
+	if len(name) == 0 {
+		name = helped
+	}
+	if pu.State.GetHelperScansUsed() >= m.GetHandicap().GetHelperScans() {
+		env.respondWithClue(w, u, fmt.Sprintf("No scans left for %v", name))
+		return
+	}
+
+	mode, err := env.CurrentGameMode()
+	if common.Should500(err, w, "There was a problem figuring out the game, maybe try again?") {
+		return
+	}
+	stepResult, err := mode.Step(env, pu, a)
+	if err != nil {
+		common.Should500(err, w, fmt.Sprintf("You scanned someone unexpected: %v", err.Error()))
+		return
+	}
+	action := fmt.Sprintf("For %v: %v", name, stepResult.actionString)
+	if !helperScanCounts(stepResult.actionResult) {
+		env.respondWithClue(w, u, action)
+		return
+	}
+	stepResult.newState.HelperScansUsed = proto.Int64(pu.State.GetHelperScansUsed() + 1)
+	if common.Should500(env.saveStep(pu, stepResult, qrpb.ActionLog_ACTION_HELPER_SCAN), w, "could not record the scan, please try again") {
+		return
+	}
+	// Set up the player's next clue, as their own page would after a move.
+	if common.Should500(env.prepareAndSave(mode, pu), w, "could not set up the next clue of the player you are helping") {
+		return
+	}
+	env.respondWithClue(w, u, action)
+}
+
+// helperScanCounts returns whether a helper's scan with this result is used up. Scans that change
+// nothing for the player, like one after the game is over, are free.
+func helperScanCounts(r qrpb.ActionLog_ActionResult) bool {
+	switch r {
+	case qrpb.ActionLog_RESULT_UNSPECIFIED, qrpb.ActionLog_RESULT_IGNORED,
+		qrpb.ActionLog_RESULT_ALREADY_VICTORIOUS, qrpb.ActionLog_RESULT_ALREADY_DEAD:
+		return false
+	}
+	return true
+}
+
+// DescribeHandicap summarizes the handicap for the admin pages. It is empty if there is none.
+func DescribeHandicap(h *qrpb.PlayerHandicap, gs *qrpb.GameState) string {
+	parts := make([]string, 0)
+	if h.GetExtraLives() > 0 {
+		parts = append(parts, fmt.Sprintf("+%v lives", h.GetExtraLives()))
+	}
+	if h.GetNoPenalty() {
+		parts = append(parts, "no penalty")
+	}
+	if len(h.GetHelper()) > 0 && h.GetHelperScans() > 0 {
+		parts = append(parts, fmt.Sprintf("helper %v (%v/%v used)", h.GetHelper(), gs.GetHelperScansUsed(), h.GetHelperScans()))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// CheckHandicaps returns an error if a handicap in the roster makes no sense.
+func CheckHandicaps(qrset *qrpb.QRMappingSet) error {
+	usernames := make(map[string]bool)
+	for _, m := range qrset.GetQrMappings() {
+		usernames[m.GetUsername()] = true
+	}
+	for _, m := range qrset.GetQrMappings() {
+		h := m.GetHandicap()
+		if h.GetExtraLives() < 0 {
+			return fmt.Errorf("%v: extra lives cannot be negative", m.GetUsername())
+		}
+		if h.GetHelperScans() < 0 {
+			return fmt.Errorf("%v: helper scans cannot be negative", m.GetUsername())
+		}
+		if len(h.GetHelper()) == 0 {
+			if h.GetHelperScans() > 0 {
+				return fmt.Errorf("%v: helper scans need a helper", m.GetUsername())
+			}
+			continue
+		}
+		if h.GetHelper() == m.GetUsername() {
+			return fmt.Errorf("%v: a player cannot be their own helper", m.GetUsername())
+		}
+		if !usernames[h.GetHelper()] {
+			return fmt.Errorf("%v: helper %q is not in the roster", m.GetUsername(), h.GetHelper())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/sushovande/qr-mixer-game/qrpb"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCheckHandicaps(t *testing.T) {
+	roster := func(h *qrpb.PlayerHandicap) *qrpb.QRMappingSet {
+		return &qrpb.QRMappingSet{QrMappings: []*qrpb.QRMapping{
+			{Username: proto.String("alice"), Handicap: h},
+			{Username: proto.String("bob")},
+		}}
+	}
+	if err := CheckHandicaps(roster(&qrpb.PlayerHandicap{ExtraLives: proto.Int64(2), Helper: proto.String("bob"), HelperScans: proto.Int64(3)})); err != nil {
+		t.Errorf("expected a valid handicap. got: %v", err)
+	}
+	bad := []*qrpb.PlayerHandicap{
+		{ExtraLives: proto.Int64(-1)},
+		{HelperScans: proto.Int64(2)},
+		{Helper: proto.String("alice"), HelperScans: proto.Int64(1)},
+		{Helper: proto.String("carol"), HelperScans: proto.Int64(1)},
+	}
+	for _, h := range bad {
+		if CheckHandicaps(roster(h)) == nil {
+			t.Errorf("expected %v to be rejected", h)
+		}
+	}
+}
+
+func TestHandicapsInGame(t *testing.T) {
+	env, err := createEnv(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer env.db.Close()
+	setupSynthetic(env.cgo, 10)
+	qrm, _ := env.cgo.GetQRMappings()
+	qrm.LookupByUsername("username-1").Handicap = &qrpb.PlayerHandicap{
+		ExtraLives:  proto.Int64(2),
+		NoPenalty:   proto.Bool(true),
+		Helper:      proto.String("username-9"),
+		HelperScans: proto.Int64(1),
+	}
+	js, _ := protojson.Marshal(qrm.mappings)
+	f := callController("POST", "/saveUserQrMapping", "users="+url.QueryEscape(string(js)), nil, env.adminSaveUserQrMapping)
+	if f.statuscode != http.StatusOK {
+		t.Fatalf("expected the roster to be saved. got: %v", f.resptext)
+	}
+
+	postData := fmt.Sprintf("qr=%v&dqans1=true&dqans2=false", url.QueryEscape("qrcode-1"))
+	ck := http.Cookie{Name: "sid", Value: "foo-foo"}
+	callController("POST", "/submitsurvey", postData, &ck, env.submitSurvey)
+	u1, _ := GetUserStateByCookie(env.db, "foo-foo")
+	if u1.State.GetLife() != STARTING_LIFE+2 {
+		t.Errorf("expected two extra lives. got: %v", u1.State)
+	}
+
+	mr, _ := huntMode{}.Step(env, u1, "qrcode-5")
+	if mr.actionString != "Not Quite!" || mr.newState.GetLife() != STARTING_LIFE+2 {
+		t.Errorf("expected no penalty. got: %v %v", mr.actionString, mr.newState)
+	}
+	// Other players play by the usual rules.
+	mr, _ = huntMode{}.Step(env, GetSyntheticStateRow(2, 1), "qrcode-9")
+	if mr.actionString != "Lost a Life!" {
+		t.Errorf("expected other players to lose a life. got: %v", mr.actionString)
+	}
+
+	// Scanning the helper's badge is just a wrong answer.
+	mr, _ = huntMode{}.Step(env, u1, "qrcode-9")
+	if mr.actionString != "Not Quite!" || mr.newState.GetUserLevel() != 1 {
+		t.Errorf("expected the helper's badge to be an ordinary scan. got: %v %v", mr.actionString, mr.newState)
+	}
+
+	f = callController("GET", "/allUsers", "", nil, env.adminAllUsers)
+	if !strings.Contains(f.resptext, "2 lives, no penalty, helper username-9 (0/1 used)") {
+		t.Errorf("expected the handicap on the admin page. got: %v", f.resptext)
+	}
+}
+
+func TestHelperScansForPlayer(t *testing.T) {
+	env, err := createEnv(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer env.db.Close()
+	setupSynthetic(env.cgo, 10)
+	qrm, _ := env.cgo.GetQRMappings()
+	qrm.LookupByUsername("username-1").Handicap = &qrpb.PlayerHandicap{
+		Helper:      proto.String("username-9"),
+		HelperScans: proto.Int64(1),
+	}
+	sqs, _ := env.cgo.GetGameQSet()
+	sqs.GameQuestions[1].Type = qrpb.GQType_SECRET_BUDDY.Enum()
+	env.cgo.SetGameQSet(sqs)
+
+	ck1 := http.Cookie{Name: "sid", Value: "cookie-1"}
+	ck9 := http.Cookie{Name: "sid", Value: "cookie-9"}
+	ck5 := http.Cookie{Name: "sid", Value: "cookie-5"}
+	callController("POST", "/submitsurvey", "qr=qrcode-1&dqans1=true&dqans2=false", &ck1, env.submitSurvey)
+	callController("POST", "/submitsurvey", "qr=qrcode-9&dqans1=true&dqans2=false", &ck9, env.submitSurvey)
+	callController("POST", "/submitsurvey", "qr=qrcode-5&dqans1=true&dqans2=false", &ck5, env.submitSurvey)
+
+	f := callController("GET", "/game", "", &ck9, env.gameHandler)
+	if !strings.Contains(f.resptext, `<option value="username-1">name-1</option>`) {
+		t.Errorf("expected the helper to be able to scan for name-1. got: %v", f.resptext)
+	}
+
+	// Only the named helper can scan for the player.
+	f = callController("POST", "/helpscan", "answer=qrcode-1&for=username-1", &ck5, env.helpScan)
+	if f.statuscode == http.StatusOK {
+		t.Errorf("expected someone else's help to be refused. got: %v", f.resptext)
+	}
+
+	f = callController("POST", "/helpscan", "answer=qrcode-1&for=username-1", &ck9, env.helpScan)
+	var mr MoveResponse
+	if err := json.Unmarshal([]byte(f.resptext), &mr); err != nil {
+		t.Fatalf("%v: %v", err, f.resptext)
+	}
+	if mr.GameArtifacts["action"] != "For name-1: Correct!" {
+		t.Errorf("expected the scan to count for the player. got: %v", mr.GameArtifacts)
+	}
+	u1, _ := GetUserStateByCookie(env.db, "cookie-1")
+	if u1.State.GetUserLevel() != 2 || u1.State.GetHelperScansUsed() != 1 || BuddyFor(u1.State, 2) == nil {
+		t.Errorf("expected the player to move on, with a buddy for the new level. got: %v", u1.State)
+	}
+	u9, _ := GetUserStateByCookie(env.db, "cookie-9")
+	if u9.State.GetUserLevel() != 1 || mr.State.GetUserLevel() != 1 {
+		t.Errorf("expected the helper's own game to be untouched. got: %v %v", u9.State, mr.State)
+	}
+	logs, _ := GetAllActionLogs(env.db)
+	if len(logs) != 1 || logs[0].Username != "username-1" || logs[0].GameLog.GetType() != qrpb.ActionLog_ACTION_HELPER_SCAN {
+		t.Errorf("expected a helper scan in the player's log. got: %v", logs)
+	}
+
+	f = callController("POST", "/helpscan", "answer=qrcode-2&for=username-1", &ck9, env.helpScan)
+	json.Unmarshal([]byte(f.resptext), &mr)
+	u1, _ = GetUserStateByCookie(env.db, "cookie-1")
+	if mr.GameArtifacts["action"] != "No scans left for name-1" || u1.State.GetUserLevel() != 2 {
+		t.Errorf("expected the helper scans to run out. got: %v %v", mr.GameArtifacts, u1.State)
+	}
+
+	// A scan that changes nothing for the player is not used up.
+	u1.State.HelperScansUsed = proto.Int64(0)
+	u1.State.Life = proto.Int64(0)
+	UpdateUserDetails(env.db, u1)
+	f = callController("POST", "/helpscan", "answer=qrcode-2&for=username-1", &ck9, env.helpScan)
+	json.Unmarshal([]byte(f.resptext), &mr)
+	u1, _ = GetUserStateByCookie(env.db, "cookie-1")
+	if mr.GameArtifacts["action"] != "For name-1: Already Dead!" || u1.State.GetHelperScansUsed() != 0 {
+		t.Errorf("expected the scan to be free. got: %v %v", mr.GameArtifacts, u1.State)
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"log"
+	"sort"
+
+	"github.com/sushovande/qr-mixer-game/qrpb"
+)
+
+// QUESTION_MISSING_ACTION is what a player sees when they answer a question that is missing or
+// broken. That is the organizer's mistake, so it never costs a life.
+const QUESTION_MISSING_ACTION = "This question isn't ready yet!"
+
+// IsDead reports whether the player has run out of lives.
+func IsDead(gs *qrpb.GameState) bool {
+	return gs.GetUserLevel() < 0 || gs.GetLife() <= 0
+}
+
+// HasWon reports whether the player has finished the treasure hunt.
+func HasWon(gs *qrpb.GameState) bool {
+	return gs.GetUserLevel() == HUNT_VICTORY_LEVEL
+}
+
+// QuestionProblem returns why nobody can answer the question, or an empty string if it is fine.
+func QuestionProblem(sq *qrpb.GameQuestion, ss *qrpb.SurveySet) string {
+	if sq == nil {
+		return "there is no question for this level"
+	}
+	switch sq.GetType() {
+	case qrpb.GQType_GQTYPE_UNSPECIFIED:
+		return "the question has no type"
+	case qrpb.GQType_USERNAME_LIST:
+		if len(sq.GetAnsUsernames()) == 0 {
+			return "the question has no ans_usernames"
+		}
+	case qrpb.GQType_SURVEY_ANS:
+		if GetSurveyQuestionByIndex(ss, sq.GetSurveyId()) == nil {
+			return "the question's survey_id is not a survey question"
+		}
+	case qrpb.GQType_TEXT_ANSWER:
+		if len(sq.GetTextAnswers()) == 0 {
+			return "the question has no text_answers"
+		}
+	case qrpb.GQType_CHECKPOINT_ROUTE:
+		if len(sq.GetRouteUsernames()) == 0 {
+			return "the question has no route_usernames"
+		}
+	}
+	return ""
+}
+
+// StepTerminal fills in the result for a player who has already won or died, whose moves no longer
+// count. It returns true if they have.
+func StepTerminal(result *StepResponse, old *qrpb.GameState) bool {
+	if HasWon(old) {
+		result.actionString = "Already Victorious!"
+		result.actionResult = *qrpb.ActionLog_RESULT_ALREADY_VICTORIOUS.Enum()
+		return true
+	}
+	if IsDead(old) {
+		result.actionString = "Already Dead!"
+		result.actionResult = *qrpb.ActionLog_RESULT_ALREADY_DEAD.Enum()
+		return true
+	}
+	return false
+}
+
+// StepMisconfigured ignores the move if the player's question cannot be answered. It returns true
+// if it did.
+func StepMisconfigured(result *StepResponse, sq *qrpb.GameQuestion, ss *qrpb.SurveySet, level int64) bool {
+	problem := QuestionProblem(sq, ss)
+	if len(problem) == 0 {
+		return false
+	}
+	log.Printf("level %v is misconfigured: %v", level, problem)
+	result.actionString = QUESTION_MISSING_ACTION
+	result.actionResult = *qrpb.ActionLog_RESULT_IGNORED.Enum()
+	return true
+}
+
+// huntStateClue returns the page for a player who has died, won, or is stuck on a question that
+// cannot be answered. ok is false if the player is playing normally.
+func (env *Env) huntStateClue(gs *qrpb.GameState, sq *qrpb.GameQuestion) (clue ClueData, ok bool, err error) {
+	var name string
+	switch {
+	case HasWon(gs):
+		// Organizers usually write their own ending as the last question.
+		if len(sq.GetQuestionHtml()) > 0 {
+			return ClueData{}, false, nil
+		}
+		name = "victory.html"
+	case IsDead(gs):
+		name = "gameover.html"
+	default:
+		ss, err := env.cgo.GetSurveySet()
+		if err != nil {
+			return ClueData{}, false, err
+		}
+		if len(QuestionProblem(sq, ss)) == 0 {
+			return ClueData{}, false, nil
+		}
+		name = "questionmissing.html"
+	}
+	var buf bytes.Buffer
+	if err := env.tem.ExecuteTemplate(&buf, name, gs); err != nil {
+		return ClueData{}, false, err
+	}
+	return ClueData{HTML: buf.String()}, true, nil
+}
+
+// LevelAlert is a level that players have reached but cannot answer.
+type LevelAlert struct {
+	Level   int64
+	Problem string
+	Players int
+}
+
+// MisconfiguredLevels returns the levels of the treasure hunt that someone is stuck on because the
+// question is missing or broken, lowest first.
+func MisconfiguredLevels(srs []StateRow, sqs *qrpb.GameQSet, ss *qrpb.SurveySet) []LevelAlert {
+	stuck := make(map[int64]int)
+	problems := make(map[int64]string)
+	for _, sr := range srs {
+		if HasWon(sr.State) || IsDead(sr.State) {
+			continue
+		}
+		level := sr.State.GetUserLevel()
+		if p := QuestionProblem(GetQuestionForPlayer(sqs, sr.State), ss); len(p) > 0 {
+			stuck[level]++
+			problems[level] = p
+		}
+	}
+	alerts := make([]LevelAlert, 0)
+	for level, n := range stuck {
+		alerts = append(alerts, LevelAlert{Level: level, Problem: problems[level], Players: n})
+	}
+	sort.Slice(alerts, func(i, j int) bool { return alerts[i].Level < alerts[j].Level })
+	return alerts
+}
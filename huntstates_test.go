@@ -0,0 +1,133 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/sushovande/qr-mixer-game/qrpb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestMissingQuestions(t *testing.T) {
+	env, err := createEnv(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer env.db.Close()
+	setupSynthetic(env.cgo, 10)
+	sqs, _ := env.cgo.GetGameQSet()
+	// Q4 is gone, and Q5 asks about a survey question that does not exist.
+	sqs.GameQuestions = append(sqs.GameQuestions[:3], sqs.GameQuestions[4:]...)
+	GetQuestionByIndex(sqs, 5).SurveyId = proto.Int64(9)
+	env.cgo.SetGameQSet(sqs)
+
+	u4 := GetSyntheticStateRow(4, 4)
+	mr, err := env.Step(u4.State, "qrcode-9")
+	if err != nil || mr.actionString != QUESTION_MISSING_ACTION || mr.newState.GetLife() != 3 {
+		t.Errorf("expected a scan on a missing question to be ignored. got: %v %v %v", mr.actionString, mr.newState, err)
+	}
+	mr, _ = env.StepText(u4.State, "anything")
+	if mr.actionString != QUESTION_MISSING_ACTION || mr.newState.GetLife() != 3 {
+		t.Errorf("expected a typed answer on a missing question to be ignored. got: %v %v", mr.actionString, mr.newState)
+	}
+	u5 := GetSyntheticStateRow(5, 5)
+	mr, _ = env.Step(u5.State, "qrcode-1")
+	if mr.actionString != QUESTION_MISSING_ACTION || mr.newState.GetLife() != 3 {
+		t.Errorf("expected a broken survey question to be ignored. got: %v %v", mr.actionString, mr.newState)
+	}
+
+	AddUser(env.db, u4)
+	AddUser(env.db, u5)
+	AddUser(env.db, GetSyntheticStateRow(6, 4))
+	AddUser(env.db, GetSyntheticStateRow(1, 2))
+	ck := http.Cookie{Name: "sid", Value: u4.Cookie}
+	f := callController("GET", "/game", "", &ck, env.gameHandler)
+	if !strings.Contains(f.resptext, "still setting up this question") {
+		t.Errorf("expected the missing question page. got: %v", f.resptext)
+	}
+
+	f = callController("GET", "/allUsers", "", nil, env.adminAllUsers)
+	if !strings.Contains(f.resptext, "Question 4 is misconfigured: there is no question for this level. 2 players") ||
+		!strings.Contains(f.resptext, "Question 5 is misconfigured") || strings.Contains(f.resptext, "Question 2 is misconfigured") {
+		t.Errorf("expected alerts for questions 4 and 5 only. got: %v", f.resptext)
+	}
+}
+
+func TestTerminalStates(t *testing.T) {
+	env, err := createEnv(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer env.db.Close()
+	setupSynthetic(env.cgo, 30)
+
+	dead := GetSyntheticStateRow(1, -1)
+	dead.State.Life = proto.Int64(0)
+	mr, err := env.Step(dead.State, "qrcode-1")
+	if err != nil || mr.actionString != "Already Dead!" || mr.newState.GetUserLevel() != -1 {
+		t.Errorf("expected the scan of a dead player not to count. got: %v %v %v", mr.actionString, mr.newState, err)
+	}
+	mr, _ = env.StepText(dead.State, "anything")
+	if mr.actionString != "Already Dead!" {
+		t.Errorf("expected the answer of a dead player not to count. got: %v", mr.actionString)
+	}
+	AddUser(env.db, dead)
+	ck := http.Cookie{Name: "sid", Value: dead.Cookie}
+	f := callController("GET", "/game", "", &ck, env.gameHandler)
+	if !strings.Contains(f.resptext, "Game over!") {
+		t.Errorf("expected the game over page. got: %v", f.resptext)
+	}
+
+	won := GetSyntheticStateRow(2, HUNT_VICTORY_LEVEL)
+	AddUser(env.db, won)
+	ck = http.Cookie{Name: "sid", Value: won.Cookie}
+	f = callController("GET", "/game", "", &ck, env.gameHandler)
+	if !strings.Contains(f.resptext, "qHtml-22") {
+		t.Errorf("expected the organizer's last question to be the ending. got: %v", f.resptext)
+	}
+	sqs, _ := env.cgo.GetGameQSet()
+	GetQuestionByIndex(sqs, HUNT_VICTORY_LEVEL).QuestionHtml = nil
+	env.cgo.SetGameQSet(sqs)
+	f = callController("GET", "/game", "", &ck, env.gameHandler)
+	if !strings.Contains(f.resptext, "You did it!") {
+		t.Errorf("expected the victory page. got: %v", f.resptext)
+	}
+}
+
+func TestQuestionProblem(t *testing.T) {
+	ss := &qrpb.SurveySet{SurveyQuestions: []*qrpb.SurveyQuestion{{QuestionId: proto.Int64(1)}}}
+	for _, tc := range []struct {
+		sq   *qrpb.GameQuestion
+		want bool
+	}{
+		{nil, true},
+		{&qrpb.GameQuestion{}, true},
+		{&qrpb.GameQuestion{Type: qrpb.GQType_USERNAME_LIST.Enum()}, true},
+		{&qrpb.GameQuestion{Type: qrpb.GQType_USERNAME_LIST.Enum(), AnsUsernames: []string{"a"}}, false},
+		{&qrpb.GameQuestion{Type: qrpb.GQType_SURVEY_ANS.Enum(), SurveyId: proto.Int64(1)}, false},
+		{&qrpb.GameQuestion{Type: qrpb.GQType_SURVEY_ANS.Enum(), SurveyId: proto.Int64(2)}, true},
+		{&qrpb.GameQuestion{Type: qrpb.GQType_TEXT_ANSWER.Enum()}, true},
+		{&qrpb.GameQuestion{Type: qrpb.GQType_ANY_PERSON.Enum()}, false},
+		{&qrpb.GameQuestion{Type: qrpb.GQType_PHOTO_PROOF.Enum()}, false},
+	} {
+		if got := len(QuestionProblem(tc.sq, ss)) > 0; got != tc.want {
+			t.Errorf("QuestionProblem(%v) = %v, want a problem: %v", tc.sq, QuestionProblem(tc.sq, ss), tc.want)
+		}
+	}
+}
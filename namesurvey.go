@@ -29,9 +29,6 @@ import (
 	proto "google.golang.org/protobuf/proto"
 )
 
-const STARTING_LEVEL int64 = 1
-const STARTING_LIFE int64 = 5
-
 // checkRegisteredBadge is the endpoint that checks the QR code scanned on the nocookie.html page
 // and if a valid badge is found, it redirects to the confirm name page. It does not set a cookie.
 func (env *Env) checkRegisteredBadge(w http.ResponseWriter, r *http.Request) {
@@ -132,7 +129,12 @@ func (env *Env) survey(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	expiration := time.Now().Add(cookieValidity)
+	gc, err := env.cgo.GetGameConfig()
+	if common.Should500(err, w, "Could not get the game settings") {
+		return
+	}
+
+	expiration := time.Now().Add(CookieValidity(gc))
 	cookie := http.Cookie{Name: "sid", Value: uuid.New().String(), Expires: expiration, HttpOnly: true}
 	http.SetCookie(w, &cookie)
 	common.RenderTemplate(w, env.tem, "survey.html", struct {
@@ -204,10 +206,12 @@ func (env *Env) submitSurvey(w http.ResponseWriter, r *http.Request) {
 	}
 	if sr != nil {
 		gu.Username = proto.String(sr.UserInfo.GetUsername())
+		gu.PresenceSecret = sr.UserInfo.PresenceSecret
 		sr.UserInfo = &gu
 		if common.Should500(UpdateUserDetails(env.GetDb(), sr), w, "could not update your survey details") {
 			return
 		}
+		env.checkSurveyCoverageAfterRegistration()
 		fmt.Fprint(w, "ok")
 		return
 	}
@@ -227,18 +231,45 @@ func (env *Env) submitSurvey(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Now we know this user definitely does not exist. So we add a new entry.
+	mode, err := env.CurrentGameMode()
+	if common.Should500(err, w, "Could not get the game settings") {
+		return
+	}
+	gs, err := mode.InitialState(env, gu.GetUsername())
+	if common.Should500(err, w, "Could not set up your game") {
+		return
+	}
+	h, err := env.HandicapFor(gu.GetUsername())
+	if common.Should500(err, w, "Could not set up your game") {
+		return
+	}
+	ApplyStartingHandicap(gs, h)
+	secret, err := NewPresenceSecret()
+	if common.Should500(err, w, "Could not set up your game") {
+		return
+	}
+	gu.PresenceSecret = proto.String(secret)
 	sr = &StateRow{
 		Cookie:   ck.Value,
 		Username: gu.GetUsername(),
 		UserInfo: &gu,
-		State: &qrpb.GameState{
-			Life:      proto.Int64(STARTING_LIFE),
-			UserLevel: proto.Int64(STARTING_LEVEL),
-		},
+		State:    gs,
 	}
 	if common.Should500(AddUser(env.GetDb(), sr), w, "could not add the user") {
 		return
 	}
+	if common.Should500(env.prepareAndSave(mode, sr), w, "Could not set up your game") {
+		return
+	}
+	env.checkSurveyCoverageAfterRegistration()
 
 	fmt.Fprint(w, "ok")
 }
+
+// checkSurveyCoverageAfterRegistration runs CheckSurveyCoverage for a new set of survey answers. A
+// failure is only logged, since the player is registered either way.
+func (env *Env) checkSurveyCoverageAfterRegistration() {
+	if _, err := env.CheckSurveyCoverage(); err != nil {
+		log.Printf("could not check the survey coverage: %v", err)
+	}
+}
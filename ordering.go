@@ -0,0 +1,384 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sushovande/qr-mixer-game/common"
+	"github.com/sushovande/qr-mixer-game/qrpb"
+
+	proto "google.golang.org/protobuf/proto"
+)
+
+// ORDER_LAST_LEVEL is the last question that can be moved. The ones after it are the endgame.
+const ORDER_LAST_LEVEL = 19
+
+// ORDER_MIN_ATTEMPTS is how many logged answers a question needs before its solve rate counts.
+const ORDER_MIN_ATTEMPTS = 5
+
+// QuestionDifficulty is how hard a question looks, from what the ordering page knows about it.
+type QuestionDifficulty struct {
+	Level int64
+	HTML  template.HTML
+	// Answers is the number of registered players who are a right answer, or -1 if that does not
+	// depend on who registered.
+	Answers int
+	// Tag is the author's difficulty, or 0 if they gave none.
+	Tag int64
+	// SolveRate is the share of logged answers on this level that were right, or -1 if there are
+	// too few.
+	SolveRate float64
+	// Score goes from 0 for the easiest to 1 for the hardest.
+	Score float64
+	// Fixed questions stay where they are, because they have an unlock_time.
+	Fixed   bool
+	targets map[string]bool
+}
+
+// ScorePercent is the Score for display.
+func (qd QuestionDifficulty) ScorePercent() int {
+	return int(qd.Score*100 + 0.5)
+}
+
+// SolvePercent is the SolveRate for display.
+func (qd QuestionDifficulty) SolvePercent() int {
+	return int(qd.SolveRate*100 + 0.5)
+}
+
+// questionTargets returns the registered players who are a right answer to q, and false if the
+// answer is not a particular set of people.
+func questionTargets(q *qrpb.GameQuestion, srs []StateRow) (map[string]bool, bool) {
+	targets := make(map[string]bool)
+	switch q.GetType() {
+	case qrpb.GQType_USERNAME_LIST, qrpb.GQType_CHECKPOINT_ROUTE:
+		registered := make(map[string]bool)
+		for _, sr := range srs {
+			registered[sr.Username] = true
+		}
+		for _, u := range append(q.GetAnsUsernames(), q.GetRouteUsernames()...) {
+			if registered[u] {
+				targets[u] = true
+			}
+		}
+	case qrpb.GQType_SURVEY_ANS:
+		for _, sr := range srs {
+			if getSurveyResponse(sr.UserInfo, q.GetSurveyId()) == q.GetSurveyTrueIsCorrect() {
+				targets[sr.Username] = true
+			}
+		}
+	default:
+		return nil, false
+	}
+	return targets, true
+}
+
+// solveRates counts the right and wrong answers logged on each level since the questions were last
+// reordered. Checkpoints on a route are not counted as right, as the level is not solved yet.
+func solveRates(logs []LogRow, since int64) map[int64][2]int {
+	counts := make(map[int64][2]int)
+	for _, lr := range logs {
+		if lr.GameLog.GetTimestampUsec() < since {
+			continue
+		}
+		level := lr.GameLog.GetOldState().GetUserLevel()
+		c := counts[level]
+		switch lr.GameLog.GetResult() {
+		case qrpb.ActionLog_RESULT_PROGRESS:
+			c[0]++
+		case qrpb.ActionLog_RESULT_LOST_LIFE, qrpb.ActionLog_RESULT_WRONG_ANSWER, qrpb.ActionLog_RESULT_SKIPPED, qrpb.ActionLog_RESULT_ROUTE_RESET:
+			c[1]++
+		default:
+			continue
+		}
+		counts[level] = c
+	}
+	return counts
+}
+
+// RateQuestions works out how hard each question from the starting level to ORDER_LAST_LEVEL looks.
+// The score averages whichever of these are known: the author's tag, how small a share of the
+// players are a right answer, and how often the level was answered wrong in the logs. Levels with
+// several variants are rated by the one new players get.
+func RateQuestions(sqs *qrpb.GameQSet, gc *qrpb.GameConfig, srs []StateRow, logs []LogRow) []QuestionDifficulty {
+	rates := solveRates(logs, sqs.GetReorderedUsec())
+	qds := make([]QuestionDifficulty, 0)
+	for l := StartingLevel(gc); l <= ORDER_LAST_LEVEL; l++ {
+		q := GetQuestionByIndex(sqs, l)
+		if q == nil {
+			continue
+		}
+		qd := QuestionDifficulty{
+			Level:     l,
+			HTML:      template.HTML(q.GetQuestionHtml()),
+			Answers:   -1,
+			Tag:       q.GetDifficulty(),
+			SolveRate: -1,
+			Fixed:     len(q.GetUnlockTime()) > 0,
+		}
+		parts := make([]float64, 0)
+		if qd.Tag > 0 {
+			parts = append(parts, float64(qd.Tag-1)/4)
+		}
+		if targets, ok := questionTargets(q, srs); ok {
+			qd.targets = targets
+			qd.Answers = len(targets)
+			if len(srs) > 0 {
+				// Half the players or more is as easy as it gets.
+				share := float64(len(targets)) / float64(len(srs))
+				parts = append(parts, 1-min(share*2, 1))
+			}
+		}
+		if c := rates[l]; c[0]+c[1] >= ORDER_MIN_ATTEMPTS {
+			qd.SolveRate = float64(c[0]) / float64(c[0]+c[1])
+			parts = append(parts, 1-qd.SolveRate)
+		}
+		qd.Score = 0.5
+		if len(parts) > 0 {
+			qd.Score = 0
+			for _, p := range parts {
+				qd.Score += p
+			}
+			qd.Score /= float64(len(parts))
+		}
+		qds = append(qds, qd)
+	}
+	return qds
+}
+
+// sameTargets is true if two questions mostly want the same people, so asking them one after the
+// other sends players back to whoever they just scanned. A few people out of a large set, like
+// everyone who said no to a survey question, do not count.
+func sameTargets(a, b QuestionDifficulty) bool {
+	if len(a.targets) == 0 || len(b.targets) == 0 {
+		return false
+	}
+	shared := 0
+	for u := range a.targets {
+		if b.targets[u] {
+			shared++
+		}
+	}
+	return shared*2 >= min(len(a.targets), len(b.targets)) && shared*4 >= max(len(a.targets), len(b.targets))
+}
+
+// OrderingRow is one level of the current or proposed order.
+type OrderingRow struct {
+	Level int64
+	Q     QuestionDifficulty
+	// SameAsPrevious is true if the question wants the same people as the one before it.
+	SameAsPrevious bool
+}
+
+// OrderingProposal is a new order of the questions, next to the current one.
+type OrderingProposal struct {
+	Current  []OrderingRow
+	Proposed []OrderingRow
+	// Moves maps the level a question is on now to the level it is proposed for.
+	Moves map[int64]int64
+}
+
+// ProposeOrdering orders the questions from easiest to hardest, skipping ahead to the next easiest
+// whenever a question wants the same people as the one before it. Questions with an unlock_time
+// stay where they are.
+func ProposeOrdering(qds []QuestionDifficulty) OrderingProposal {
+	op := OrderingProposal{Moves: make(map[int64]int64)}
+	remaining := make([]QuestionDifficulty, 0)
+	for _, qd := range qds {
+		if !qd.Fixed {
+			remaining = append(remaining, qd)
+		}
+	}
+	sort.SliceStable(remaining, func(i, j int) bool { return remaining[i].Score < remaining[j].Score })
+
+	for i, qd := range qds {
+		cur := OrderingRow{Level: qd.Level, Q: qd}
+		if i > 0 {
+			cur.SameAsPrevious = sameTargets(qds[i-1], qd)
+		}
+		op.Current = append(op.Current, cur)
+
+		next := qd
+		if !qd.Fixed {
+			pick := 0
+			if i > 0 {
+				for k, c := range remaining {
+					if !sameTargets(op.Proposed[i-1].Q, c) {
+						pick = k
+						break
+					}
+				}
+			}
+			next = remaining[pick]
+			remaining = append(remaining[:pick], remaining[pick+1:]...)
+		}
+		row := OrderingRow{Level: qd.Level, Q: next}
+		if i > 0 {
+			row.SameAsPrevious = sameTargets(op.Proposed[i-1].Q, next)
+		}
+		op.Proposed = append(op.Proposed, row)
+		if next.Level != qd.Level {
+			op.Moves[next.Level] = qd.Level
+		}
+	}
+	return op
+}
+
+// MovesString writes the moves as "from:to" pairs, for the apply button.
+func (op OrderingProposal) MovesString() string {
+	pairs := make([]string, 0, len(op.Moves))
+	for _, row := range op.Proposed {
+		if to, ok := op.Moves[row.Q.Level]; ok {
+			pairs = append(pairs, fmt.Sprintf("%v:%v", row.Q.Level, to))
+		}
+	}
+	return strings.Join(pairs, ",")
+}
+
+// ParseMoves reads moves written by MovesString.
+func ParseMoves(s string) (map[int64]int64, error) {
+	moves := make(map[int64]int64)
+	if len(s) == 0 {
+		return moves, nil
+	}
+	for _, pair := range strings.Split(s, ",") {
+		from, to, ok := strings.Cut(pair, ":")
+		if !ok {
+			return nil, fmt.Errorf("%q is not a move like 3:7", pair)
+		}
+		f, err := strconv.ParseInt(from, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		t, err := strconv.ParseInt(to, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		moves[f] = t
+	}
+	return moves, nil
+}
+
+// ReorderQuestions returns a copy of sqs with every question on a level in moves, all its variants
+// included, renumbered to the level it moves to. The moves must swap questions among the movable
+// levels, so no level ends up empty or doubled.
+func ReorderQuestions(sqs *qrpb.GameQSet, gc *qrpb.GameConfig, moves map[int64]int64) (*qrpb.GameQSet, error) {
+	tos := make(map[int64]bool)
+	for from, to := range moves {
+		for _, l := range []int64{from, to} {
+			if l < StartingLevel(gc) || l > ORDER_LAST_LEVEL {
+				return nil, fmt.Errorf("only questions %v to %v can be moved, not %v", StartingLevel(gc), ORDER_LAST_LEVEL, l)
+			}
+		}
+		tos[to] = true
+	}
+	for from := range moves {
+		if !tos[from] {
+			return nil, fmt.Errorf("question %v moves away, but nothing takes its place", from)
+		}
+	}
+	if len(tos) != len(moves) {
+		return nil, fmt.Errorf("two questions cannot move to the same level")
+	}
+
+	updated := proto.Clone(sqs).(*qrpb.GameQSet)
+	for _, q := range updated.GetGameQuestions() {
+		if to, ok := moves[q.GetQuestionId()]; ok {
+			q.QuestionId = proto.Int64(to)
+		}
+	}
+	sort.SliceStable(updated.GameQuestions, func(i, j int) bool {
+		return updated.GameQuestions[i].GetQuestionId() < updated.GameQuestions[j].GetQuestionId()
+	})
+	return updated, nil
+}
+
+func (env *Env) adminRenderOrdering(w http.ResponseWriter, r *http.Request) {
+	log.Println("Req: ", r.URL)
+	sqs, err := env.cgo.GetGameQSet()
+	if common.Should500(err, w, "could not read the game questions") {
+		return
+	}
+	gc, err := env.cgo.GetGameConfig()
+	if common.Should500(err, w, "could not read the game settings") {
+		return
+	}
+	srs, err := AdminGetAllUserStates(env.db)
+	if common.Should500(err, w, "could not fetch the players") {
+		return
+	}
+	logs, err := GetAllActionLogs(env.db)
+	if common.Should500(err, w, "could not fetch the logs") {
+		return
+	}
+
+	common.RenderTemplate(w, env.tem, "adminordering.html", struct {
+		Players  int
+		Proposal OrderingProposal
+	}{
+		Players:  len(srs),
+		Proposal: ProposeOrdering(RateQuestions(sqs, gc, srs, logs)),
+	})
+}
+
+func (env *Env) adminApplyOrdering(w http.ResponseWriter, r *http.Request) {
+	log.Println("Req: ", r.URL)
+	if common.Should500(r.ParseForm(), w, "error parsing the new order") {
+		return
+	}
+	moves, err := ParseMoves(r.FormValue("moves"))
+	if common.Should500(err, w, "could not read the new order") {
+		return
+	}
+	gc, err := env.cgo.GetGameConfig()
+	if common.Should500(err, w, "could not read the game settings") {
+		return
+	}
+
+	// Players keep their level, so moving questions under them would change their clue.
+	srs, err := AdminGetAllUserStates(env.db)
+	if common.Should500(err, w, "could not fetch the players") {
+		return
+	}
+	for _, sr := range srs {
+		if sr.State.GetUserLevel() != StartingLevel(gc) {
+			common.Should500(fmt.Errorf("%v is on question %v", sr.Username, sr.State.GetUserLevel()), w,
+				"the game has started, so the questions can no longer be reordered")
+			return
+		}
+	}
+
+	err = env.cgo.UpdateGameQSet(func(sqs *qrpb.GameQSet) (bool, error) {
+		updated, err := ReorderQuestions(sqs, gc, moves)
+		if err != nil {
+			return false, err
+		}
+		proto.Reset(sqs)
+		proto.Merge(sqs, updated)
+		sqs.ReorderedUsec = proto.Int64(time.Now().UnixNano() / 1000)
+		return true, nil
+	})
+	if common.Should500(err, w, "could not reorder the questions") {
+		return
+	}
+	fmt.Fprint(w, "ok")
+}
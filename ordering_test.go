@@ -0,0 +1,180 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/sushovande/qr-mixer-game/qrpb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestProposeOrdering(t *testing.T) {
+	env, err := createEnv(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer env.db.Close()
+	setupGlobals(env.cgo)
+	sqs, _ := env.cgo.GetGameQSet()
+	GetQuestionByIndex(sqs, 4).Difficulty = proto.Int64(5)
+	GetQuestionByIndex(sqs, 6).Difficulty = proto.Int64(1)
+	GetQuestionByIndex(sqs, 10).UnlockTime = proto.String("2022-11-05T19:30:00+05:30")
+	env.cgo.SetGameQSet(sqs)
+	// Half the players said yes to both survey questions, so Q3 and Q5 each want half of them.
+	for i := 1; i <= 10; i++ {
+		sr := GetSyntheticStateRow(i, 1)
+		sr.UserInfo.SurveyAnswers = []*qrpb.SurveyAnswer{
+			{QuestionId: proto.Int64(1), IsTrue: proto.Bool(i <= 5)},
+			{QuestionId: proto.Int64(2), IsTrue: proto.Bool(i <= 5)},
+		}
+		AddUser(env.db, sr)
+	}
+	for i := 0; i < ORDER_MIN_ATTEMPTS; i++ {
+		lr := NewLogRow()
+		lr.Username = "username-1"
+		lr.GameLog.OldState = &qrpb.GameState{UserLevel: proto.Int64(2)}
+		lr.GameLog.Result = qrpb.ActionLog_RESULT_PROGRESS.Enum()
+		AddActionLog(env.db, &lr)
+	}
+
+	srs, _ := AdminGetAllUserStates(env.db)
+	logs, _ := GetAllActionLogs(env.db)
+	gc, _ := env.cgo.GetGameConfig()
+	qds := RateQuestions(sqs, gc, srs, logs)
+	if len(qds) != ORDER_LAST_LEVEL {
+		t.Fatalf("expected questions 1 to %v to be rated. got: %v", ORDER_LAST_LEVEL, len(qds))
+	}
+	// Each regular question up to 9 wants 2 of the 10 registered players.
+	for _, want := range []struct {
+		level     int64
+		score     int
+		solveRate float64
+	}{
+		{1, 60, -1},
+		{2, 30, 1},
+		{3, 0, -1},
+		{4, 80, -1},
+		{6, 30, -1},
+		{19, 50, -1},
+	} {
+		qd := qds[want.level-1]
+		if qd.ScorePercent() != want.score || qd.SolveRate != want.solveRate {
+			t.Errorf("expected question %v to score %v%% with solve rate %v. got: %+v", want.level, want.score, want.solveRate, qd)
+		}
+	}
+
+	op := ProposeOrdering(qds)
+	for i, want := range []int64{3, 5, 2, 6} {
+		if op.Proposed[i].Q.Level != want {
+			t.Errorf("expected question %v at level %v, easiest first. got: %v", want, i+1, op.Proposed[i].Q.Level)
+		}
+	}
+	if op.Proposed[9].Q.Level != 10 {
+		t.Errorf("expected the question with an unlock time to stay put. got: %v", op.Proposed[9].Q.Level)
+	}
+	same := func(rows []OrderingRow) int {
+		n := 0
+		for _, r := range rows {
+			if r.SameAsPrevious {
+				n++
+			}
+		}
+		return n
+	}
+	if same(op.Current) == 0 || same(op.Proposed) >= same(op.Current) {
+		t.Errorf("expected fewer questions in a row that want the same people. got: %v now, %v proposed", same(op.Current), same(op.Proposed))
+	}
+	moves, err := ParseMoves(op.MovesString())
+	if err != nil || len(moves) != len(op.Moves) {
+		t.Errorf("expected the moves to survive the apply button. got: %v %v", moves, err)
+	}
+
+	if _, err := ReorderQuestions(sqs, gc, map[int64]int64{3: 4}); err == nil {
+		t.Errorf("expected an error when a level is left empty")
+	}
+	if _, err := ReorderQuestions(sqs, gc, map[int64]int64{19: 20, 20: 19}); err == nil {
+		t.Errorf("expected an error when moving the endgame")
+	}
+
+	page := callController("GET", "/9283e316-beaa-4182-b3a6-0937046251ee/ordering", "", nil, env.adminRenderOrdering)
+	if page.statuscode != http.StatusOK || !strings.Contains(page.resptext, "was question 3") {
+		t.Errorf("expected the ordering page to show the moves. got: %v %v", page.statuscode, page.resptext)
+	}
+
+	admin := "/9283e316-beaa-4182-b3a6-0937046251ee/applyOrdering"
+	f := callController("POST", admin, "moves="+op.MovesString(), nil, env.adminApplyOrdering)
+	if f.statuscode != http.StatusOK {
+		t.Fatalf("expected the order to be applied. got: %v %v", f.statuscode, f.resptext)
+	}
+	sqs, _ = env.cgo.GetGameQSet()
+	if GetQuestionByIndex(sqs, 1).GetQuestionHtml() != "qHtml-3" || GetQuestionByIndex(sqs, 2).GetQuestionHtml() != "qHtml-5" {
+		t.Errorf("expected the proposed order to be saved. got: %v, %v", GetQuestionByIndex(sqs, 1), GetQuestionByIndex(sqs, 2))
+	}
+	// The logs were made on the old question 2, so they no longer count.
+	if qds := RateQuestions(sqs, gc, srs, logs); sqs.GetReorderedUsec() == 0 || qds[1].SolveRate != -1 {
+		t.Errorf("expected the logs from before the reorder to be ignored. got: %v %+v", sqs.GetReorderedUsec(), qds[1])
+	}
+	def, _ := env.CurrentGameDefinition()
+	if vr := ValidateGame(def); vr.HasErrorsIn(PART_QUESTIONS) {
+		t.Errorf("expected the reordered questions to be valid. got: %v", vr)
+	}
+
+	def.Questions = proto.Clone(def.Questions).(*qrpb.GameQSet)
+	def.Questions.GameQuestions[0].Difficulty = proto.Int64(7)
+	if vr := ValidateGame(def); !hasIssue(vr.Errors, PART_QUESTIONS, "difficulty must be from 1 to 5") {
+		t.Errorf("expected an error for a difficulty out of range. got: %v", vr)
+	}
+
+	sr, _ := GetUserStateByUsername(env.db, "username-1")
+	sr.State.UserLevel = proto.Int64(2)
+	UpdateUserDetails(env.db, sr)
+	f = callController("POST", admin, "moves=1:2,2:1", nil, env.adminApplyOrdering)
+	if f.statuscode != http.StatusInternalServerError {
+		t.Errorf("expected no reordering once the game has started. got: %v %v", f.statuscode, f.resptext)
+	}
+}
+
+func TestSolveRates(t *testing.T) {
+	log := func(level int64, usec int64, result qrpb.ActionLog_ActionResult) LogRow {
+		lr := NewLogRow()
+		lr.GameLog.OldState = &qrpb.GameState{UserLevel: proto.Int64(level)}
+		lr.GameLog.TimestampUsec = proto.Int64(usec)
+		lr.GameLog.Result = result.Enum()
+		return lr
+	}
+	logs := []LogRow{
+		log(1, 100, qrpb.ActionLog_RESULT_PROGRESS),
+		log(1, 200, qrpb.ActionLog_RESULT_PROGRESS),
+		log(1, 200, qrpb.ActionLog_RESULT_LOST_LIFE),
+		log(2, 200, qrpb.ActionLog_RESULT_CHECKPOINT),
+		log(2, 200, qrpb.ActionLog_RESULT_CHECKPOINT),
+		log(2, 200, qrpb.ActionLog_RESULT_ROUTE_RESET),
+		log(3, 200, qrpb.ActionLog_RESULT_CARD_SWAPPED),
+	}
+	rates := solveRates(logs, 150)
+	if rates[1] != [2]int{1, 1} {
+		t.Errorf("expected only the logs after the reorder to count. got: %v", rates[1])
+	}
+	if rates[2] != [2]int{0, 1} {
+		t.Errorf("expected checkpoints not to count as solves. got: %v", rates[2])
+	}
+	if _, ok := rates[3]; ok {
+		t.Errorf("expected poker card swaps not to count. got: %v", rates[3])
+	}
+}
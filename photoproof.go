@@ -0,0 +1,262 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sushovande/qr-mixer-game/common"
+	"github.com/sushovande/qr-mixer-game/qrpb"
+
+	proto "google.golang.org/protobuf/proto"
+)
+
+const MAX_PHOTO_BYTES int64 = 10 << 20
+
+// PhotoRow is a photo uploaded by a player as the answer to a PHOTO_PROOF question.
+type PhotoRow struct {
+	ID          int64
+	Username    string
+	QuestionID  int64
+	Updated     int64
+	ContentType string
+	Status      qrpb.PhotoStatus
+	// Photo is only filled in when a single row is fetched.
+	Photo []byte
+}
+
+// MaybeCreatePhotoTable creates the photo review table in the db if it didn't exist
+func MaybeCreatePhotoTable(db *sql.DB) error {
+	const createStmt = `
+	CREATE TABLE IF NOT EXISTS photosubmissions (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		username TEXT,
+		questionid INT,
+		updated INT,
+		contenttype TEXT,
+		status INT,
+		photo BLOB
+	);
+	`
+	_, err := db.Exec(createStmt)
+	if err != nil {
+		log.Printf("Db-err: %v\n", err)
+		return err
+	}
+	return nil
+}
+
+// AddPhotoSubmission stores a newly uploaded photo in the review queue.
+func AddPhotoSubmission(db *sql.DB, pr *PhotoRow) error {
+	const insData = `INSERT INTO photosubmissions(username, questionid, updated, contenttype, status, photo) VALUES(?,?,?,?,?,?)`
+	_, err := db.Exec(insData, pr.Username, pr.QuestionID, pr.Updated, pr.ContentType, int32(pr.Status), pr.Photo)
+	return err
+}
+
+// GetPendingPhotos returns the photos waiting for review, oldest first, without the image bytes.
+func GetPendingPhotos(db *sql.DB) ([]PhotoRow, error) {
+	const getData = `SELECT id, username, questionid, updated, contenttype, status FROM photosubmissions WHERE status=? ORDER BY updated ASC`
+	rows, err := db.Query(getData, int32(qrpb.PhotoStatus_PHOTO_PENDING))
+	if err != nil {
+		log.Printf("Db-err: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+	reply := make([]PhotoRow, 0)
+	for rows.Next() {
+		var pr PhotoRow
+		var status int32
+		if err = rows.Scan(&pr.ID, &pr.Username, &pr.QuestionID, &pr.Updated, &pr.ContentType, &status); err != nil {
+			return nil, err
+		}
+		pr.Status = qrpb.PhotoStatus(status)
+		reply = append(reply, pr)
+	}
+	return reply, nil
+}
+
+// GetPhotoByID returns a single photo including the image bytes.
+func GetPhotoByID(db *sql.DB, id int64) (*PhotoRow, error) {
+	const getStmt = `SELECT id, username, questionid, updated, contenttype, status, photo FROM photosubmissions WHERE id=?`
+	rows, err := db.Query(getStmt, id)
+	if err != nil {
+		log.Printf("Db-err: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+	if rows.Next() {
+		var pr PhotoRow
+		var status int32
+		if err = rows.Scan(&pr.ID, &pr.Username, &pr.QuestionID, &pr.Updated, &pr.ContentType, &status, &pr.Photo); err != nil {
+			return nil, err
+		}
+		pr.Status = qrpb.PhotoStatus(status)
+		return &pr, nil
+	}
+	return nil, nil
+}
+
+// SetPhotoStatus records the review decision on a photo.
+func SetPhotoStatus(db *sql.DB, id int64, status qrpb.PhotoStatus) error {
+	const updStmt = `UPDATE photosubmissions SET status=? WHERE id=?`
+	_, err := db.Exec(updStmt, int32(status), id)
+	return err
+}
+
+// StepPhotoUpload returns the GameState resulting from the player uploading a photo at the current GameState.
+func (env *Env) StepPhotoUpload(old *qrpb.GameState) (StepResponse, error) {
+	result := NewStepResponse()
+	result.newState = proto.Clone(old).(*qrpb.GameState)
+
+	if StepTerminal(&result, old) {
+		return result, nil
+	}
+
+	sqs, err := env.cgo.GetGameQSet()
+	if err != nil {
+		return StepResponse{}, err
+	}
+	ss, err := env.cgo.GetSurveySet()
+	if err != nil {
+		return StepResponse{}, err
+	}
+	sq := GetQuestionForPlayer(sqs, old)
+
+	if StepMisconfigured(&result, sq, ss, old.GetUserLevel()) {
+		return result, nil
+	}
+	if StepLocked(&result, sq, time.Now()) {
+		return result, nil
+	}
+	if sq.GetType() != qrpb.GQType_PHOTO_PROOF {
+		result.actionString = "This clue does not need a photo!"
+		result.actionResult = *qrpb.ActionLog_RESULT_IGNORED.Enum()
+		return result, nil
+	}
+	if old.GetPhotoPending() {
+		result.actionString = "Your photo is still being reviewed."
+		result.actionResult = *qrpb.ActionLog_RESULT_IGNORED.Enum()
+		return result, nil
+	}
+
+	result.newState.PhotoPending = proto.Bool(true)
+	result.actionString = "Photo sent for review!"
+	result.actionResult = *qrpb.ActionLog_RESULT_PHOTO_PENDING.Enum()
+	return result, nil
+}
+
+// StepPhotoReview returns the GameState resulting from an organizer approving or rejecting the photo of a
+// player with the given handicap, which may be nil.
+func (env *Env) StepPhotoReview(old *qrpb.GameState, approved bool, h *qrpb.PlayerHandicap) (StepResponse, error) {
+	result := NewStepResponse()
+	result.newState = proto.Clone(old).(*qrpb.GameState)
+	result.newState.PhotoPending = nil
+
+	sqs, err := env.cgo.GetGameQSet()
+	if err != nil {
+		return StepResponse{}, err
+	}
+
+	gc, err := env.cgo.GetGameConfig()
+	if err != nil {
+		return StepResponse{}, err
+	}
+
+	fx, err := env.CurrentEventEffects()
+	if err != nil {
+		return StepResponse{}, err
+	}
+
+	if approved {
+		AnswerCorrect(&result, ApplyHandicap(fx.Apply(RulesFor(GetQuestionForPlayer(sqs, old), gc)), h))
+		MaybeGrantMetal(&result, fx.DoubleTokenChance, env.rnd)
+	} else {
+		// A rejected photo sends the player back to try again, without any penalty.
+		result.actionString = "Photo Rejected!"
+		result.actionResult = *qrpb.ActionLog_RESULT_PHOTO_REJECTED.Enum()
+	}
+
+	return result, nil
+}
+
+// submitPhoto is the backend for PHOTO_PROOF questions. It puts the photo in the review queue.
+func (env *Env) submitPhoto(w http.ResponseWriter, r *http.Request) {
+	log.Println("Req: ", r.URL)
+	ck, err := r.Cookie("sid")
+	if err != nil {
+		common.RespondHTTP401(w)
+		return
+	}
+
+	u, err := GetUserStateByCookie(env.db, ck.Value)
+	if err != nil || u == nil {
+		common.RespondHTTP401(w)
+		return
+	}
+
+	mode, err := env.CurrentGameMode()
+	if common.Should500(err, w, "There was a problem figuring out the game, maybe try again?") {
+		return
+	}
+	fa, ok := mode.(FormAnswerer)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, MAX_PHOTO_BYTES)
+	f, _, err := r.FormFile("photo")
+	if common.Should500(err, w, "We could not read your photo, it may be too large.") {
+		return
+	}
+	defer f.Close()
+	photo, err := io.ReadAll(f)
+	if common.Should500(err, w, "We could not read your photo, it may be too large.") {
+		return
+	}
+	contentType := http.DetectContentType(photo)
+	if !strings.HasPrefix(contentType, "image/") {
+		common.Should500(fmt.Errorf("unexpected upload of type %v", contentType), w, "That doesn't look like a photo.")
+		return
+	}
+
+	stepResult, err := fa.StepPhoto(env, u)
+	if err != nil {
+		common.Should500(err, w, fmt.Sprintf("We could not accept your photo: %v", err.Error()))
+		return
+	}
+
+	if stepResult.actionResult == qrpb.ActionLog_RESULT_PHOTO_PENDING {
+		pr := &PhotoRow{
+			Username:    u.Username,
+			QuestionID:  u.State.GetUserLevel(),
+			Updated:     time.Now().UnixNano() / 1000,
+			ContentType: contentType,
+			Status:      qrpb.PhotoStatus_PHOTO_PENDING,
+			Photo:       photo,
+		}
+		if common.Should500(AddPhotoSubmission(env.GetDb(), pr), w, "could not save your photo, please try again") {
+			return
+		}
+	}
+
+	env.recordStep(w, u, stepResult, qrpb.ActionLog_ACTION_PHOTO_UPLOAD)
+}
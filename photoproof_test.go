@@ -0,0 +1,134 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sushovande/qr-mixer-game/qrpb"
+)
+
+// A 1x1 transparent png.
+var tinyPng = []byte{
+	0x89, 0x50, 0x4e, 0x47, 0x0d, 0x0a, 0x1a, 0x0a, 0x00, 0x00, 0x00, 0x0d, 0x49, 0x48, 0x44, 0x52,
+	0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01, 0x08, 0x06, 0x00, 0x00, 0x00, 0x1f, 0x15, 0xc4,
+	0x89, 0x00, 0x00, 0x00, 0x0a, 0x49, 0x44, 0x41, 0x54, 0x78, 0x9c, 0x63, 0x00, 0x01, 0x00, 0x00,
+	0x05, 0x00, 0x01, 0x0d, 0x0a, 0x2d, 0xb4, 0x00, 0x00, 0x00, 0x00, 0x49, 0x45, 0x4e, 0x44, 0xae,
+	0x42, 0x60, 0x82,
+}
+
+// uploadPhoto posts the given bytes to the submitPhoto controller as a multipart form.
+func uploadPhoto(env *Env, cookie *http.Cookie, photo []byte) savedHTTPResponse {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fw, _ := mw.CreateFormFile("photo", "photo.png")
+	fw.Write(photo)
+	mw.Close()
+
+	req := httptest.NewRequest("POST", "/submitphoto", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	req.AddCookie(cookie)
+	resp := httptest.NewRecorder()
+	env.submitPhoto(resp, req)
+	return savedHTTPResponse{statuscode: resp.Result().StatusCode, resptext: resp.Body.String()}
+}
+
+func TestPhotoProofQuestion(t *testing.T) {
+	env, err := createEnv(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer env.db.Close()
+	setupSynthetic(env.cgo, 10)
+
+	// Switch Q2 to a photo question
+	sqs, _ := env.cgo.GetGameQSet()
+	sqs.GameQuestions[1].Type = qrpb.GQType_PHOTO_PROOF.Enum()
+	sqs.GameQuestions[1].AnsUsernames = []string{}
+	env.cgo.SetGameQSet(sqs)
+
+	ck1 := http.Cookie{Name: "sid", Value: "cookie-1", Expires: time.Now().Add(24 * 30 * time.Hour)}
+	callController("POST", "/submitsurvey", "qr=qrcode-1&dqans1=true&dqans2=false", &ck1, env.submitSurvey)
+	callController("POST", "/makemove", "answer=qrcode-1", &ck1, env.makeMove)
+
+	// Things that aren't images are refused.
+	f := uploadPhoto(env, &ck1, []byte("hello, I am not a photo"))
+	if f.statuscode != 500 {
+		t.Errorf("expected a text upload to be refused. got HTTP %v", f.statuscode)
+	}
+
+	f = uploadPhoto(env, &ck1, tinyPng)
+	if f.statuscode != 200 {
+		t.Fatalf("Expected HTTP 200. got: %v\n%v", f.statuscode, f.resptext)
+	}
+	var mr MoveResponse
+	if err := json.Unmarshal([]byte(f.resptext), &mr); err != nil {
+		t.Fatal(err)
+	}
+	if !mr.State.GetPhotoPending() || mr.GameArtifacts["answerType"] != "photopending" {
+		t.Errorf("expected the player to be waiting for review. got: %v", f.resptext)
+	}
+
+	// Scanning while waiting is not penalised.
+	callController("POST", "/makemove", "answer=qrcode-9", &ck1, env.makeMove)
+
+	prs, err := GetPendingPhotos(env.db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prs) != 1 || prs[0].Username != "username-1" || prs[0].QuestionID != 2 {
+		t.Fatalf("expected one pending photo for username-1 on level 2. got: %v", prs)
+	}
+
+	// Rejecting the photo sends the player back to upload again.
+	f = callController("POST", "/reviewPhoto", fmt.Sprintf("id=%v&approve=false", prs[0].ID), nil, env.adminReviewPhoto)
+	if f.statuscode != 200 {
+		t.Fatalf("Expected HTTP 200. got: %v\n%v", f.statuscode, f.resptext)
+	}
+	sr, _ := GetUserStateByUsername(env.db, "username-1")
+	if sr.State.GetPhotoPending() || sr.State.GetUserLevel() != 2 || sr.State.GetLife() != STARTING_LIFE {
+		t.Errorf("expected the player back on level 2 with full life. got: %v", sr.State)
+	}
+
+	// A second review of the same photo is refused.
+	f = callController("POST", "/reviewPhoto", fmt.Sprintf("id=%v&approve=true", prs[0].ID), nil, env.adminReviewPhoto)
+	if f.statuscode != 500 {
+		t.Errorf("expected a second review to fail. got HTTP %v", f.statuscode)
+	}
+
+	// Approving the next photo moves the player ahead.
+	uploadPhoto(env, &ck1, tinyPng)
+	prs, _ = GetPendingPhotos(env.db)
+	if len(prs) != 1 {
+		t.Fatalf("expected one pending photo. got: %v", len(prs))
+	}
+	callController("POST", "/reviewPhoto", fmt.Sprintf("id=%v&approve=true", prs[0].ID), nil, env.adminReviewPhoto)
+	sr, _ = GetUserStateByUsername(env.db, "username-1")
+	if sr.State.GetPhotoPending() || sr.State.GetUserLevel() != 3 {
+		t.Errorf("expected the player to move to level 3. got: %v", sr.State)
+	}
+
+	logs, _ := GetAllLogsForUser(env.db, "username-1")
+	if logs[0].GameLog.GetType() != qrpb.ActionLog_ACTION_PHOTO_REVIEW {
+		t.Errorf("expected the review to be logged. got: %v", logs[0].GameLog)
+	}
+}
@@ -0,0 +1,397 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sushovande/qr-mixer-game/common"
+	"github.com/sushovande/qr-mixer-game/qrpb"
+
+	proto "google.golang.org/protobuf/proto"
+)
+
+const GAME_MODE_POKER = "poker"
+const POKER_HAND_SIZE = 5
+
+// pokerMode is an endgame where players collect the cards on each other's badges and the best
+// poker hand wins. It replaces the hunt for everyone at once; with poker.after_hunt, the hunt plays
+// the same endgame for each player who finishes it.
+type pokerMode struct{}
+
+func init() {
+	RegisterGameMode(GAME_MODE_POKER, pokerMode{})
+}
+
+func (pokerMode) InitialState(env *Env, username string) (*qrpb.GameState, error) {
+	return env.NewGameState()
+}
+
+func (pokerMode) Step(env *Env, u *StateRow, answer string) (StepResponse, error) {
+	return env.StepPoker(u, answer)
+}
+
+func (pokerMode) Clue(env *Env, u *StateRow) (ClueData, error) {
+	clue, err := env.RenderPokerHand(u.State)
+	return ClueData{HTML: clue}, err
+}
+
+func (pokerMode) RanksAhead(a, b *DisplayUser) bool {
+	return a.PokerScore > b.PokerScore
+}
+
+// PokerHand is how strong a set of cards is.
+type PokerHand struct {
+	// Name is the kind of hand, like "Full House".
+	Name string
+	// Score orders hands. A higher score wins, and equal scores tie.
+	Score int64
+}
+
+var pokerHandNames = []string{
+	"High Card", "One Pair", "Two Pair", "Three of a Kind", "Straight",
+	"Flush", "Full House", "Four of a Kind", "Straight Flush",
+}
+
+// EvaluatePokerHand scores up to five cards with the usual poker rules. Aces are high, except in
+// the five-high straight. Straights and flushes need all five cards, so a partial hand can at best
+// be four of a kind.
+func EvaluatePokerHand(cards []*qrpb.PokerCard) PokerHand {
+	if len(cards) == 0 {
+		return PokerHand{Name: "No Cards"}
+	}
+
+	counts := make(map[int64]int)
+	values := make([]int64, 0)
+	flush := len(cards) == POKER_HAND_SIZE
+	for _, c := range cards {
+		v := c.GetRank()
+		if v == 1 {
+			v = 14
+		}
+		if counts[v] == 0 {
+			values = append(values, v)
+		}
+		counts[v]++
+		if c.GetSuit() != cards[0].GetSuit() {
+			flush = false
+		}
+	}
+	// Bigger groups first, then higher values. This is also the tie-break order.
+	sort.Slice(values, func(i, j int) bool {
+		if counts[values[i]] != counts[values[j]] {
+			return counts[values[i]] > counts[values[j]]
+		}
+		return values[i] > values[j]
+	})
+
+	straight := false
+	if len(values) == POKER_HAND_SIZE {
+		if values[0]-values[4] == 4 {
+			straight = true
+		} else if values[0] == 14 && values[1] == 5 {
+			// A-2-3-4-5: the ace plays low.
+			straight = true
+			values = append(values[1:], 1)
+		}
+	}
+
+	var category int
+	top := counts[values[0]]
+	second := 0
+	if len(values) > 1 {
+		second = counts[values[1]]
+	}
+	switch {
+	case straight && flush:
+		category = 8
+	case top == 4:
+		category = 7
+	case top == 3 && second == 2:
+		category = 6
+	case flush:
+		category = 5
+	case straight:
+		category = 4
+	case top == 3:
+		category = 3
+	case top == 2 && second == 2:
+		category = 2
+	case top == 2:
+		category = 1
+	}
+
+	// Values are at most 14, so each one fits in a base-15 digit.
+	score := int64(category)
+	for i := 0; i < POKER_HAND_SIZE; i++ {
+		score *= 15
+		if i < len(values) {
+			score += values[i]
+		}
+	}
+	return PokerHand{Name: pokerHandNames[category], Score: score}
+}
+
+// PokerCardName returns a short name for the card, like "Q♥".
+func PokerCardName(c *qrpb.PokerCard) string {
+	ranks := map[int64]string{1: "A", 11: "J", 12: "Q", 13: "K"}
+	r, ok := ranks[c.GetRank()]
+	if !ok {
+		r = strconv.FormatInt(c.GetRank(), 10)
+	}
+	suits := map[qrpb.CardSuit]string{
+		qrpb.CardSuit_SPADES: "♠", qrpb.CardSuit_HEARTS: "♥", qrpb.CardSuit_CLUBS: "♣", qrpb.CardSuit_DIAMONDS: "♦",
+	}
+	return r + suits[c.GetSuit()]
+}
+
+// PokerHandString lists the cards in the hand, for the admin pages.
+func PokerHandString(cards []*qrpb.PokerCard) string {
+	names := make([]string, 0, len(cards))
+	for _, c := range cards {
+		names = append(names, PokerCardName(c))
+	}
+	return strings.Join(names, " ")
+}
+
+// PlaysPoker returns true if the player is collecting cards: always in the poker game mode, and in
+// the hunt once they have won it, if the organizer set poker.after_hunt.
+func PlaysPoker(mode GameMode, gc *qrpb.GameConfig, gs *qrpb.GameState) bool {
+	switch mode.(type) {
+	case pokerMode:
+		return true
+	case huntMode:
+		return gc.GetPoker().GetAfterHunt() && HasWon(gs)
+	}
+	return false
+}
+
+// pokerTimeUp returns true if the organizer set an end time and it has passed.
+func pokerTimeUp(pc *qrpb.PokerConfig, now time.Time) bool {
+	if len(pc.GetEndTime()) == 0 {
+		return false
+	}
+	end, err := time.Parse(time.RFC3339, pc.GetEndTime())
+	if err != nil {
+		log.Printf("could not parse poker end_time: %v", err)
+		return false
+	}
+	return now.After(end)
+}
+
+// StepPoker returns the GameState resulting from the player scanning someone in the poker game mode.
+// Scans never cost a life.
+func (env *Env) StepPoker(u *StateRow, answer string) (StepResponse, error) {
+	gc, err := env.cgo.GetGameConfig()
+	if err != nil {
+		return StepResponse{}, err
+	}
+
+	old := u.State
+	result := NewStepResponse()
+	result.actionResult = *qrpb.ActionLog_RESULT_IGNORED.Enum()
+	scanned, rejection, err := env.ResolveScan(answer, time.Now())
+	if err != nil {
+		return StepResponse{}, err
+	}
+	result.scannedClue = scanned.GetUsername()
+	result.newState = proto.Clone(old).(*qrpb.GameState)
+
+	if len(rejection) > 0 {
+		stepRejected(&result, rejection)
+		return result, nil
+	}
+
+	if pokerTimeUp(gc.GetPoker(), time.Now()) {
+		result.actionString = "Time's Up!"
+		return result, nil
+	}
+	if scanned == nil {
+		result.actionString = "Unknown Badge!"
+		return result, nil
+	}
+	if scanned.GetUsername() == u.Username {
+		result.actionString = "That's You!"
+		return result, nil
+	}
+	if scanned.GetCardSuit() == qrpb.CardSuit_CARD_SUIT_UNSPECIFIED || scanned.GetCardRank() < 1 || scanned.GetCardRank() > 13 {
+		result.actionString = "No Card On That Badge!"
+		return result, nil
+	}
+	if result.newState.GetPokerOffer().GetFromUsername() == scanned.GetUsername() {
+		result.actionString = "Already Have That Card!"
+		return result, nil
+	}
+	for _, c := range result.newState.GetPokerHand() {
+		if c.GetFromUsername() == scanned.GetUsername() {
+			result.actionString = "Already Have That Card!"
+			return result, nil
+		}
+	}
+
+	card := &qrpb.PokerCard{
+		Suit:         scanned.CardSuit,
+		Rank:         scanned.CardRank,
+		FromUsername: scanned.Username,
+	}
+	if len(result.newState.GetPokerHand()) < POKER_HAND_SIZE {
+		result.newState.PokerHand = append(result.newState.PokerHand, card)
+		result.actionString = "Card Collected!"
+		result.actionResult = *qrpb.ActionLog_RESULT_CARD_COLLECTED.Enum()
+		return result, nil
+	}
+	result.newState.PokerOffer = card
+	result.actionString = "Swap a Card?"
+	result.actionResult = *qrpb.ActionLog_RESULT_CARD_OFFERED.Enum()
+	return result, nil
+}
+
+// StepPokerSwap changes the player's hand. discard is either the position of a held card, or
+// "offer". A held card is replaced by the waiting offer, or dropped to free a slot if there is no
+// offer. "offer" throws the waiting offer away.
+func (env *Env) StepPokerSwap(old *qrpb.GameState, discard string) (StepResponse, error) {
+	gc, err := env.cgo.GetGameConfig()
+	if err != nil {
+		return StepResponse{}, err
+	}
+
+	result := NewStepResponse()
+	result.actionResult = *qrpb.ActionLog_RESULT_IGNORED.Enum()
+	result.newState = proto.Clone(old).(*qrpb.GameState)
+	offer := result.newState.GetPokerOffer()
+	result.scannedClue = offer.GetFromUsername()
+
+	if pokerTimeUp(gc.GetPoker(), time.Now()) {
+		result.actionString = "Time's Up!"
+		return result, nil
+	}
+
+	if discard == "offer" {
+		if offer == nil {
+			return StepResponse{}, fmt.Errorf("there is no card waiting to be swapped")
+		}
+		result.newState.PokerOffer = nil
+		result.actionString = "Card Thrown Away"
+		result.actionResult = *qrpb.ActionLog_RESULT_CARD_SWAPPED.Enum()
+		return result, nil
+	}
+
+	i, err := strconv.Atoi(discard)
+	if err != nil || i < 0 || i >= len(result.newState.GetPokerHand()) {
+		return StepResponse{}, fmt.Errorf("there is no card %q in your hand", discard)
+	}
+	if offer == nil {
+		result.scannedClue = result.newState.PokerHand[i].GetFromUsername()
+		result.newState.PokerHand = append(result.newState.PokerHand[:i], result.newState.PokerHand[i+1:]...)
+		result.actionString = "Card Dropped"
+		result.actionResult = *qrpb.ActionLog_RESULT_CARD_SWAPPED.Enum()
+		return result, nil
+	}
+	result.newState.PokerHand[i] = offer
+	result.newState.PokerOffer = nil
+	result.actionString = "Card Swapped!"
+	result.actionResult = *qrpb.ActionLog_RESULT_CARD_SWAPPED.Enum()
+	return result, nil
+}
+
+// RenderPokerHand returns the HTML for the player's hand, and the card waiting to be swapped in.
+func (env *Env) RenderPokerHand(gs *qrpb.GameState) (string, error) {
+	gc, err := env.cgo.GetGameConfig()
+	if err != nil {
+		return "", err
+	}
+
+	type CardView struct {
+		Index int
+		Name  string
+		Red   bool
+	}
+	view := func(i int, c *qrpb.PokerCard) CardView {
+		return CardView{
+			Index: i,
+			Name:  PokerCardName(c),
+			Red:   c.GetSuit() == qrpb.CardSuit_HEARTS || c.GetSuit() == qrpb.CardSuit_DIAMONDS,
+		}
+	}
+	cards := make([]CardView, 0)
+	for i, c := range gs.GetPokerHand() {
+		cards = append(cards, view(i, c))
+	}
+	var offer *CardView
+	if gs.GetPokerOffer() != nil {
+		o := view(-1, gs.GetPokerOffer())
+		offer = &o
+	}
+
+	var buf bytes.Buffer
+	err = env.tem.ExecuteTemplate(&buf, "pokerhand.html", struct {
+		Cards    []CardView
+		Offer    *CardView
+		HandName string
+		Full     bool
+		TimeUp   bool
+	}{
+		Cards:    cards,
+		Offer:    offer,
+		HandName: EvaluatePokerHand(gs.GetPokerHand()).Name,
+		Full:     len(cards) >= POKER_HAND_SIZE,
+		TimeUp:   pokerTimeUp(gc.GetPoker(), time.Now()),
+	})
+	return buf.String(), err
+}
+
+// pokerSwap is the backend for swapping cards in and out of the player's poker hand.
+func (env *Env) pokerSwap(w http.ResponseWriter, r *http.Request) {
+	log.Println("Req: ", r.URL)
+	ck, err := r.Cookie("sid")
+	if err != nil {
+		common.RespondHTTP401(w)
+		return
+	}
+
+	u, err := GetUserStateByCookie(env.db, ck.Value)
+	if err != nil || u == nil {
+		common.RespondHTTP401(w)
+		return
+	}
+
+	mode, err := env.CurrentGameMode()
+	if common.Should500(err, w, "There was a problem figuring out the game, maybe try again?") {
+		return
+	}
+	gc, err := env.cgo.GetGameConfig()
+	if common.Should500(err, w, "There was a problem reading the game settings, maybe try again?") {
+		return
+	}
+	if !PlaysPoker(mode, gc, u.State) {
+		http.NotFound(w, r)
+		return
+	}
+
+	stepResult, err := env.StepPokerSwap(u.State, r.FormValue("discard"))
+	if err != nil {
+		common.Should500(err, w, fmt.Sprintf("We could not swap your card: %v", err.Error()))
+		return
+	}
+
+	env.recordStep(w, u, stepResult, qrpb.ActionLog_ACTION_CARD_SWAP)
+}
@@ -0,0 +1,227 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sushovande/qr-mixer-game/qrpb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// parseHand turns "AS KH 2C" into cards.
+func parseHand(s string) []*qrpb.PokerCard {
+	suits := map[byte]qrpb.CardSuit{'S': qrpb.CardSuit_SPADES, 'H': qrpb.CardSuit_HEARTS, 'C': qrpb.CardSuit_CLUBS, 'D': qrpb.CardSuit_DIAMONDS}
+	ranks := map[string]int64{"A": 1, "T": 10, "J": 11, "Q": 12, "K": 13}
+	cards := make([]*qrpb.PokerCard, 0)
+	for _, f := range strings.Fields(s) {
+		r, ok := ranks[f[:1]]
+		if !ok {
+			r = int64(f[0] - '0')
+		}
+		cards = append(cards, &qrpb.PokerCard{Suit: suits[f[1]].Enum(), Rank: proto.Int64(r)})
+	}
+	return cards
+}
+
+func TestEvaluatePokerHand(t *testing.T) {
+	tests := []struct {
+		hand string
+		name string
+	}{
+		{"", "No Cards"},
+		{"AS", "High Card"},
+		{"2S 2H", "One Pair"},
+		{"2S 2H 3C 3D", "Two Pair"},
+		{"2S 2H 2C 9D 4S", "Three of a Kind"},
+		{"AS 2H 3C 4D 5S", "Straight"},
+		{"TS JH QC KD AS", "Straight"},
+		{"2H 7H 9H JH KH", "Flush"},
+		{"QS QH QC 4D 4S", "Full House"},
+		{"9S 9H 9C 9D", "Four of a Kind"},
+		{"5H 6H 7H 8H 9H", "Straight Flush"},
+		{"QS KH AC 2D 3S", "High Card"},
+	}
+	for _, tt := range tests {
+		if got := EvaluatePokerHand(parseHand(tt.hand)).Name; got != tt.name {
+			t.Errorf("%q: expected %v. got: %v", tt.hand, tt.name, got)
+		}
+	}
+
+	// Each hand beats the one after it.
+	ordered := []string{
+		"TH JH QH KH AH",
+		"5H 6H 7H 8H 9H",
+		"AS 2S 3S 4S 5S",
+		"2S 2H 2C 2D",
+		"3S 3H 3C 2D 2S",
+		"2S 2H 2C 3D 3S",
+		"2H 7H 9H JH KH",
+		"TS JH QC KD AS",
+		"AS 2H 3C 4D 5S",
+		"AS AH AC",
+		"KS KH 5C 5D 9S",
+		"KS KH 5C 5D 8S",
+		"AS AH",
+		"AS KH QC JD 9S",
+		"AS",
+		"",
+	}
+	for i := 0; i+1 < len(ordered); i++ {
+		a := EvaluatePokerHand(parseHand(ordered[i]))
+		b := EvaluatePokerHand(parseHand(ordered[i+1]))
+		if a.Score <= b.Score {
+			t.Errorf("expected %q (%v) to beat %q (%v)", ordered[i], a.Name, ordered[i+1], b.Name)
+		}
+	}
+}
+
+func TestPokerGame(t *testing.T) {
+	env, err := createEnv(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer env.db.Close()
+	setupSynthetic(env.cgo, 8)
+	env.cgo.SetGameConfig(&qrpb.GameConfig{GameMode: proto.String(GAME_MODE_POKER)})
+
+	ck := http.Cookie{Name: "sid", Value: "cookie-1", Expires: time.Now().Add(24 * 30 * time.Hour)}
+	callController("POST", "/submitsurvey", "qr=qrcode-1&dqans1=true&dqans2=false", &ck, env.submitSurvey)
+
+	var mr MoveResponse
+	f := callController("POST", "/makemove", "answer=qrcode-1", &ck, env.makeMove)
+	json.Unmarshal([]byte(f.resptext), &mr)
+	if mr.GameArtifacts["action"] != "That's You!" {
+		t.Errorf("expected your own badge not to count. got: %v", f.resptext)
+	}
+
+	// Badges 2 to 6 hold 2♥ 3♣ 4♦ 5♠ 6♥.
+	for i := 2; i <= 6; i++ {
+		mr = MoveResponse{}
+		f = callController("POST", "/makemove", fmt.Sprintf("answer=qrcode-%v", i), &ck, env.makeMove)
+		json.Unmarshal([]byte(f.resptext), &mr)
+	}
+	if len(mr.State.GetPokerHand()) != 5 || !strings.Contains(mr.PortHTML, "Straight") {
+		t.Errorf("expected a full hand with a straight. got: %v", f.resptext)
+	}
+
+	mr = MoveResponse{}
+	f = callController("POST", "/makemove", "answer=qrcode-3", &ck, env.makeMove)
+	json.Unmarshal([]byte(f.resptext), &mr)
+	if mr.GameArtifacts["action"] != "Already Have That Card!" {
+		t.Errorf("expected a repeat badge to be ignored. got: %v", f.resptext)
+	}
+
+	mr = MoveResponse{}
+	f = callController("POST", "/makemove", "answer=qrcode-7", &ck, env.makeMove)
+	json.Unmarshal([]byte(f.resptext), &mr)
+	if mr.GameArtifacts["action"] != "Swap a Card?" || mr.State.GetPokerOffer().GetRank() != 7 {
+		t.Errorf("expected the 7 to be offered. got: %v", f.resptext)
+	}
+
+	mr = MoveResponse{}
+	f = callController("POST", "/pokerswap", "discard=0", &ck, env.pokerSwap)
+	json.Unmarshal([]byte(f.resptext), &mr)
+	if mr.GameArtifacts["action"] != "Card Swapped!" || mr.State.GetPokerOffer() != nil {
+		t.Fatalf("expected the 7 to replace the 2. got: %v", f.resptext)
+	}
+	if got := PokerHandString(mr.State.GetPokerHand()); got != "7♣ 3♣ 4♦ 5♠ 6♥" {
+		t.Errorf("expected the swapped hand. got: %v", got)
+	}
+	logs, _ := GetAllLogsForUser(env.db, "username-1")
+	if r := logs[0].GameLog.GetResult(); r != qrpb.ActionLog_RESULT_CARD_SWAPPED {
+		t.Errorf("expected the swap to be logged as a swap, not as solving a question. got: %v", r)
+	}
+
+	f = callController("POST", "/pokerswap", "discard=offer", &ck, env.pokerSwap)
+	if f.statuscode != http.StatusInternalServerError {
+		t.Errorf("expected an error with no offer waiting. got: %v", f.resptext)
+	}
+
+	f = callController("GET", "/allUsers", "", nil, env.adminAllUsers)
+	if !strings.Contains(f.resptext, "7♣ 3♣ 4♦ 5♠ 6♥") || !strings.Contains(f.resptext, "Straight") {
+		t.Errorf("expected admins to see the hand. got: %v", f.resptext)
+	}
+
+	// Once the game ends, hands are frozen.
+	env.cgo.SetGameConfig(&qrpb.GameConfig{
+		GameMode: proto.String(GAME_MODE_POKER),
+		Poker:    &qrpb.PokerConfig{EndTime: proto.String(time.Now().Add(-time.Minute).Format(time.RFC3339))},
+	})
+	mr = MoveResponse{}
+	f = callController("POST", "/makemove", "answer=qrcode-8", &ck, env.makeMove)
+	json.Unmarshal([]byte(f.resptext), &mr)
+	if mr.GameArtifacts["action"] != "Time's Up!" || mr.State.GetPokerOffer() != nil {
+		t.Errorf("expected no more cards after the end. got: %v", f.resptext)
+	}
+}
+
+func TestPokerAfterHunt(t *testing.T) {
+	env, err := createEnv(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer env.db.Close()
+	setupSynthetic(env.cgo, 8)
+
+	ck := http.Cookie{Name: "sid", Value: "cookie-1", Expires: time.Now().Add(24 * 30 * time.Hour)}
+	callController("POST", "/submitsurvey", "qr=qrcode-1&dqans1=true&dqans2=false", &ck, env.submitSurvey)
+
+	// Players on the hunt cannot touch the poker hand.
+	f := callController("POST", "/pokerswap", "discard=0", &ck, env.pokerSwap)
+	if f.statuscode != http.StatusNotFound {
+		t.Errorf("expected no card swaps in the hunt. got: %v %v", f.statuscode, f.resptext)
+	}
+
+	env.cgo.SetGameConfig(&qrpb.GameConfig{Poker: &qrpb.PokerConfig{AfterHunt: proto.Bool(true)}})
+	var mr MoveResponse
+	f = callController("POST", "/makemove", "answer=qrcode-1", &ck, env.makeMove)
+	json.Unmarshal([]byte(f.resptext), &mr)
+	if mr.GameArtifacts["action"] != "Correct!" || len(mr.State.GetPokerHand()) != 0 {
+		t.Errorf("expected the hunt to go on until the player wins it. got: %v", f.resptext)
+	}
+	f = callController("POST", "/pokerswap", "discard=0", &ck, env.pokerSwap)
+	if f.statuscode != http.StatusNotFound {
+		t.Errorf("expected no card swaps before the hunt is won. got: %v %v", f.statuscode, f.resptext)
+	}
+
+	u1, _ := GetUserStateByCookie(env.db, "cookie-1")
+	u1.State.UserLevel = proto.Int64(HUNT_VICTORY_LEVEL)
+	UpdateUserDetails(env.db, u1)
+	mr = MoveResponse{}
+	f = callController("POST", "/makemove", "answer=qrcode-2", &ck, env.makeMove)
+	json.Unmarshal([]byte(f.resptext), &mr)
+	if mr.GameArtifacts["action"] != "Card Collected!" || len(mr.State.GetPokerHand()) != 1 || !strings.Contains(mr.PortHTML, "High Card") {
+		t.Errorf("expected the winner to collect cards. got: %v", f.resptext)
+	}
+	f = callController("POST", "/pokerswap", "discard=0", &ck, env.pokerSwap)
+	json.Unmarshal([]byte(f.resptext), &mr)
+	if mr.GameArtifacts["action"] != "Card Dropped" {
+		t.Errorf("expected the winner to manage their hand. got: %v", f.resptext)
+	}
+
+	// Among players who finished the hunt, the better hand ranks first.
+	a := &DisplayUser{Level: HUNT_VICTORY_LEVEL, PokerScore: 10, Points: 1}
+	b := &DisplayUser{Level: HUNT_VICTORY_LEVEL, PokerScore: 5, Points: 9}
+	if !(huntMode{}).RanksAhead(a, b) {
+		t.Errorf("expected the better hand to rank first")
+	}
+}
@@ -24,7 +24,9 @@ import (
 	"log"
 	"math/rand"
 	"net/http"
+	"os"
 	"path"
+	"sync"
 	"time"
 
 	"github.com/sushovande/qr-mixer-game/common"
@@ -34,8 +36,7 @@ import (
 )
 
 var (
-	templateDir    = "templates/"
-	cookieValidity = 24 * 30 * time.Hour
+	templateDir = "templates/"
 )
 
 // Env holds information about connections and templates that's shared across requests
@@ -43,6 +44,11 @@ type Env struct {
 	db  *sql.DB
 	tem *template.Template
 	cgo *CachedGameOptions
+	// chainMu serializes changes to the assassin target chain.
+	chainMu sync.Mutex
+	// rnd is the random source for the game logic. Simulations replace it with a seeded one, so
+	// runs can be repeated.
+	rnd *rand.Rand
 }
 
 func createEnv(dbPath string) (*Env, error) {
@@ -54,10 +60,40 @@ func createEnv(dbPath string) (*Env, error) {
 		db:  dbConn,
 		tem: loadAllTemplateFiles(),
 		cgo: CreateCachedGameOptions(dbConn),
+		rnd: rand.New(newLockedSource(time.Now().UnixNano())),
 	}, nil
 }
 
+// lockedSource is a rand.Source that is safe to use from concurrent requests.
+type lockedSource struct {
+	mu  sync.Mutex
+	src rand.Source
+}
+
+func newLockedSource(seed int64) *lockedSource {
+	return &lockedSource{src: rand.NewSource(seed)}
+}
+
+func (s *lockedSource) Int63() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Int63()
+}
+
+func (s *lockedSource) Seed(seed int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.src.Seed(seed)
+}
+
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "validate" {
+		os.Exit(runValidate(os.Args[2:], os.Stdout))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "simulate" {
+		os.Exit(runSimulate(os.Args[2:], os.Stdout))
+	}
+
 	env, err := createEnv("datastore.db")
 	if err != nil {
 		log.Panic(err)
@@ -65,30 +101,51 @@ func main() {
 	}
 	defer env.db.Close()
 
-	rand.Seed(time.Now().UnixNano())
 	fs := http.FileServer(http.Dir("static"))
 	http.Handle("/static/", http.StripPrefix("/static/", denyDirectoryListings(fs)))
 	http.Handle("/favicon.ico", fs)
 
-	http.HandleFunc("/", env.handler)
+	http.HandleFunc("/", withPlayerCSP(env.handler))
 	http.HandleFunc("/checkregisteredbadge", env.checkRegisteredBadge)
-	http.HandleFunc("/confirmname", env.confirmName)
-	http.HandleFunc("/survey", env.survey)
+	http.HandleFunc("/confirmname", withPlayerCSP(env.confirmName))
+	http.HandleFunc("/survey", withPlayerCSP(env.survey))
 	http.HandleFunc("/submitsurvey", env.submitSurvey)
-	http.HandleFunc("/game", env.gameHandler)  // frontend
-	http.HandleFunc("/makemove", env.makeMove) // backend
+	http.HandleFunc("/game", withPlayerCSP(env.gameHandler)) // frontend
+	http.HandleFunc("/makemove", env.makeMove)               // backend
+	http.HandleFunc("/clue", env.refreshClue)
+	http.HandleFunc("/helpscan", env.helpScan)
+	http.HandleFunc("/submitanswer", env.submitTextAnswer)
+	http.HandleFunc("/submitphoto", env.submitPhoto)
+	http.HandleFunc("/pokerswap", env.pokerSwap)
+	http.HandleFunc("/mycode", withPlayerCSP(env.myCode))
+	http.HandleFunc("/mycode.png", env.myCodeImage)
+	http.HandleFunc("/mybadge", withPlayerCSP(env.myBadge))
+	http.HandleFunc("/mybadge.png", env.myBadgeImage)
+	http.HandleFunc("/mybadgecard.svg", env.myBadgeCard)
 	http.HandleFunc("/logout", env.logout)
 	http.HandleFunc("/9283e316-beaa-4182-b3a6-0937046251ee/allUsers", env.adminAllUsers)
 	http.HandleFunc("/9283e316-beaa-4182-b3a6-0937046251ee/allLogs", env.adminAllLogs)
 	http.HandleFunc("/9283e316-beaa-4182-b3a6-0937046251ee/userLogs/", env.adminUserLogs)
 	http.HandleFunc("/9283e316-beaa-4182-b3a6-0937046251ee/questions", env.adminRenderQuestions)
 	http.HandleFunc("/9283e316-beaa-4182-b3a6-0937046251ee/saveQuestions", env.adminSaveQuestions)
+	http.HandleFunc("/9283e316-beaa-4182-b3a6-0937046251ee/retireQuestion", env.adminRetireQuestion)
+	http.HandleFunc("/9283e316-beaa-4182-b3a6-0937046251ee/suggestQuestions", env.adminSuggestQuestions)
+	http.HandleFunc("/9283e316-beaa-4182-b3a6-0937046251ee/ordering", env.adminRenderOrdering)
+	http.HandleFunc("/9283e316-beaa-4182-b3a6-0937046251ee/applyOrdering", env.adminApplyOrdering)
 	http.HandleFunc("/9283e316-beaa-4182-b3a6-0937046251ee/updateUser", env.adminUpdateUser)
 	http.HandleFunc("/9283e316-beaa-4182-b3a6-0937046251ee/manageUsers", env.adminRenderManagerUsers)
 	http.HandleFunc("/9283e316-beaa-4182-b3a6-0937046251ee/saveUserQrMapping", env.adminSaveUserQrMapping)
 	http.HandleFunc("/9283e316-beaa-4182-b3a6-0937046251ee/card/", env.adminGetCard)
 	http.HandleFunc("/9283e316-beaa-4182-b3a6-0937046251ee/printBadges", env.adminRenderPrintBadges)
 	http.HandleFunc("/9283e316-beaa-4182-b3a6-0937046251ee/qrimage", env.adminGetQrImage)
+	http.HandleFunc("/9283e316-beaa-4182-b3a6-0937046251ee/photoReview", env.adminRenderPhotoReview)
+	http.HandleFunc("/9283e316-beaa-4182-b3a6-0937046251ee/photo", env.adminGetPhoto)
+	http.HandleFunc("/9283e316-beaa-4182-b3a6-0937046251ee/reviewPhoto", env.adminReviewPhoto)
+	http.HandleFunc("/9283e316-beaa-4182-b3a6-0937046251ee/dropPlayer", env.adminAssassinDrop)
+	http.HandleFunc("/9283e316-beaa-4182-b3a6-0937046251ee/resetChain", env.adminAssassinReset)
+	http.HandleFunc("/9283e316-beaa-4182-b3a6-0937046251ee/events", env.adminRenderEvents)
+	http.HandleFunc("/9283e316-beaa-4182-b3a6-0937046251ee/startEvent", env.adminStartEvent)
+	http.HandleFunc("/9283e316-beaa-4182-b3a6-0937046251ee/stopEvent", env.adminStopEvent)
 
 	flagPort := flag.String("port", "8080", "what port to listen at")
 	flag.Parse()
@@ -140,21 +197,54 @@ func (env *Env) gameHandler(w http.ResponseWriter, r *http.Request) {
 		common.RenderTemplate(w, env.tem, "nocookie.html", nil)
 		return
 	}
-	sqs, err := env.cgo.GetGameQSet()
+	mode, err := env.CurrentGameMode()
+	if err != nil {
+		common.Should500(err, w, "There was a problem figuring out the game, maybe try again?")
+		return
+	}
+	if common.Should500(env.prepareAndSave(mode, u), w, "There was a problem setting up your game, maybe try again?") {
+		return
+	}
+
+	clue, err := mode.Clue(env, u)
 	if err != nil {
 		common.Should500(err, w, "There was a problem figuring out the questions, maybe try again?")
 		return
 	}
 
-	qn := GetQuestionByIndex(sqs, *u.State.UserLevel)
-	qnht := qn.GetQuestionHtml()
+	banner, err := env.currentEventBanner()
+	if common.Should500(err, w, "There was a problem reading the events, maybe try again?") {
+		return
+	}
+	gc, err := env.cgo.GetGameConfig()
+	if common.Should500(err, w, "There was a problem reading the game settings, maybe try again?") {
+		return
+	}
+	qrm, err := env.cgo.GetQRMappings()
+	if common.Should500(err, w, "There was a problem reading the players, maybe try again?") {
+		return
+	}
 
 	renderData := struct {
-		U    *StateRow
-		Clue template.HTML
+		U           *StateRow
+		Clue        template.HTML
+		AnswerType  string
+		EventBanner string
+		Hearts      []int64
+		ShowMyCode  bool
+		ShowMyBadge bool
+		RefreshSec  int64
+		Helping     []*qrpb.QRMapping
 	}{
 		u,
-		template.HTML(qnht),
+		template.HTML(clue.HTML),
+		clue.AnswerType,
+		banner,
+		HeartNumbers(gc),
+		acceptsRotating(gc),
+		qrm.LookupByUsername(u.Username).GetDigitalOnly() && acceptsPrinted(gc),
+		ClueRefreshSec(gc),
+		HelpedPlayers(qrm, u.Username),
 	}
 
 	common.RenderTemplate(w, env.tem, "hascookie.html", renderData)
@@ -169,7 +259,7 @@ func (env *Env) makeMove(w http.ResponseWriter, r *http.Request) {
 	}
 
 	u, err := GetUserStateByCookie(env.db, ck.Value)
-	if err != nil {
+	if err != nil || u == nil {
 		common.RespondHTTP401(w)
 		return
 	}
@@ -180,14 +270,72 @@ func (env *Env) makeMove(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// do logic and respond
-	mr := NewMoveResponse()
-	stepResult, err := env.Step(u.State, a)
+	mode, err := env.CurrentGameMode()
+	if err != nil {
+		common.Should500(err, w, "There was a problem figuring out the game, maybe try again?")
+		return
+	}
+
+	stepResult, err := mode.Step(env, u, a)
 	if err != nil {
 		common.Should500(err, w, fmt.Sprintf("You scanned someone unexpected: %v", err.Error()))
 		return
 	}
 
+	env.recordStep(w, u, stepResult, qrpb.ActionLog_ACTION_CODE_SCAN)
+}
+
+// submitTextAnswer is the backend for questions where the player types the answer instead of scanning it.
+func (env *Env) submitTextAnswer(w http.ResponseWriter, r *http.Request) {
+	log.Println("Req: ", r.URL)
+	ck, err := r.Cookie("sid")
+	if err != nil {
+		common.RespondHTTP401(w)
+		return
+	}
+
+	u, err := GetUserStateByCookie(env.db, ck.Value)
+	if err != nil || u == nil {
+		common.RespondHTTP401(w)
+		return
+	}
+
+	a := r.FormValue("answer")
+	if len(a) == 0 {
+		http.NotFound(w, r)
+		return
+	}
+
+	mode, err := env.CurrentGameMode()
+	if common.Should500(err, w, "There was a problem figuring out the game, maybe try again?") {
+		return
+	}
+	fa, ok := mode.(FormAnswerer)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	stepResult, err := fa.StepText(env, u, a)
+	if err != nil {
+		common.Should500(err, w, fmt.Sprintf("We could not check your answer: %v", err.Error()))
+		return
+	}
+
+	env.recordStep(w, u, stepResult, qrpb.ActionLog_ACTION_TEXT_ANSWER)
+}
+
+// recordStep saves the result of a step to the db, logs it, and responds with the MoveResponse json.
+func (env *Env) recordStep(w http.ResponseWriter, u *StateRow, stepResult StepResponse, actionType qrpb.ActionLog_ActionType) {
+	if common.Should500(env.saveStep(u, stepResult, actionType), w, "could not record your action, please try again") {
+		return
+	}
+
+	// Prepare after logging, so the mode sees this move in the player's history.
+	env.respondWithClue(w, u, stepResult.actionString)
+}
+
+// saveStep saves the result of a step to u in the db, and logs it.
+func (env *Env) saveStep(u *StateRow, stepResult StepResponse, actionType qrpb.ActionLog_ActionType) error {
 	lr := NewLogRow()
 	lr.Username = u.Username
 	lr.Updated = time.Now().UnixNano() / 1000
@@ -196,27 +344,78 @@ func (env *Env) makeMove(w http.ResponseWriter, r *http.Request) {
 		TimestampUsec: proto.Int64(lr.Updated),
 		ClueShortName: proto.String(stepResult.scannedClue),
 		Result:        &stepResult.actionResult,
-		Type:          qrpb.ActionLog_ACTION_CODE_SCAN.Enum(),
+		Type:          actionType.Enum(),
 	}
 
 	u.State = stepResult.newState
 
-	if common.Should500(UpdateUserDetails(env.GetDb(), u), w, "could not record your action, please try again") {
+	save := func(db queryer) error {
+		if err := UpdateUserDetails(db, u); err != nil {
+			return err
+		}
+		return AddActionLog(db, &lr)
+	}
+	if stepResult.applyTx == nil {
+		return save(env.GetDb())
+	}
+	return env.withChainTx(func(tx *sql.Tx) error {
+		if err := stepResult.applyTx(tx); err != nil {
+			return err
+		}
+		return save(tx)
+	})
+}
+
+// refreshClue responds with the player's current clue, so an open game page can pick up hints
+// revealed while the player waits.
+func (env *Env) refreshClue(w http.ResponseWriter, r *http.Request) {
+	log.Println("Req: ", r.URL)
+	ck, err := r.Cookie("sid")
+	if err != nil {
+		common.RespondHTTP401(w)
 		return
 	}
-	if common.Should500(AddActionLog(env.GetDb(), &lr), w, "could not log your action, refresh the page") {
+
+	u, err := GetUserStateByCookie(env.db, ck.Value)
+	if err != nil || u == nil {
+		common.RespondHTTP401(w)
 		return
 	}
-	sqs, err := env.cgo.GetGameQSet()
+	env.respondWithClue(w, u, "")
+}
+
+// respondWithClue prepares the player for their next clue, and responds with the MoveResponse
+// json. action is left out of the response when it is empty.
+func (env *Env) respondWithClue(w http.ResponseWriter, u *StateRow, action string) {
+	mr := NewMoveResponse()
+	mode, err := env.CurrentGameMode()
+	if common.Should500(err, w, "could not figure out the game, refresh the page") {
+		return
+	}
+	if common.Should500(env.prepareAndSave(mode, u), w, "could not set up your next clue, refresh the page") {
+		return
+	}
+	clue, err := mode.Clue(env, u)
 	if err != nil {
 		common.Should500(err, w, "There was a problem figuring out the questions, maybe try again?")
 		return
 	}
 
+	banner, err := env.currentEventBanner()
+	if common.Should500(err, w, "could not read the events, refresh the page") {
+		return
+	}
+
 	mr.GameArtifacts = make(map[string]string, 0)
-	mr.GameArtifacts["action"] = stepResult.actionString
+	if len(action) > 0 {
+		mr.GameArtifacts["action"] = action
+	}
+	mr.GameArtifacts["events"] = banner
 	mr.State = u.State
-	mr.PortHTML = GetQuestionByIndex(sqs, *u.State.UserLevel).GetQuestionHtml()
+	mr.PortHTML = clue.HTML
+	if len(clue.AnswerType) > 0 {
+		mr.GameArtifacts["answerType"] = clue.AnswerType
+	}
 	js, err := json.Marshal(mr)
 	if common.Should500(err, w, "error encoding json") {
 		return
@@ -80,6 +80,63 @@ func (CardSuit) EnumDescriptor() ([]byte, []int) {
 	return file_gamedata_proto_rawDescGZIP(), []int{0}
 }
 
+// GlobalEventKind is a game-wide modifier an organizer can switch on for a
+// while.
+type GlobalEventKind int32
+
+const (
+	GlobalEventKind_GLOBAL_EVENT_UNSPECIFIED GlobalEventKind = 0
+	// Tokens are twice as likely to be granted for a correct answer.
+	GlobalEventKind_DOUBLE_TOKEN_CHANCE GlobalEventKind = 1
+	// Wrong answers cost no lives.
+	GlobalEventKind_FREE_WRONG_SCANS GlobalEventKind = 2
+	// Every correct answer also gives a life.
+	GlobalEventKind_LIFE_PER_CORRECT GlobalEventKind = 3
+)
+
+// Enum value maps for GlobalEventKind.
+var (
+	GlobalEventKind_name = map[int32]string{
+		0: "GLOBAL_EVENT_UNSPECIFIED",
+		1: "DOUBLE_TOKEN_CHANCE",
+		2: "FREE_WRONG_SCANS",
+		3: "LIFE_PER_CORRECT",
+	}
+	GlobalEventKind_value = map[string]int32{
+		"GLOBAL_EVENT_UNSPECIFIED": 0,
+		"DOUBLE_TOKEN_CHANCE":      1,
+		"FREE_WRONG_SCANS":         2,
+		"LIFE_PER_CORRECT":         3,
+	}
+)
+
+func (x GlobalEventKind) Enum() *GlobalEventKind {
+	p := new(GlobalEventKind)
+	*p = x
+	return p
+}
+
+func (x GlobalEventKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (GlobalEventKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_gamedata_proto_enumTypes[1].Descriptor()
+}
+
+func (GlobalEventKind) Type() protoreflect.EnumType {
+	return &file_gamedata_proto_enumTypes[1]
+}
+
+func (x GlobalEventKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use GlobalEventKind.Descriptor instead.
+func (GlobalEventKind) EnumDescriptor() ([]byte, []int) {
+	return file_gamedata_proto_rawDescGZIP(), []int{1}
+}
+
 type GQType int32
 
 const (
@@ -87,6 +144,10 @@ const (
 	GQType_USERNAME_LIST      GQType = 1
 	GQType_SURVEY_ANS         GQType = 2
 	GQType_ANY_PERSON         GQType = 3
+	GQType_TEXT_ANSWER        GQType = 4
+	GQType_PHOTO_PROOF        GQType = 5
+	GQType_CHECKPOINT_ROUTE   GQType = 6
+	GQType_SECRET_BUDDY       GQType = 7
 )
 
 // Enum value maps for GQType.
@@ -96,12 +157,20 @@ var (
 		1: "USERNAME_LIST",
 		2: "SURVEY_ANS",
 		3: "ANY_PERSON",
+		4: "TEXT_ANSWER",
+		5: "PHOTO_PROOF",
+		6: "CHECKPOINT_ROUTE",
+		7: "SECRET_BUDDY",
 	}
 	GQType_value = map[string]int32{
 		"GQTYPE_UNSPECIFIED": 0,
 		"USERNAME_LIST":      1,
 		"SURVEY_ANS":         2,
 		"ANY_PERSON":         3,
+		"TEXT_ANSWER":        4,
+		"PHOTO_PROOF":        5,
+		"CHECKPOINT_ROUTE":   6,
+		"SECRET_BUDDY":       7,
 	}
 )
 
@@ -116,11 +185,11 @@ func (x GQType) String() string {
 }
 
 func (GQType) Descriptor() protoreflect.EnumDescriptor {
-	return file_gamedata_proto_enumTypes[1].Descriptor()
+	return file_gamedata_proto_enumTypes[2].Descriptor()
 }
 
 func (GQType) Type() protoreflect.EnumType {
-	return &file_gamedata_proto_enumTypes[1]
+	return &file_gamedata_proto_enumTypes[2]
 }
 
 func (x GQType) Number() protoreflect.EnumNumber {
@@ -129,7 +198,61 @@ func (x GQType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use GQType.Descriptor instead.
 func (GQType) EnumDescriptor() ([]byte, []int) {
-	return file_gamedata_proto_rawDescGZIP(), []int{1}
+	return file_gamedata_proto_rawDescGZIP(), []int{2}
+}
+
+// PhotoStatus is where a photo uploaded for a PHOTO_PROOF question is in the
+// organizer review queue.
+type PhotoStatus int32
+
+const (
+	PhotoStatus_PHOTO_STATUS_UNSPECIFIED PhotoStatus = 0
+	PhotoStatus_PHOTO_PENDING            PhotoStatus = 1
+	PhotoStatus_PHOTO_APPROVED           PhotoStatus = 2
+	PhotoStatus_PHOTO_REJECTED           PhotoStatus = 3
+)
+
+// Enum value maps for PhotoStatus.
+var (
+	PhotoStatus_name = map[int32]string{
+		0: "PHOTO_STATUS_UNSPECIFIED",
+		1: "PHOTO_PENDING",
+		2: "PHOTO_APPROVED",
+		3: "PHOTO_REJECTED",
+	}
+	PhotoStatus_value = map[string]int32{
+		"PHOTO_STATUS_UNSPECIFIED": 0,
+		"PHOTO_PENDING":            1,
+		"PHOTO_APPROVED":           2,
+		"PHOTO_REJECTED":           3,
+	}
+)
+
+func (x PhotoStatus) Enum() *PhotoStatus {
+	p := new(PhotoStatus)
+	*p = x
+	return p
+}
+
+func (x PhotoStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PhotoStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_gamedata_proto_enumTypes[3].Descriptor()
+}
+
+func (PhotoStatus) Type() protoreflect.EnumType {
+	return &file_gamedata_proto_enumTypes[3]
+}
+
+func (x PhotoStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PhotoStatus.Descriptor instead.
+func (PhotoStatus) EnumDescriptor() ([]byte, []int) {
+	return file_gamedata_proto_rawDescGZIP(), []int{3}
 }
 
 type SurveyType int32
@@ -162,11 +285,11 @@ func (x SurveyType) String() string {
 }
 
 func (SurveyType) Descriptor() protoreflect.EnumDescriptor {
-	return file_gamedata_proto_enumTypes[2].Descriptor()
+	return file_gamedata_proto_enumTypes[4].Descriptor()
 }
 
 func (SurveyType) Type() protoreflect.EnumType {
-	return &file_gamedata_proto_enumTypes[2]
+	return &file_gamedata_proto_enumTypes[4]
 }
 
 func (x SurveyType) Number() protoreflect.EnumNumber {
@@ -175,14 +298,136 @@ func (x SurveyType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use SurveyType.Descriptor instead.
 func (SurveyType) EnumDescriptor() ([]byte, []int) {
-	return file_gamedata_proto_rawDescGZIP(), []int{2}
+	return file_gamedata_proto_rawDescGZIP(), []int{4}
+}
+
+type BadgeCodes int32
+
+const (
+	// Same as PRINTED_ONLY.
+	BadgeCodes_BADGE_CODES_UNSPECIFIED BadgeCodes = 0
+	// Only the QR code printed on the badge.
+	BadgeCodes_PRINTED_ONLY BadgeCodes = 1
+	// Only the rotating code on the player's phone. Printed codes of props and
+	// of people who have not registered still count.
+	BadgeCodes_ROTATING_ONLY BadgeCodes = 2
+	// Either one.
+	BadgeCodes_PRINTED_OR_ROTATING BadgeCodes = 3
+)
+
+// Enum value maps for BadgeCodes.
+var (
+	BadgeCodes_name = map[int32]string{
+		0: "BADGE_CODES_UNSPECIFIED",
+		1: "PRINTED_ONLY",
+		2: "ROTATING_ONLY",
+		3: "PRINTED_OR_ROTATING",
+	}
+	BadgeCodes_value = map[string]int32{
+		"BADGE_CODES_UNSPECIFIED": 0,
+		"PRINTED_ONLY":            1,
+		"ROTATING_ONLY":           2,
+		"PRINTED_OR_ROTATING":     3,
+	}
+)
+
+func (x BadgeCodes) Enum() *BadgeCodes {
+	p := new(BadgeCodes)
+	*p = x
+	return p
+}
+
+func (x BadgeCodes) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BadgeCodes) Descriptor() protoreflect.EnumDescriptor {
+	return file_gamedata_proto_enumTypes[5].Descriptor()
+}
+
+func (BadgeCodes) Type() protoreflect.EnumType {
+	return &file_gamedata_proto_enumTypes[5]
+}
+
+func (x BadgeCodes) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BadgeCodes.Descriptor instead.
+func (BadgeCodes) EnumDescriptor() ([]byte, []int) {
+	return file_gamedata_proto_rawDescGZIP(), []int{5}
+}
+
+// AssassinStatus is whether a player is still part of the target chain in the
+// assassin game mode.
+type AssassinStatus int32
+
+const (
+	AssassinStatus_ASSASSIN_STATUS_UNSPECIFIED AssassinStatus = 0
+	AssassinStatus_ASSASSIN_ALIVE              AssassinStatus = 1
+	AssassinStatus_ASSASSIN_TAGGED             AssassinStatus = 2
+	AssassinStatus_ASSASSIN_DROPPED            AssassinStatus = 3
+)
+
+// Enum value maps for AssassinStatus.
+var (
+	AssassinStatus_name = map[int32]string{
+		0: "ASSASSIN_STATUS_UNSPECIFIED",
+		1: "ASSASSIN_ALIVE",
+		2: "ASSASSIN_TAGGED",
+		3: "ASSASSIN_DROPPED",
+	}
+	AssassinStatus_value = map[string]int32{
+		"ASSASSIN_STATUS_UNSPECIFIED": 0,
+		"ASSASSIN_ALIVE":              1,
+		"ASSASSIN_TAGGED":             2,
+		"ASSASSIN_DROPPED":            3,
+	}
+)
+
+func (x AssassinStatus) Enum() *AssassinStatus {
+	p := new(AssassinStatus)
+	*p = x
+	return p
+}
+
+func (x AssassinStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AssassinStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_gamedata_proto_enumTypes[6].Descriptor()
+}
+
+func (AssassinStatus) Type() protoreflect.EnumType {
+	return &file_gamedata_proto_enumTypes[6]
+}
+
+func (x AssassinStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AssassinStatus.Descriptor instead.
+func (AssassinStatus) EnumDescriptor() ([]byte, []int) {
+	return file_gamedata_proto_rawDescGZIP(), []int{6}
 }
 
 type ActionLog_ActionType int32
 
 const (
-	ActionLog_ACTION_UNSPECIFIED ActionLog_ActionType = 0
-	ActionLog_ACTION_CODE_SCAN   ActionLog_ActionType = 1
+	ActionLog_ACTION_UNSPECIFIED  ActionLog_ActionType = 0
+	ActionLog_ACTION_CODE_SCAN    ActionLog_ActionType = 1
+	ActionLog_ACTION_TEXT_ANSWER  ActionLog_ActionType = 2
+	ActionLog_ACTION_PHOTO_UPLOAD ActionLog_ActionType = 3
+	ActionLog_ACTION_PHOTO_REVIEW ActionLog_ActionType = 4
+	// Someone else scanned this player in the assassin game mode.
+	ActionLog_ACTION_TAGGED ActionLog_ActionType = 5
+	// The player swapped a card in or out of their poker hand.
+	ActionLog_ACTION_CARD_SWAP ActionLog_ActionType = 6
+	// A hint was revealed because the player was stuck.
+	ActionLog_ACTION_HINT ActionLog_ActionType = 7
+	// The player's helper scanned a badge for them. See PlayerHandicap.
+	ActionLog_ACTION_HELPER_SCAN ActionLog_ActionType = 8
 )
 
 // Enum value maps for ActionLog_ActionType.
@@ -190,10 +435,24 @@ var (
 	ActionLog_ActionType_name = map[int32]string{
 		0: "ACTION_UNSPECIFIED",
 		1: "ACTION_CODE_SCAN",
+		2: "ACTION_TEXT_ANSWER",
+		3: "ACTION_PHOTO_UPLOAD",
+		4: "ACTION_PHOTO_REVIEW",
+		5: "ACTION_TAGGED",
+		6: "ACTION_CARD_SWAP",
+		7: "ACTION_HINT",
+		8: "ACTION_HELPER_SCAN",
 	}
 	ActionLog_ActionType_value = map[string]int32{
-		"ACTION_UNSPECIFIED": 0,
-		"ACTION_CODE_SCAN":   1,
+		"ACTION_UNSPECIFIED":  0,
+		"ACTION_CODE_SCAN":    1,
+		"ACTION_TEXT_ANSWER":  2,
+		"ACTION_PHOTO_UPLOAD": 3,
+		"ACTION_PHOTO_REVIEW": 4,
+		"ACTION_TAGGED":       5,
+		"ACTION_CARD_SWAP":    6,
+		"ACTION_HINT":         7,
+		"ACTION_HELPER_SCAN":  8,
 	}
 )
 
@@ -208,11 +467,11 @@ func (x ActionLog_ActionType) String() string {
 }
 
 func (ActionLog_ActionType) Descriptor() protoreflect.EnumDescriptor {
-	return file_gamedata_proto_enumTypes[3].Descriptor()
+	return file_gamedata_proto_enumTypes[7].Descriptor()
 }
 
 func (ActionLog_ActionType) Type() protoreflect.EnumType {
-	return &file_gamedata_proto_enumTypes[3]
+	return &file_gamedata_proto_enumTypes[7]
 }
 
 func (x ActionLog_ActionType) Number() protoreflect.EnumNumber {
@@ -221,7 +480,7 @@ func (x ActionLog_ActionType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use ActionLog_ActionType.Descriptor instead.
 func (ActionLog_ActionType) EnumDescriptor() ([]byte, []int) {
-	return file_gamedata_proto_rawDescGZIP(), []int{4, 0}
+	return file_gamedata_proto_rawDescGZIP(), []int{8, 0}
 }
 
 type ActionLog_ActionResult int32
@@ -234,18 +493,52 @@ const (
 	ActionLog_RESULT_ALREADY_DEAD       ActionLog_ActionResult = 4
 	ActionLog_RESULT_GRABBED_METAL      ActionLog_ActionResult = 5
 	ActionLog_RESULT_NO_GRABBED_METAL   ActionLog_ActionResult = 6
+	// The action had no effect on the game, for example a scan on a question
+	// that expects a typed answer.
+	ActionLog_RESULT_IGNORED        ActionLog_ActionResult = 7
+	ActionLog_RESULT_PHOTO_PENDING  ActionLog_ActionResult = 8
+	ActionLog_RESULT_PHOTO_REJECTED ActionLog_ActionResult = 9
+	ActionLog_RESULT_CHECKPOINT     ActionLog_ActionResult = 10
+	ActionLog_RESULT_ROUTE_RESET    ActionLog_ActionResult = 11
+	ActionLog_RESULT_BINGO_CELL     ActionLog_ActionResult = 12
+	ActionLog_RESULT_TAGGED_TARGET  ActionLog_ActionResult = 13
+	ActionLog_RESULT_WAS_TAGGED     ActionLog_ActionResult = 14
+	ActionLog_RESULT_CARD_COLLECTED ActionLog_ActionResult = 15
+	ActionLog_RESULT_CARD_OFFERED   ActionLog_ActionResult = 16
+	// The player ran out of attempts and moved on to the next question.
+	ActionLog_RESULT_SKIPPED ActionLog_ActionResult = 17
+	// A wrong answer on a question that does not cost any lives.
+	ActionLog_RESULT_WRONG_ANSWER  ActionLog_ActionResult = 18
+	ActionLog_RESULT_HINT_REVEALED ActionLog_ActionResult = 19
+	// The player swapped, dropped or threw away a poker card. It does not
+	// answer anything, so the ordering page does not count it.
+	ActionLog_RESULT_CARD_SWAPPED ActionLog_ActionResult = 20
 )
 
 // Enum value maps for ActionLog_ActionResult.
 var (
 	ActionLog_ActionResult_name = map[int32]string{
-		0: "RESULT_UNSPECIFIED",
-		1: "RESULT_PROGRESS",
-		2: "RESULT_LOST_LIFE",
-		3: "RESULT_ALREADY_VICTORIOUS",
-		4: "RESULT_ALREADY_DEAD",
-		5: "RESULT_GRABBED_METAL",
-		6: "RESULT_NO_GRABBED_METAL",
+		0:  "RESULT_UNSPECIFIED",
+		1:  "RESULT_PROGRESS",
+		2:  "RESULT_LOST_LIFE",
+		3:  "RESULT_ALREADY_VICTORIOUS",
+		4:  "RESULT_ALREADY_DEAD",
+		5:  "RESULT_GRABBED_METAL",
+		6:  "RESULT_NO_GRABBED_METAL",
+		7:  "RESULT_IGNORED",
+		8:  "RESULT_PHOTO_PENDING",
+		9:  "RESULT_PHOTO_REJECTED",
+		10: "RESULT_CHECKPOINT",
+		11: "RESULT_ROUTE_RESET",
+		12: "RESULT_BINGO_CELL",
+		13: "RESULT_TAGGED_TARGET",
+		14: "RESULT_WAS_TAGGED",
+		15: "RESULT_CARD_COLLECTED",
+		16: "RESULT_CARD_OFFERED",
+		17: "RESULT_SKIPPED",
+		18: "RESULT_WRONG_ANSWER",
+		19: "RESULT_HINT_REVEALED",
+		20: "RESULT_CARD_SWAPPED",
 	}
 	ActionLog_ActionResult_value = map[string]int32{
 		"RESULT_UNSPECIFIED":        0,
@@ -255,6 +548,20 @@ var (
 		"RESULT_ALREADY_DEAD":       4,
 		"RESULT_GRABBED_METAL":      5,
 		"RESULT_NO_GRABBED_METAL":   6,
+		"RESULT_IGNORED":            7,
+		"RESULT_PHOTO_PENDING":      8,
+		"RESULT_PHOTO_REJECTED":     9,
+		"RESULT_CHECKPOINT":         10,
+		"RESULT_ROUTE_RESET":        11,
+		"RESULT_BINGO_CELL":         12,
+		"RESULT_TAGGED_TARGET":      13,
+		"RESULT_WAS_TAGGED":         14,
+		"RESULT_CARD_COLLECTED":     15,
+		"RESULT_CARD_OFFERED":       16,
+		"RESULT_SKIPPED":            17,
+		"RESULT_WRONG_ANSWER":       18,
+		"RESULT_HINT_REVEALED":      19,
+		"RESULT_CARD_SWAPPED":       20,
 	}
 )
 
@@ -269,11 +576,11 @@ func (x ActionLog_ActionResult) String() string {
 }
 
 func (ActionLog_ActionResult) Descriptor() protoreflect.EnumDescriptor {
-	return file_gamedata_proto_enumTypes[4].Descriptor()
+	return file_gamedata_proto_enumTypes[8].Descriptor()
 }
 
 func (ActionLog_ActionResult) Type() protoreflect.EnumType {
-	return &file_gamedata_proto_enumTypes[4]
+	return &file_gamedata_proto_enumTypes[8]
 }
 
 func (x ActionLog_ActionResult) Number() protoreflect.EnumNumber {
@@ -282,7 +589,7 @@ func (x ActionLog_ActionResult) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use ActionLog_ActionResult.Descriptor instead.
 func (ActionLog_ActionResult) EnumDescriptor() ([]byte, []int) {
-	return file_gamedata_proto_rawDescGZIP(), []int{4, 1}
+	return file_gamedata_proto_rawDescGZIP(), []int{8, 1}
 }
 
 // GUser represents a player who has signed up for the game and
@@ -295,6 +602,9 @@ type GUser struct {
 	Username      *string         `protobuf:"bytes,3,opt,name=username,proto3,oneof" json:"username,omitempty"`
 	Name          *string         `protobuf:"bytes,2,opt,name=name,proto3,oneof" json:"name,omitempty"`
 	SurveyAnswers []*SurveyAnswer `protobuf:"bytes,4,rep,name=survey_answers,json=surveyAnswers,proto3" json:"survey_answers,omitempty"`
+	// Secret issued at registration, from which the rotating code on the
+	// player's phone is derived. Never shown to other players.
+	PresenceSecret *string `protobuf:"bytes,5,opt,name=presence_secret,json=presenceSecret,proto3,oneof" json:"presence_secret,omitempty"`
 }
 
 func (x *GUser) Reset() {
@@ -350,6 +660,13 @@ func (x *GUser) GetSurveyAnswers() []*SurveyAnswer {
 	return nil
 }
 
+func (x *GUser) GetPresenceSecret() string {
+	if x != nil && x.PresenceSecret != nil {
+		return *x.PresenceSecret
+	}
+	return ""
+}
+
 // QRMapping is an entry that associates a player with various properties,
 // like their name, username, and card suit. This is set by the game organizer.
 type QRMapping struct {
@@ -371,6 +688,11 @@ type QRMapping struct {
 	// The face value of this card. Number 1 represents Ace, and numbers
 	// 11 to 13 represent the face cards (J, Q, K).
 	CardRank *int64 `protobuf:"varint,5,opt,name=card_rank,json=cardRank,proto3,oneof" json:"card_rank,omitempty"`
+	// Adjustments to the game for this player, set by the organizer.
+	Handicap *PlayerHandicap `protobuf:"bytes,6,opt,name=handicap,proto3,oneof" json:"handicap,omitempty"`
+	// The player has no printed badge. They join with a link from the organizer
+	// and show their badge on their phone instead.
+	DigitalOnly *bool `protobuf:"varint,7,opt,name=digital_only,json=digitalOnly,proto3,oneof" json:"digital_only,omitempty"`
 }
 
 func (x *QRMapping) Reset() {
@@ -440,6 +762,98 @@ func (x *QRMapping) GetCardRank() int64 {
 	return 0
 }
 
+func (x *QRMapping) GetHandicap() *PlayerHandicap {
+	if x != nil {
+		return x.Handicap
+	}
+	return nil
+}
+
+func (x *QRMapping) GetDigitalOnly() bool {
+	if x != nil && x.DigitalOnly != nil {
+		return *x.DigitalOnly
+	}
+	return false
+}
+
+// PlayerHandicap makes the game easier for one player, for example a guest
+// who cannot walk around much, a child, or someone who arrives late.
+type PlayerHandicap struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Lives given on top of the usual starting lives when the player registers.
+	ExtraLives *int64 `protobuf:"varint,1,opt,name=extra_lives,json=extraLives,proto3,oneof" json:"extra_lives,omitempty"`
+	// Wrong answers never cost this player a life.
+	NoPenalty *bool `protobuf:"varint,2,opt,name=no_penalty,json=noPenalty,proto3,oneof" json:"no_penalty,omitempty"`
+	// Username of someone, usually a friend or family member, who can scan
+	// badges for the player from their own phone. Limited by helper_scans.
+	Helper *string `protobuf:"bytes,3,opt,name=helper,proto3,oneof" json:"helper,omitempty"`
+	// How many scans the helper can make for the player.
+	HelperScans *int64 `protobuf:"varint,4,opt,name=helper_scans,json=helperScans,proto3,oneof" json:"helper_scans,omitempty"`
+}
+
+func (x *PlayerHandicap) Reset() {
+	*x = PlayerHandicap{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gamedata_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PlayerHandicap) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlayerHandicap) ProtoMessage() {}
+
+func (x *PlayerHandicap) ProtoReflect() protoreflect.Message {
+	mi := &file_gamedata_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlayerHandicap.ProtoReflect.Descriptor instead.
+func (*PlayerHandicap) Descriptor() ([]byte, []int) {
+	return file_gamedata_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *PlayerHandicap) GetExtraLives() int64 {
+	if x != nil && x.ExtraLives != nil {
+		return *x.ExtraLives
+	}
+	return 0
+}
+
+func (x *PlayerHandicap) GetNoPenalty() bool {
+	if x != nil && x.NoPenalty != nil {
+		return *x.NoPenalty
+	}
+	return false
+}
+
+func (x *PlayerHandicap) GetHelper() string {
+	if x != nil && x.Helper != nil {
+		return *x.Helper
+	}
+	return ""
+}
+
+func (x *PlayerHandicap) GetHelperScans() int64 {
+	if x != nil && x.HelperScans != nil {
+		return *x.HelperScans
+	}
+	return 0
+}
+
 // A set of name associations.
 type QRMappingSet struct {
 	state         protoimpl.MessageState
@@ -452,7 +866,7 @@ type QRMappingSet struct {
 func (x *QRMappingSet) Reset() {
 	*x = QRMappingSet{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_gamedata_proto_msgTypes[2]
+		mi := &file_gamedata_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -465,7 +879,7 @@ func (x *QRMappingSet) String() string {
 func (*QRMappingSet) ProtoMessage() {}
 
 func (x *QRMappingSet) ProtoReflect() protoreflect.Message {
-	mi := &file_gamedata_proto_msgTypes[2]
+	mi := &file_gamedata_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -478,7 +892,7 @@ func (x *QRMappingSet) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use QRMappingSet.ProtoReflect.Descriptor instead.
 func (*QRMappingSet) Descriptor() ([]byte, []int) {
-	return file_gamedata_proto_rawDescGZIP(), []int{2}
+	return file_gamedata_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *QRMappingSet) GetQrMappings() []*QRMapping {
@@ -500,12 +914,49 @@ type GameState struct {
 	HasCu     *bool  `protobuf:"varint,4,opt,name=has_cu,json=hasCu,proto3,oneof" json:"has_cu,omitempty"`
 	HasSn     *bool  `protobuf:"varint,5,opt,name=has_sn,json=hasSn,proto3,oneof" json:"has_sn,omitempty"`
 	HasZn     *bool  `protobuf:"varint,6,opt,name=has_zn,json=hasZn,proto3,oneof" json:"has_zn,omitempty"`
+	// Set while a photo uploaded for a PHOTO_PROOF question is waiting for an
+	// organizer to review it.
+	PhotoPending *bool `protobuf:"varint,7,opt,name=photo_pending,json=photoPending,proto3,oneof" json:"photo_pending,omitempty"`
+	// How many checkpoints of a CHECKPOINT_ROUTE question have been scanned in
+	// order so far on the current level.
+	RouteProgress *int64 `protobuf:"varint,8,opt,name=route_progress,json=routeProgress,proto3,oneof" json:"route_progress,omitempty"`
+	// The player's bingo card, row by row. Only used in the bingo game mode.
+	BingoCells []*BingoCell `protobuf:"bytes,9,rep,name=bingo_cells,json=bingoCells,proto3" json:"bingo_cells,omitempty"`
+	// How many rows, columns or diagonals of the bingo card are complete.
+	BingoLines *int64 `protobuf:"varint,10,opt,name=bingo_lines,json=bingoLines,proto3,oneof" json:"bingo_lines,omitempty"`
+	BingoWon   *bool  `protobuf:"varint,11,opt,name=bingo_won,json=bingoWon,proto3,oneof" json:"bingo_won,omitempty"`
+	// The people picked as this player's secret buddy, one per SECRET_BUDDY
+	// question reached so far.
+	Buddies []*BuddyAssignment `protobuf:"bytes,12,rep,name=buddies,proto3" json:"buddies,omitempty"`
+	// The badge cards the player holds in the poker game mode, at most five.
+	PokerHand []*PokerCard `protobuf:"bytes,13,rep,name=poker_hand,json=pokerHand,proto3" json:"poker_hand,omitempty"`
+	// A card scanned while the hand was full, waiting for the player to swap it
+	// in or throw it away.
+	PokerOffer *PokerCard `protobuf:"bytes,14,opt,name=poker_offer,json=pokerOffer,proto3,oneof" json:"poker_offer,omitempty"`
+	// Points earned from question rewards.
+	Points *int64 `protobuf:"varint,15,opt,name=points,proto3,oneof" json:"points,omitempty"`
+	// How many wrong answers the player has given on the current level.
+	WrongAttempts *int64 `protobuf:"varint,16,opt,name=wrong_attempts,json=wrongAttempts,proto3,oneof" json:"wrong_attempts,omitempty"`
+	// How many of the hints for the question at hint_level have been revealed
+	// to the player because they were stuck.
+	HintLevel     *int64 `protobuf:"varint,17,opt,name=hint_level,json=hintLevel,proto3,oneof" json:"hint_level,omitempty"`
+	HintsRevealed *int64 `protobuf:"varint,18,opt,name=hints_revealed,json=hintsRevealed,proto3,oneof" json:"hints_revealed,omitempty"`
+	// How many scans this player's helper has made for them. See
+	// PlayerHandicap.
+	HelperScansUsed *int64 `protobuf:"varint,19,opt,name=helper_scans_used,json=helperScansUsed,proto3,oneof" json:"helper_scans_used,omitempty"`
+	// Which variant of the question the player was given, on levels that have
+	// several. See GameQuestion.variant.
+	Variants []*VariantAssignment `protobuf:"bytes,20,rep,name=variants,proto3" json:"variants,omitempty"`
+	// When the player reached the level in reached_level, to tell how long they
+	// have been stuck there. Only kept while auto hints are on.
+	ReachedLevel *int64 `protobuf:"varint,21,opt,name=reached_level,json=reachedLevel,proto3,oneof" json:"reached_level,omitempty"`
+	ReachedUsec  *int64 `protobuf:"varint,22,opt,name=reached_usec,json=reachedUsec,proto3,oneof" json:"reached_usec,omitempty"`
 }
 
 func (x *GameState) Reset() {
 	*x = GameState{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_gamedata_proto_msgTypes[3]
+		mi := &file_gamedata_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -518,7 +969,7 @@ func (x *GameState) String() string {
 func (*GameState) ProtoMessage() {}
 
 func (x *GameState) ProtoReflect() protoreflect.Message {
-	mi := &file_gamedata_proto_msgTypes[3]
+	mi := &file_gamedata_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -531,7 +982,7 @@ func (x *GameState) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GameState.ProtoReflect.Descriptor instead.
 func (*GameState) Descriptor() ([]byte, []int) {
-	return file_gamedata_proto_rawDescGZIP(), []int{3}
+	return file_gamedata_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *GameState) GetUserLevel() int64 {
@@ -576,109 +1027,130 @@ func (x *GameState) GetHasZn() bool {
 	return false
 }
 
-// ActionLog represents a single activity performed by a user
-type ActionLog struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	TimestampUsec *int64                  `protobuf:"varint,1,opt,name=timestamp_usec,json=timestampUsec,proto3,oneof" json:"timestamp_usec,omitempty"`
-	ClueShortName *string                 `protobuf:"bytes,2,opt,name=clue_short_name,json=clueShortName,proto3,oneof" json:"clue_short_name,omitempty"`
-	Type          *ActionLog_ActionType   `protobuf:"varint,3,opt,name=type,proto3,enum=qrpb.ActionLog_ActionType,oneof" json:"type,omitempty"`
-	OldState      *GameState              `protobuf:"bytes,4,opt,name=old_state,json=oldState,proto3,oneof" json:"old_state,omitempty"`
-	Result        *ActionLog_ActionResult `protobuf:"varint,5,opt,name=result,proto3,enum=qrpb.ActionLog_ActionResult,oneof" json:"result,omitempty"`
-}
-
-func (x *ActionLog) Reset() {
-	*x = ActionLog{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_gamedata_proto_msgTypes[4]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+func (x *GameState) GetPhotoPending() bool {
+	if x != nil && x.PhotoPending != nil {
+		return *x.PhotoPending
 	}
+	return false
 }
 
-func (x *ActionLog) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*ActionLog) ProtoMessage() {}
-
-func (x *ActionLog) ProtoReflect() protoreflect.Message {
-	mi := &file_gamedata_proto_msgTypes[4]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+func (x *GameState) GetRouteProgress() int64 {
+	if x != nil && x.RouteProgress != nil {
+		return *x.RouteProgress
 	}
-	return mi.MessageOf(x)
+	return 0
 }
 
-// Deprecated: Use ActionLog.ProtoReflect.Descriptor instead.
-func (*ActionLog) Descriptor() ([]byte, []int) {
-	return file_gamedata_proto_rawDescGZIP(), []int{4}
+func (x *GameState) GetBingoCells() []*BingoCell {
+	if x != nil {
+		return x.BingoCells
+	}
+	return nil
 }
 
-func (x *ActionLog) GetTimestampUsec() int64 {
-	if x != nil && x.TimestampUsec != nil {
-		return *x.TimestampUsec
+func (x *GameState) GetBingoLines() int64 {
+	if x != nil && x.BingoLines != nil {
+		return *x.BingoLines
 	}
 	return 0
 }
 
-func (x *ActionLog) GetClueShortName() string {
-	if x != nil && x.ClueShortName != nil {
-		return *x.ClueShortName
+func (x *GameState) GetBingoWon() bool {
+	if x != nil && x.BingoWon != nil {
+		return *x.BingoWon
 	}
-	return ""
+	return false
 }
 
-func (x *ActionLog) GetType() ActionLog_ActionType {
-	if x != nil && x.Type != nil {
-		return *x.Type
+func (x *GameState) GetBuddies() []*BuddyAssignment {
+	if x != nil {
+		return x.Buddies
 	}
-	return ActionLog_ACTION_UNSPECIFIED
+	return nil
 }
 
-func (x *ActionLog) GetOldState() *GameState {
+func (x *GameState) GetPokerHand() []*PokerCard {
 	if x != nil {
-		return x.OldState
+		return x.PokerHand
 	}
 	return nil
 }
 
-func (x *ActionLog) GetResult() ActionLog_ActionResult {
-	if x != nil && x.Result != nil {
-		return *x.Result
+func (x *GameState) GetPokerOffer() *PokerCard {
+	if x != nil {
+		return x.PokerOffer
 	}
-	return ActionLog_RESULT_UNSPECIFIED
+	return nil
 }
 
-// A game question, which is a numbered question with the HTML to show to the
-// players. The answer could be a set of USERNAMEs or anyone who answered the
-// survey question with the same boolean.
-type GameQuestion struct {
+func (x *GameState) GetPoints() int64 {
+	if x != nil && x.Points != nil {
+		return *x.Points
+	}
+	return 0
+}
+
+func (x *GameState) GetWrongAttempts() int64 {
+	if x != nil && x.WrongAttempts != nil {
+		return *x.WrongAttempts
+	}
+	return 0
+}
+
+func (x *GameState) GetHintLevel() int64 {
+	if x != nil && x.HintLevel != nil {
+		return *x.HintLevel
+	}
+	return 0
+}
+
+func (x *GameState) GetHintsRevealed() int64 {
+	if x != nil && x.HintsRevealed != nil {
+		return *x.HintsRevealed
+	}
+	return 0
+}
+
+func (x *GameState) GetHelperScansUsed() int64 {
+	if x != nil && x.HelperScansUsed != nil {
+		return *x.HelperScansUsed
+	}
+	return 0
+}
+
+func (x *GameState) GetVariants() []*VariantAssignment {
+	if x != nil {
+		return x.Variants
+	}
+	return nil
+}
+
+func (x *GameState) GetReachedLevel() int64 {
+	if x != nil && x.ReachedLevel != nil {
+		return *x.ReachedLevel
+	}
+	return 0
+}
+
+func (x *GameState) GetReachedUsec() int64 {
+	if x != nil && x.ReachedUsec != nil {
+		return *x.ReachedUsec
+	}
+	return 0
+}
+
+// VariantAssignment is the variant of a level's question given to a player.
+type VariantAssignment struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	QuestionId   *int64  `protobuf:"varint,1,opt,name=question_id,json=questionId,proto3,oneof" json:"question_id,omitempty"`
-	Type         *GQType `protobuf:"varint,2,opt,name=type,proto3,enum=qrpb.GQType,oneof" json:"type,omitempty"`
-	QuestionHtml *string `protobuf:"bytes,3,opt,name=question_html,json=questionHtml,proto3,oneof" json:"question_html,omitempty"`
-	// Valid answers. Only valid for type = USERNAME_LIST.
-	AnsUsernames []string `protobuf:"bytes,4,rep,name=ans_usernames,json=ansUsernames,proto3" json:"ans_usernames,omitempty"`
-	// Which Survey question is being referenced here? Only valid for type =
-	// SURVEY_ANS.
-	SurveyId *int64 `protobuf:"varint,5,opt,name=survey_id,json=surveyId,proto3,oneof" json:"survey_id,omitempty"`
-	// Whether the correct answers are those who chose the 'true' option in the
-	// survey.
-	SurveyTrueIsCorrect *bool `protobuf:"varint,6,opt,name=survey_true_is_correct,json=surveyTrueIsCorrect,proto3,oneof" json:"survey_true_is_correct,omitempty"`
+	QuestionId *int64  `protobuf:"varint,1,opt,name=question_id,json=questionId,proto3,oneof" json:"question_id,omitempty"`
+	Variant    *string `protobuf:"bytes,2,opt,name=variant,proto3,oneof" json:"variant,omitempty"`
 }
 
-func (x *GameQuestion) Reset() {
-	*x = GameQuestion{}
+func (x *VariantAssignment) Reset() {
+	*x = VariantAssignment{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_gamedata_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -686,13 +1158,13 @@ func (x *GameQuestion) Reset() {
 	}
 }
 
-func (x *GameQuestion) String() string {
+func (x *VariantAssignment) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GameQuestion) ProtoMessage() {}
+func (*VariantAssignment) ProtoMessage() {}
 
-func (x *GameQuestion) ProtoReflect() protoreflect.Message {
+func (x *VariantAssignment) ProtoReflect() protoreflect.Message {
 	mi := &file_gamedata_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -704,78 +1176,186 @@ func (x *GameQuestion) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GameQuestion.ProtoReflect.Descriptor instead.
-func (*GameQuestion) Descriptor() ([]byte, []int) {
+// Deprecated: Use VariantAssignment.ProtoReflect.Descriptor instead.
+func (*VariantAssignment) Descriptor() ([]byte, []int) {
 	return file_gamedata_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *GameQuestion) GetQuestionId() int64 {
+func (x *VariantAssignment) GetQuestionId() int64 {
 	if x != nil && x.QuestionId != nil {
 		return *x.QuestionId
 	}
 	return 0
 }
 
-func (x *GameQuestion) GetType() GQType {
-	if x != nil && x.Type != nil {
-		return *x.Type
+func (x *VariantAssignment) GetVariant() string {
+	if x != nil && x.Variant != nil {
+		return *x.Variant
 	}
-	return GQType_GQTYPE_UNSPECIFIED
+	return ""
 }
 
-func (x *GameQuestion) GetQuestionHtml() string {
-	if x != nil && x.QuestionHtml != nil {
-		return *x.QuestionHtml
+// PokerCard is a card collected from someone's badge in the poker game mode.
+type PokerCard struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Suit *CardSuit `protobuf:"varint,1,opt,name=suit,proto3,enum=qrpb.CardSuit,oneof" json:"suit,omitempty"`
+	// 1 is the ace, 11 to 13 are the jack, queen and king.
+	Rank *int64 `protobuf:"varint,2,opt,name=rank,proto3,oneof" json:"rank,omitempty"`
+	// The player whose badge the card came from.
+	FromUsername *string `protobuf:"bytes,3,opt,name=from_username,json=fromUsername,proto3,oneof" json:"from_username,omitempty"`
+}
+
+func (x *PokerCard) Reset() {
+	*x = PokerCard{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gamedata_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PokerCard) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PokerCard) ProtoMessage() {}
+
+func (x *PokerCard) ProtoReflect() protoreflect.Message {
+	mi := &file_gamedata_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PokerCard.ProtoReflect.Descriptor instead.
+func (*PokerCard) Descriptor() ([]byte, []int) {
+	return file_gamedata_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *PokerCard) GetSuit() CardSuit {
+	if x != nil && x.Suit != nil {
+		return *x.Suit
+	}
+	return CardSuit_CARD_SUIT_UNSPECIFIED
+}
+
+func (x *PokerCard) GetRank() int64 {
+	if x != nil && x.Rank != nil {
+		return *x.Rank
+	}
+	return 0
+}
+
+func (x *PokerCard) GetFromUsername() string {
+	if x != nil && x.FromUsername != nil {
+		return *x.FromUsername
 	}
 	return ""
 }
 
-func (x *GameQuestion) GetAnsUsernames() []string {
-	if x != nil {
-		return x.AnsUsernames
+// BuddyAssignment is the person a player has to find on a SECRET_BUDDY
+// question, and the clue generated for them when they were assigned.
+type BuddyAssignment struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	QuestionId *int64  `protobuf:"varint,1,opt,name=question_id,json=questionId,proto3,oneof" json:"question_id,omitempty"`
+	Username   *string `protobuf:"bytes,2,opt,name=username,proto3,oneof" json:"username,omitempty"`
+	Clue       *string `protobuf:"bytes,3,opt,name=clue,proto3,oneof" json:"clue,omitempty"`
+}
+
+func (x *BuddyAssignment) Reset() {
+	*x = BuddyAssignment{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gamedata_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return nil
 }
 
-func (x *GameQuestion) GetSurveyId() int64 {
-	if x != nil && x.SurveyId != nil {
-		return *x.SurveyId
+func (x *BuddyAssignment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BuddyAssignment) ProtoMessage() {}
+
+func (x *BuddyAssignment) ProtoReflect() protoreflect.Message {
+	mi := &file_gamedata_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BuddyAssignment.ProtoReflect.Descriptor instead.
+func (*BuddyAssignment) Descriptor() ([]byte, []int) {
+	return file_gamedata_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *BuddyAssignment) GetQuestionId() int64 {
+	if x != nil && x.QuestionId != nil {
+		return *x.QuestionId
 	}
 	return 0
 }
 
-func (x *GameQuestion) GetSurveyTrueIsCorrect() bool {
-	if x != nil && x.SurveyTrueIsCorrect != nil {
-		return *x.SurveyTrueIsCorrect
+func (x *BuddyAssignment) GetUsername() string {
+	if x != nil && x.Username != nil {
+		return *x.Username
 	}
-	return false
+	return ""
 }
 
-type GameQSet struct {
+func (x *BuddyAssignment) GetClue() string {
+	if x != nil && x.Clue != nil {
+		return *x.Clue
+	}
+	return ""
+}
+
+// ActionLog represents a single activity performed by a user
+type ActionLog struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	GameQuestions []*GameQuestion `protobuf:"bytes,1,rep,name=game_questions,json=gameQuestions,proto3" json:"game_questions,omitempty"`
+	TimestampUsec *int64                  `protobuf:"varint,1,opt,name=timestamp_usec,json=timestampUsec,proto3,oneof" json:"timestamp_usec,omitempty"`
+	ClueShortName *string                 `protobuf:"bytes,2,opt,name=clue_short_name,json=clueShortName,proto3,oneof" json:"clue_short_name,omitempty"`
+	Type          *ActionLog_ActionType   `protobuf:"varint,3,opt,name=type,proto3,enum=qrpb.ActionLog_ActionType,oneof" json:"type,omitempty"`
+	OldState      *GameState              `protobuf:"bytes,4,opt,name=old_state,json=oldState,proto3,oneof" json:"old_state,omitempty"`
+	Result        *ActionLog_ActionResult `protobuf:"varint,5,opt,name=result,proto3,enum=qrpb.ActionLog_ActionResult,oneof" json:"result,omitempty"`
 }
 
-func (x *GameQSet) Reset() {
-	*x = GameQSet{}
+func (x *ActionLog) Reset() {
+	*x = ActionLog{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_gamedata_proto_msgTypes[6]
+		mi := &file_gamedata_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *GameQSet) String() string {
+func (x *ActionLog) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GameQSet) ProtoMessage() {}
+func (*ActionLog) ProtoMessage() {}
 
-func (x *GameQSet) ProtoReflect() protoreflect.Message {
-	mi := &file_gamedata_proto_msgTypes[6]
+func (x *ActionLog) ProtoReflect() protoreflect.Message {
+	mi := &file_gamedata_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -786,46 +1366,77 @@ func (x *GameQSet) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GameQSet.ProtoReflect.Descriptor instead.
-func (*GameQSet) Descriptor() ([]byte, []int) {
-	return file_gamedata_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use ActionLog.ProtoReflect.Descriptor instead.
+func (*ActionLog) Descriptor() ([]byte, []int) {
+	return file_gamedata_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *GameQSet) GetGameQuestions() []*GameQuestion {
+func (x *ActionLog) GetTimestampUsec() int64 {
+	if x != nil && x.TimestampUsec != nil {
+		return *x.TimestampUsec
+	}
+	return 0
+}
+
+func (x *ActionLog) GetClueShortName() string {
+	if x != nil && x.ClueShortName != nil {
+		return *x.ClueShortName
+	}
+	return ""
+}
+
+func (x *ActionLog) GetType() ActionLog_ActionType {
+	if x != nil && x.Type != nil {
+		return *x.Type
+	}
+	return ActionLog_ACTION_UNSPECIFIED
+}
+
+func (x *ActionLog) GetOldState() *GameState {
 	if x != nil {
-		return x.GameQuestions
+		return x.OldState
 	}
 	return nil
 }
 
-// A survey question shown at the beginning to the players (during signup).
-type SurveyQuestion struct {
+func (x *ActionLog) GetResult() ActionLog_ActionResult {
+	if x != nil && x.Result != nil {
+		return *x.Result
+	}
+	return ActionLog_RESULT_UNSPECIFIED
+}
+
+// GlobalEvent is a modifier that applies to every player between its start
+// and end times.
+type GlobalEvent struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	QuestionId   *int64      `protobuf:"varint,1,opt,name=question_id,json=questionId,proto3,oneof" json:"question_id,omitempty"`
-	QuestionText *string     `protobuf:"bytes,2,opt,name=question_text,json=questionText,proto3,oneof" json:"question_text,omitempty"`
-	Type         *SurveyType `protobuf:"varint,3,opt,name=type,proto3,enum=qrpb.SurveyType,oneof" json:"type,omitempty"`
+	Kind      *GlobalEventKind `protobuf:"varint,1,opt,name=kind,proto3,enum=qrpb.GlobalEventKind,oneof" json:"kind,omitempty"`
+	StartUsec *int64           `protobuf:"varint,2,opt,name=start_usec,json=startUsec,proto3,oneof" json:"start_usec,omitempty"`
+	EndUsec   *int64           `protobuf:"varint,3,opt,name=end_usec,json=endUsec,proto3,oneof" json:"end_usec,omitempty"`
+	// Shown in the banner on every player's game page.
+	Message *string `protobuf:"bytes,4,opt,name=message,proto3,oneof" json:"message,omitempty"`
 }
 
-func (x *SurveyQuestion) Reset() {
-	*x = SurveyQuestion{}
+func (x *GlobalEvent) Reset() {
+	*x = GlobalEvent{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_gamedata_proto_msgTypes[7]
+		mi := &file_gamedata_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *SurveyQuestion) String() string {
+func (x *GlobalEvent) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SurveyQuestion) ProtoMessage() {}
+func (*GlobalEvent) ProtoMessage() {}
 
-func (x *SurveyQuestion) ProtoReflect() protoreflect.Message {
-	mi := &file_gamedata_proto_msgTypes[7]
+func (x *GlobalEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_gamedata_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -833,62 +1444,1232 @@ func (x *SurveyQuestion) ProtoReflect() protoreflect.Message {
 		}
 		return ms
 	}
-	return mi.MessageOf(x)
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GlobalEvent.ProtoReflect.Descriptor instead.
+func (*GlobalEvent) Descriptor() ([]byte, []int) {
+	return file_gamedata_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GlobalEvent) GetKind() GlobalEventKind {
+	if x != nil && x.Kind != nil {
+		return *x.Kind
+	}
+	return GlobalEventKind_GLOBAL_EVENT_UNSPECIFIED
+}
+
+func (x *GlobalEvent) GetStartUsec() int64 {
+	if x != nil && x.StartUsec != nil {
+		return *x.StartUsec
+	}
+	return 0
+}
+
+func (x *GlobalEvent) GetEndUsec() int64 {
+	if x != nil && x.EndUsec != nil {
+		return *x.EndUsec
+	}
+	return 0
+}
+
+func (x *GlobalEvent) GetMessage() string {
+	if x != nil && x.Message != nil {
+		return *x.Message
+	}
+	return ""
+}
+
+type GlobalEventSet struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Events []*GlobalEvent `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+}
+
+func (x *GlobalEventSet) Reset() {
+	*x = GlobalEventSet{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gamedata_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GlobalEventSet) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GlobalEventSet) ProtoMessage() {}
+
+func (x *GlobalEventSet) ProtoReflect() protoreflect.Message {
+	mi := &file_gamedata_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GlobalEventSet.ProtoReflect.Descriptor instead.
+func (*GlobalEventSet) Descriptor() ([]byte, []int) {
+	return file_gamedata_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GlobalEventSet) GetEvents() []*GlobalEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+// A game question, which is a numbered question with the HTML to show to the
+// players. The answer could be a set of USERNAMEs, anyone who answered the
+// survey question with the same boolean, some text typed by the player, a
+// photo that an organizer approves, a route of props scanned in order, or a
+// secret buddy picked for each player by the server.
+type GameQuestion struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	QuestionId   *int64  `protobuf:"varint,1,opt,name=question_id,json=questionId,proto3,oneof" json:"question_id,omitempty"`
+	Type         *GQType `protobuf:"varint,2,opt,name=type,proto3,enum=qrpb.GQType,oneof" json:"type,omitempty"`
+	QuestionHtml *string `protobuf:"bytes,3,opt,name=question_html,json=questionHtml,proto3,oneof" json:"question_html,omitempty"`
+	// Valid answers. Only valid for type = USERNAME_LIST.
+	AnsUsernames []string `protobuf:"bytes,4,rep,name=ans_usernames,json=ansUsernames,proto3" json:"ans_usernames,omitempty"`
+	// Which Survey question is being referenced here? Only valid for type =
+	// SURVEY_ANS.
+	SurveyId *int64 `protobuf:"varint,5,opt,name=survey_id,json=surveyId,proto3,oneof" json:"survey_id,omitempty"`
+	// Whether the correct answers are those who chose the 'true' option in the
+	// survey.
+	SurveyTrueIsCorrect *bool `protobuf:"varint,6,opt,name=survey_true_is_correct,json=surveyTrueIsCorrect,proto3,oneof" json:"survey_true_is_correct,omitempty"`
+	// Accepted typed answers. Only valid for type = TEXT_ANSWER. Matching
+	// ignores case, extra whitespace and diacritics.
+	TextAnswers []string `protobuf:"bytes,7,rep,name=text_answers,json=textAnswers,proto3" json:"text_answers,omitempty"`
+	// The usernames that must be scanned, in this order. Only valid for type =
+	// CHECKPOINT_ROUTE.
+	RouteUsernames []string `protobuf:"bytes,8,rep,name=route_usernames,json=routeUsernames,proto3" json:"route_usernames,omitempty"`
+	// Whether scanning out of order costs a life. If false, an out of order scan
+	// resets the player to the start of the route instead.
+	RouteMistakeCostsLife *bool `protobuf:"varint,9,opt,name=route_mistake_costs_life,json=routeMistakeCostsLife,proto3,oneof" json:"route_mistake_costs_life,omitempty"`
+	// How many lives a wrong answer costs. Zero is allowed. If unset, the
+	// default from the game config is used.
+	WrongPenalty *int64 `protobuf:"varint,10,opt,name=wrong_penalty,json=wrongPenalty,proto3,oneof" json:"wrong_penalty,omitempty"`
+	// What the player earns on top of moving to the next question. If unset,
+	// the default from the game config is used.
+	Reward *QuestionReward `protobuf:"bytes,11,opt,name=reward,proto3,oneof" json:"reward,omitempty"`
+	// How many wrong answers are allowed before the player skips to the next
+	// question. Zero means no limit. If unset, the default from the game config
+	// is used.
+	MaxAttempts *int64 `protobuf:"varint,12,opt,name=max_attempts,json=maxAttempts,proto3,oneof" json:"max_attempts,omitempty"`
+	// Hints revealed one at a time to players who are stuck on this question.
+	// See AutoHintConfig. Like question_html, these can contain HTML.
+	Hints []string `protobuf:"bytes,13,rep,name=hints,proto3" json:"hints,omitempty"`
+	// The question stays locked until this time, in RFC3339 format, e.g.
+	// "2022-11-05T19:30:00+05:30". Players who get here early see a placeholder,
+	// and their scans are ignored.
+	UnlockTime *string `protobuf:"bytes,14,opt,name=unlock_time,json=unlockTime,proto3,oneof" json:"unlock_time,omitempty"`
+	// Asked instead of this question once backup_active is set, for example
+	// when too few registered players can answer a SURVEY_ANS question. Its
+	// question_id is ignored. See SurveyCoverage.
+	Backup       *GameQuestion `protobuf:"bytes,15,opt,name=backup,proto3,oneof" json:"backup,omitempty"`
+	BackupActive *bool         `protobuf:"varint,16,opt,name=backup_active,json=backupActive,proto3,oneof" json:"backup_active,omitempty"`
+	// Several questions can share a question_id, and the players who reach
+	// that level are split between them. Each then needs its own variant name,
+	// like "a" and "b".
+	Variant *string `protobuf:"bytes,17,opt,name=variant,proto3,oneof" json:"variant,omitempty"`
+	// A retired variant is no longer given to anyone. Retire variants from the
+	// Questions page, which also moves the players on them to another variant.
+	Retired *bool `protobuf:"varint,18,opt,name=retired,proto3,oneof" json:"retired,omitempty"`
+	// How hard the author thinks the question is, from 1 (easy) to 5 (hard).
+	// The ordering page uses it to propose an order of questions.
+	Difficulty *int64 `protobuf:"varint,19,opt,name=difficulty,proto3,oneof" json:"difficulty,omitempty"`
+}
+
+func (x *GameQuestion) Reset() {
+	*x = GameQuestion{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gamedata_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GameQuestion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GameQuestion) ProtoMessage() {}
+
+func (x *GameQuestion) ProtoReflect() protoreflect.Message {
+	mi := &file_gamedata_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GameQuestion.ProtoReflect.Descriptor instead.
+func (*GameQuestion) Descriptor() ([]byte, []int) {
+	return file_gamedata_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GameQuestion) GetQuestionId() int64 {
+	if x != nil && x.QuestionId != nil {
+		return *x.QuestionId
+	}
+	return 0
+}
+
+func (x *GameQuestion) GetType() GQType {
+	if x != nil && x.Type != nil {
+		return *x.Type
+	}
+	return GQType_GQTYPE_UNSPECIFIED
+}
+
+func (x *GameQuestion) GetQuestionHtml() string {
+	if x != nil && x.QuestionHtml != nil {
+		return *x.QuestionHtml
+	}
+	return ""
+}
+
+func (x *GameQuestion) GetAnsUsernames() []string {
+	if x != nil {
+		return x.AnsUsernames
+	}
+	return nil
+}
+
+func (x *GameQuestion) GetSurveyId() int64 {
+	if x != nil && x.SurveyId != nil {
+		return *x.SurveyId
+	}
+	return 0
+}
+
+func (x *GameQuestion) GetSurveyTrueIsCorrect() bool {
+	if x != nil && x.SurveyTrueIsCorrect != nil {
+		return *x.SurveyTrueIsCorrect
+	}
+	return false
+}
+
+func (x *GameQuestion) GetTextAnswers() []string {
+	if x != nil {
+		return x.TextAnswers
+	}
+	return nil
+}
+
+func (x *GameQuestion) GetRouteUsernames() []string {
+	if x != nil {
+		return x.RouteUsernames
+	}
+	return nil
+}
+
+func (x *GameQuestion) GetRouteMistakeCostsLife() bool {
+	if x != nil && x.RouteMistakeCostsLife != nil {
+		return *x.RouteMistakeCostsLife
+	}
+	return false
+}
+
+func (x *GameQuestion) GetWrongPenalty() int64 {
+	if x != nil && x.WrongPenalty != nil {
+		return *x.WrongPenalty
+	}
+	return 0
+}
+
+func (x *GameQuestion) GetReward() *QuestionReward {
+	if x != nil {
+		return x.Reward
+	}
+	return nil
+}
+
+func (x *GameQuestion) GetMaxAttempts() int64 {
+	if x != nil && x.MaxAttempts != nil {
+		return *x.MaxAttempts
+	}
+	return 0
+}
+
+func (x *GameQuestion) GetHints() []string {
+	if x != nil {
+		return x.Hints
+	}
+	return nil
+}
+
+func (x *GameQuestion) GetUnlockTime() string {
+	if x != nil && x.UnlockTime != nil {
+		return *x.UnlockTime
+	}
+	return ""
+}
+
+func (x *GameQuestion) GetBackup() *GameQuestion {
+	if x != nil {
+		return x.Backup
+	}
+	return nil
+}
+
+func (x *GameQuestion) GetBackupActive() bool {
+	if x != nil && x.BackupActive != nil {
+		return *x.BackupActive
+	}
+	return false
+}
+
+func (x *GameQuestion) GetVariant() string {
+	if x != nil && x.Variant != nil {
+		return *x.Variant
+	}
+	return ""
+}
+
+func (x *GameQuestion) GetRetired() bool {
+	if x != nil && x.Retired != nil {
+		return *x.Retired
+	}
+	return false
+}
+
+func (x *GameQuestion) GetDifficulty() int64 {
+	if x != nil && x.Difficulty != nil {
+		return *x.Difficulty
+	}
+	return 0
+}
+
+// QuestionReward is what a player earns for answering a question correctly.
+type QuestionReward struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Points *int64 `protobuf:"varint,1,opt,name=points,proto3,oneof" json:"points,omitempty"`
+	Lives  *int64 `protobuf:"varint,2,opt,name=lives,proto3,oneof" json:"lives,omitempty"`
+	// A token to grant: one of "al", "cu", "sn" or "zn".
+	Token *string `protobuf:"bytes,3,opt,name=token,proto3,oneof" json:"token,omitempty"`
+}
+
+func (x *QuestionReward) Reset() {
+	*x = QuestionReward{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gamedata_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QuestionReward) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuestionReward) ProtoMessage() {}
+
+func (x *QuestionReward) ProtoReflect() protoreflect.Message {
+	mi := &file_gamedata_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuestionReward.ProtoReflect.Descriptor instead.
+func (*QuestionReward) Descriptor() ([]byte, []int) {
+	return file_gamedata_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *QuestionReward) GetPoints() int64 {
+	if x != nil && x.Points != nil {
+		return *x.Points
+	}
+	return 0
+}
+
+func (x *QuestionReward) GetLives() int64 {
+	if x != nil && x.Lives != nil {
+		return *x.Lives
+	}
+	return 0
+}
+
+func (x *QuestionReward) GetToken() string {
+	if x != nil && x.Token != nil {
+		return *x.Token
+	}
+	return ""
+}
+
+// QuestionDefaults are the penalty, reward and attempt limit for every
+// question that does not set its own.
+type QuestionDefaults struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Defaults to one life when unset.
+	WrongPenalty *int64          `protobuf:"varint,1,opt,name=wrong_penalty,json=wrongPenalty,proto3,oneof" json:"wrong_penalty,omitempty"`
+	Reward       *QuestionReward `protobuf:"bytes,2,opt,name=reward,proto3,oneof" json:"reward,omitempty"`
+	MaxAttempts  *int64          `protobuf:"varint,3,opt,name=max_attempts,json=maxAttempts,proto3,oneof" json:"max_attempts,omitempty"`
+}
+
+func (x *QuestionDefaults) Reset() {
+	*x = QuestionDefaults{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gamedata_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QuestionDefaults) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuestionDefaults) ProtoMessage() {}
+
+func (x *QuestionDefaults) ProtoReflect() protoreflect.Message {
+	mi := &file_gamedata_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuestionDefaults.ProtoReflect.Descriptor instead.
+func (*QuestionDefaults) Descriptor() ([]byte, []int) {
+	return file_gamedata_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *QuestionDefaults) GetWrongPenalty() int64 {
+	if x != nil && x.WrongPenalty != nil {
+		return *x.WrongPenalty
+	}
+	return 0
+}
+
+func (x *QuestionDefaults) GetReward() *QuestionReward {
+	if x != nil {
+		return x.Reward
+	}
+	return nil
+}
+
+func (x *QuestionDefaults) GetMaxAttempts() int64 {
+	if x != nil && x.MaxAttempts != nil {
+		return *x.MaxAttempts
+	}
+	return 0
+}
+
+type GameQSet struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GameQuestions []*GameQuestion `protobuf:"bytes,1,rep,name=game_questions,json=gameQuestions,proto3" json:"game_questions,omitempty"`
+	// When the ordering page last moved the questions. Logs from before then
+	// were made on other questions, so the ordering page ignores them.
+	ReorderedUsec *int64 `protobuf:"varint,2,opt,name=reordered_usec,json=reorderedUsec,proto3,oneof" json:"reordered_usec,omitempty"`
+}
+
+func (x *GameQSet) Reset() {
+	*x = GameQSet{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gamedata_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GameQSet) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GameQSet) ProtoMessage() {}
+
+func (x *GameQSet) ProtoReflect() protoreflect.Message {
+	mi := &file_gamedata_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GameQSet.ProtoReflect.Descriptor instead.
+func (*GameQSet) Descriptor() ([]byte, []int) {
+	return file_gamedata_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GameQSet) GetGameQuestions() []*GameQuestion {
+	if x != nil {
+		return x.GameQuestions
+	}
+	return nil
+}
+
+func (x *GameQSet) GetReorderedUsec() int64 {
+	if x != nil && x.ReorderedUsec != nil {
+		return *x.ReorderedUsec
+	}
+	return 0
+}
+
+// A survey question shown at the beginning to the players (during signup).
+type SurveyQuestion struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	QuestionId   *int64      `protobuf:"varint,1,opt,name=question_id,json=questionId,proto3,oneof" json:"question_id,omitempty"`
+	QuestionText *string     `protobuf:"bytes,2,opt,name=question_text,json=questionText,proto3,oneof" json:"question_text,omitempty"`
+	Type         *SurveyType `protobuf:"varint,3,opt,name=type,proto3,enum=qrpb.SurveyType,oneof" json:"type,omitempty"`
+}
+
+func (x *SurveyQuestion) Reset() {
+	*x = SurveyQuestion{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gamedata_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SurveyQuestion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SurveyQuestion) ProtoMessage() {}
+
+func (x *SurveyQuestion) ProtoReflect() protoreflect.Message {
+	mi := &file_gamedata_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SurveyQuestion.ProtoReflect.Descriptor instead.
+func (*SurveyQuestion) Descriptor() ([]byte, []int) {
+	return file_gamedata_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *SurveyQuestion) GetQuestionId() int64 {
+	if x != nil && x.QuestionId != nil {
+		return *x.QuestionId
+	}
+	return 0
+}
+
+func (x *SurveyQuestion) GetQuestionText() string {
+	if x != nil && x.QuestionText != nil {
+		return *x.QuestionText
+	}
+	return ""
+}
+
+func (x *SurveyQuestion) GetType() SurveyType {
+	if x != nil && x.Type != nil {
+		return *x.Type
+	}
+	return SurveyType_SURVEY_TYPE_UNSPECIFIED
+}
+
+// Survey response to a single question.
+type SurveyAnswer struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	QuestionId *int64 `protobuf:"varint,1,opt,name=question_id,json=questionId,proto3,oneof" json:"question_id,omitempty"`
+	IsTrue     *bool  `protobuf:"varint,2,opt,name=is_true,json=isTrue,proto3,oneof" json:"is_true,omitempty"`
+}
+
+func (x *SurveyAnswer) Reset() {
+	*x = SurveyAnswer{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gamedata_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SurveyAnswer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SurveyAnswer) ProtoMessage() {}
+
+func (x *SurveyAnswer) ProtoReflect() protoreflect.Message {
+	mi := &file_gamedata_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SurveyAnswer.ProtoReflect.Descriptor instead.
+func (*SurveyAnswer) Descriptor() ([]byte, []int) {
+	return file_gamedata_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *SurveyAnswer) GetQuestionId() int64 {
+	if x != nil && x.QuestionId != nil {
+		return *x.QuestionId
+	}
+	return 0
+}
+
+func (x *SurveyAnswer) GetIsTrue() bool {
+	if x != nil && x.IsTrue != nil {
+		return *x.IsTrue
+	}
+	return false
+}
+
+type SurveySet struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SurveyQuestions []*SurveyQuestion `protobuf:"bytes,1,rep,name=survey_questions,json=surveyQuestions,proto3" json:"survey_questions,omitempty"`
+}
+
+func (x *SurveySet) Reset() {
+	*x = SurveySet{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gamedata_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SurveySet) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SurveySet) ProtoMessage() {}
+
+func (x *SurveySet) ProtoReflect() protoreflect.Message {
+	mi := &file_gamedata_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SurveySet.ProtoReflect.Descriptor instead.
+func (*SurveySet) Descriptor() ([]byte, []int) {
+	return file_gamedata_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *SurveySet) GetSurveyQuestions() []*SurveyQuestion {
+	if x != nil {
+		return x.SurveyQuestions
+	}
+	return nil
+}
+
+// GameConfig holds the game-wide settings chosen by the organizer.
+type GameConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Which set of rules the game is played with. Empty means the regular
+	// treasure hunt through the game questions. The other options are "bingo",
+	// "assassin" and "poker".
+	GameMode *string `protobuf:"bytes,1,opt,name=game_mode,json=gameMode,proto3,oneof" json:"game_mode,omitempty"`
+	// Settings for the bingo game mode.
+	Bingo *BingoConfig `protobuf:"bytes,2,opt,name=bingo,proto3,oneof" json:"bingo,omitempty"`
+	// Settings for the assassin game mode.
+	Assassin *AssassinConfig `protobuf:"bytes,3,opt,name=assassin,proto3,oneof" json:"assassin,omitempty"`
+	// Settings for the poker game mode.
+	Poker *PokerConfig `protobuf:"bytes,4,opt,name=poker,proto3,oneof" json:"poker,omitempty"`
+	// Penalties, rewards and attempt limits for the game questions.
+	QuestionDefaults *QuestionDefaults `protobuf:"bytes,5,opt,name=question_defaults,json=questionDefaults,proto3,oneof" json:"question_defaults,omitempty"`
+	// When to reveal hints to players who are stuck.
+	AutoHint *AutoHintConfig `protobuf:"bytes,6,opt,name=auto_hint,json=autoHint,proto3,oneof" json:"auto_hint,omitempty"`
+	// How many lives players start with. Defaults to 5.
+	StartingLife *int64 `protobuf:"varint,7,opt,name=starting_life,json=startingLife,proto3,oneof" json:"starting_life,omitempty"`
+	// Which question players start on. Defaults to 1.
+	StartingLevel *int64 `protobuf:"varint,8,opt,name=starting_level,json=startingLevel,proto3,oneof" json:"starting_level,omitempty"`
+	// How long a player stays logged in on their phone. Defaults to 720 (30 days).
+	CookieValidityHours *int64 `protobuf:"varint,9,opt,name=cookie_validity_hours,json=cookieValidityHours,proto3,oneof" json:"cookie_validity_hours,omitempty"`
+	// How long the server keeps the questions and settings before reading them
+	// from the database again. Defaults to 60.
+	CacheTtlSec *int64 `protobuf:"varint,10,opt,name=cache_ttl_sec,json=cacheTtlSec,proto3,oneof" json:"cache_ttl_sec,omitempty"`
+	// How many hearts the game page shows. Defaults to 5.
+	MaxHearts *int64 `protobuf:"varint,11,opt,name=max_hearts,json=maxHearts,proto3,oneof" json:"max_hearts,omitempty"`
+	// Mark players with a handicap on the All Users page, so prizes can take it
+	// into account.
+	FlagHandicaps *bool `protobuf:"varint,12,opt,name=flag_handicaps,json=flagHandicaps,proto3,oneof" json:"flag_handicaps,omitempty"`
+	// Which codes count when a player scans someone. Rotating codes are shown
+	// on the player's phone and change every rotating_code_period_sec seconds,
+	// so a photo of a badge passed around a group chat is of no use.
+	BadgeCodes *BadgeCodes `protobuf:"varint,13,opt,name=badge_codes,json=badgeCodes,proto3,enum=qrpb.BadgeCodes,oneof" json:"badge_codes,omitempty"`
+	// How often the rotating codes change. Defaults to 30.
+	RotatingCodePeriodSec *int64 `protobuf:"varint,14,opt,name=rotating_code_period_sec,json=rotatingCodePeriodSec,proto3,oneof" json:"rotating_code_period_sec,omitempty"`
+	// When to warn about, and swap out, SURVEY_ANS questions that too few
+	// registered players can answer.
+	SurveyCoverage *SurveyCoverage `protobuf:"bytes,15,opt,name=survey_coverage,json=surveyCoverage,proto3,oneof" json:"survey_coverage,omitempty"`
+}
+
+func (x *GameConfig) Reset() {
+	*x = GameConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gamedata_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GameConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GameConfig) ProtoMessage() {}
+
+func (x *GameConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_gamedata_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GameConfig.ProtoReflect.Descriptor instead.
+func (*GameConfig) Descriptor() ([]byte, []int) {
+	return file_gamedata_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GameConfig) GetGameMode() string {
+	if x != nil && x.GameMode != nil {
+		return *x.GameMode
+	}
+	return ""
+}
+
+func (x *GameConfig) GetBingo() *BingoConfig {
+	if x != nil {
+		return x.Bingo
+	}
+	return nil
+}
+
+func (x *GameConfig) GetAssassin() *AssassinConfig {
+	if x != nil {
+		return x.Assassin
+	}
+	return nil
+}
+
+func (x *GameConfig) GetPoker() *PokerConfig {
+	if x != nil {
+		return x.Poker
+	}
+	return nil
+}
+
+func (x *GameConfig) GetQuestionDefaults() *QuestionDefaults {
+	if x != nil {
+		return x.QuestionDefaults
+	}
+	return nil
+}
+
+func (x *GameConfig) GetAutoHint() *AutoHintConfig {
+	if x != nil {
+		return x.AutoHint
+	}
+	return nil
+}
+
+func (x *GameConfig) GetStartingLife() int64 {
+	if x != nil && x.StartingLife != nil {
+		return *x.StartingLife
+	}
+	return 0
+}
+
+func (x *GameConfig) GetStartingLevel() int64 {
+	if x != nil && x.StartingLevel != nil {
+		return *x.StartingLevel
+	}
+	return 0
+}
+
+func (x *GameConfig) GetCookieValidityHours() int64 {
+	if x != nil && x.CookieValidityHours != nil {
+		return *x.CookieValidityHours
+	}
+	return 0
+}
+
+func (x *GameConfig) GetCacheTtlSec() int64 {
+	if x != nil && x.CacheTtlSec != nil {
+		return *x.CacheTtlSec
+	}
+	return 0
+}
+
+func (x *GameConfig) GetMaxHearts() int64 {
+	if x != nil && x.MaxHearts != nil {
+		return *x.MaxHearts
+	}
+	return 0
+}
+
+func (x *GameConfig) GetFlagHandicaps() bool {
+	if x != nil && x.FlagHandicaps != nil {
+		return *x.FlagHandicaps
+	}
+	return false
+}
+
+func (x *GameConfig) GetBadgeCodes() BadgeCodes {
+	if x != nil && x.BadgeCodes != nil {
+		return *x.BadgeCodes
+	}
+	return BadgeCodes_BADGE_CODES_UNSPECIFIED
+}
+
+func (x *GameConfig) GetRotatingCodePeriodSec() int64 {
+	if x != nil && x.RotatingCodePeriodSec != nil {
+		return *x.RotatingCodePeriodSec
+	}
+	return 0
+}
+
+func (x *GameConfig) GetSurveyCoverage() *SurveyCoverage {
+	if x != nil {
+		return x.SurveyCoverage
+	}
+	return nil
+}
+
+// SurveyCoverage watches how many registered players are a right answer to
+// each SURVEY_ANS and USERNAME_LIST question.
+type SurveyCoverage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Warn when fewer players than this are a right answer. Defaults to 1.
+	MinPlayers *int64 `protobuf:"varint,1,opt,name=min_players,json=minPlayers,proto3,oneof" json:"min_players,omitempty"`
+	// Swap in the question's backup when it falls below min_players.
+	AutoBackup *bool `protobuf:"varint,2,opt,name=auto_backup,json=autoBackup,proto3,oneof" json:"auto_backup,omitempty"`
+	// Only swap in backups once this many players have registered, so the
+	// first few registrations do not trigger them. Defaults to 10.
+	MinRegistered *int64 `protobuf:"varint,3,opt,name=min_registered,json=minRegistered,proto3,oneof" json:"min_registered,omitempty"`
+}
+
+func (x *SurveyCoverage) Reset() {
+	*x = SurveyCoverage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gamedata_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SurveyCoverage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SurveyCoverage) ProtoMessage() {}
+
+func (x *SurveyCoverage) ProtoReflect() protoreflect.Message {
+	mi := &file_gamedata_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SurveyCoverage.ProtoReflect.Descriptor instead.
+func (*SurveyCoverage) Descriptor() ([]byte, []int) {
+	return file_gamedata_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *SurveyCoverage) GetMinPlayers() int64 {
+	if x != nil && x.MinPlayers != nil {
+		return *x.MinPlayers
+	}
+	return 0
+}
+
+func (x *SurveyCoverage) GetAutoBackup() bool {
+	if x != nil && x.AutoBackup != nil {
+		return *x.AutoBackup
+	}
+	return false
+}
+
+func (x *SurveyCoverage) GetMinRegistered() int64 {
+	if x != nil && x.MinRegistered != nil {
+		return *x.MinRegistered
+	}
+	return 0
+}
+
+// AutoHintConfig decides when a stuck player gets the next hint of their
+// question. Each time either threshold is crossed again, one more hint is
+// revealed. Leave both unset to turn hints off.
+type AutoHintConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Seconds spent on the same question.
+	AfterSeconds *int64 `protobuf:"varint,1,opt,name=after_seconds,json=afterSeconds,proto3,oneof" json:"after_seconds,omitempty"`
+	// Wrong answers given on the same question.
+	AfterWrongScans *int64 `protobuf:"varint,2,opt,name=after_wrong_scans,json=afterWrongScans,proto3,oneof" json:"after_wrong_scans,omitempty"`
+}
+
+func (x *AutoHintConfig) Reset() {
+	*x = AutoHintConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gamedata_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AutoHintConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AutoHintConfig) ProtoMessage() {}
+
+func (x *AutoHintConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_gamedata_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AutoHintConfig.ProtoReflect.Descriptor instead.
+func (*AutoHintConfig) Descriptor() ([]byte, []int) {
+	return file_gamedata_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *AutoHintConfig) GetAfterSeconds() int64 {
+	if x != nil && x.AfterSeconds != nil {
+		return *x.AfterSeconds
+	}
+	return 0
+}
+
+func (x *AutoHintConfig) GetAfterWrongScans() int64 {
+	if x != nil && x.AfterWrongScans != nil {
+		return *x.AfterWrongScans
+	}
+	return 0
+}
+
+// PokerConfig holds the settings for the poker endgame, where players collect
+// the cards on each other's badges to build the best hand.
+type PokerConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// When hands are frozen and scored, in RFC 3339 format. Empty means the hands
+	// stay open until the organizer switches modes.
+	EndTime *string `protobuf:"bytes,1,opt,name=end_time,json=endTime,proto3,oneof" json:"end_time,omitempty"`
+	// Players who finish the hunt go on to collect cards, and the best hand ranks
+	// first among them. Without it, the poker game mode plays the endgame on its
+	// own, for everyone at once.
+	AfterHunt *bool `protobuf:"varint,2,opt,name=after_hunt,json=afterHunt,proto3,oneof" json:"after_hunt,omitempty"`
+}
+
+func (x *PokerConfig) Reset() {
+	*x = PokerConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gamedata_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PokerConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PokerConfig) ProtoMessage() {}
+
+func (x *PokerConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_gamedata_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PokerConfig.ProtoReflect.Descriptor instead.
+func (*PokerConfig) Descriptor() ([]byte, []int) {
+	return file_gamedata_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *PokerConfig) GetEndTime() string {
+	if x != nil && x.EndTime != nil {
+		return *x.EndTime
+	}
+	return ""
+}
+
+func (x *PokerConfig) GetAfterHunt() bool {
+	if x != nil && x.AfterHunt != nil {
+		return *x.AfterHunt
+	}
+	return false
+}
+
+// BingoConfig describes how bingo cards are generated and won.
+type BingoConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Cards have card_size rows and columns. Defaults to 3.
+	CardSize *int64 `protobuf:"varint,1,opt,name=card_size,json=cardSize,proto3,oneof" json:"card_size,omitempty"`
+	// If true, a player needs to fill every square to win, rather than a line.
+	BlackoutToWin *bool `protobuf:"varint,2,opt,name=blackout_to_win,json=blackoutToWin,proto3,oneof" json:"blackout_to_win,omitempty"`
+	// The traits to draw squares from. If empty, traits are generated from the
+	// survey questions and the badge cards.
+	Traits []*BingoTrait `protobuf:"bytes,3,rep,name=traits,proto3" json:"traits,omitempty"`
+}
+
+func (x *BingoConfig) Reset() {
+	*x = BingoConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gamedata_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BingoConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BingoConfig) ProtoMessage() {}
+
+func (x *BingoConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_gamedata_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BingoConfig.ProtoReflect.Descriptor instead.
+func (*BingoConfig) Descriptor() ([]byte, []int) {
+	return file_gamedata_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *BingoConfig) GetCardSize() int64 {
+	if x != nil && x.CardSize != nil {
+		return *x.CardSize
+	}
+	return 0
+}
+
+func (x *BingoConfig) GetBlackoutToWin() bool {
+	if x != nil && x.BlackoutToWin != nil {
+		return *x.BlackoutToWin
+	}
+	return false
+}
+
+func (x *BingoConfig) GetTraits() []*BingoTrait {
+	if x != nil {
+		return x.Traits
+	}
+	return nil
+}
+
+// BingoTrait describes the people who can fill a square on a bingo card. A
+// person qualifies if they satisfy every condition that is set.
+type BingoTrait struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The text shown in the square.
+	Text                *string   `protobuf:"bytes,1,opt,name=text,proto3,oneof" json:"text,omitempty"`
+	Usernames           []string  `protobuf:"bytes,2,rep,name=usernames,proto3" json:"usernames,omitempty"`
+	SurveyId            *int64    `protobuf:"varint,3,opt,name=survey_id,json=surveyId,proto3,oneof" json:"survey_id,omitempty"`
+	SurveyTrueIsCorrect *bool     `protobuf:"varint,4,opt,name=survey_true_is_correct,json=surveyTrueIsCorrect,proto3,oneof" json:"survey_true_is_correct,omitempty"`
+	CardSuit            *CardSuit `protobuf:"varint,5,opt,name=card_suit,json=cardSuit,proto3,enum=qrpb.CardSuit,oneof" json:"card_suit,omitempty"`
+	CardRank            *int64    `protobuf:"varint,6,opt,name=card_rank,json=cardRank,proto3,oneof" json:"card_rank,omitempty"`
+}
+
+func (x *BingoTrait) Reset() {
+	*x = BingoTrait{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gamedata_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BingoTrait) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BingoTrait) ProtoMessage() {}
+
+func (x *BingoTrait) ProtoReflect() protoreflect.Message {
+	mi := &file_gamedata_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BingoTrait.ProtoReflect.Descriptor instead.
+func (*BingoTrait) Descriptor() ([]byte, []int) {
+	return file_gamedata_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *BingoTrait) GetText() string {
+	if x != nil && x.Text != nil {
+		return *x.Text
+	}
+	return ""
 }
 
-// Deprecated: Use SurveyQuestion.ProtoReflect.Descriptor instead.
-func (*SurveyQuestion) Descriptor() ([]byte, []int) {
-	return file_gamedata_proto_rawDescGZIP(), []int{7}
+func (x *BingoTrait) GetUsernames() []string {
+	if x != nil {
+		return x.Usernames
+	}
+	return nil
 }
 
-func (x *SurveyQuestion) GetQuestionId() int64 {
-	if x != nil && x.QuestionId != nil {
-		return *x.QuestionId
+func (x *BingoTrait) GetSurveyId() int64 {
+	if x != nil && x.SurveyId != nil {
+		return *x.SurveyId
 	}
 	return 0
 }
 
-func (x *SurveyQuestion) GetQuestionText() string {
-	if x != nil && x.QuestionText != nil {
-		return *x.QuestionText
+func (x *BingoTrait) GetSurveyTrueIsCorrect() bool {
+	if x != nil && x.SurveyTrueIsCorrect != nil {
+		return *x.SurveyTrueIsCorrect
 	}
-	return ""
+	return false
 }
 
-func (x *SurveyQuestion) GetType() SurveyType {
-	if x != nil && x.Type != nil {
-		return *x.Type
+func (x *BingoTrait) GetCardSuit() CardSuit {
+	if x != nil && x.CardSuit != nil {
+		return *x.CardSuit
 	}
-	return SurveyType_SURVEY_TYPE_UNSPECIFIED
+	return CardSuit_CARD_SUIT_UNSPECIFIED
 }
 
-// Survey response to a single question.
-type SurveyAnswer struct {
+func (x *BingoTrait) GetCardRank() int64 {
+	if x != nil && x.CardRank != nil {
+		return *x.CardRank
+	}
+	return 0
+}
+
+// BingoCell is a single square on a player's bingo card.
+type BingoCell struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	QuestionId *int64 `protobuf:"varint,1,opt,name=question_id,json=questionId,proto3,oneof" json:"question_id,omitempty"`
-	IsTrue     *bool  `protobuf:"varint,2,opt,name=is_true,json=isTrue,proto3,oneof" json:"is_true,omitempty"`
+	Trait *BingoTrait `protobuf:"bytes,1,opt,name=trait,proto3,oneof" json:"trait,omitempty"`
+	// The username of the person who filled this square, if any.
+	FilledBy *string `protobuf:"bytes,2,opt,name=filled_by,json=filledBy,proto3,oneof" json:"filled_by,omitempty"`
 }
 
-func (x *SurveyAnswer) Reset() {
-	*x = SurveyAnswer{}
+func (x *BingoCell) Reset() {
+	*x = BingoCell{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_gamedata_proto_msgTypes[8]
+		mi := &file_gamedata_proto_msgTypes[24]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *SurveyAnswer) String() string {
+func (x *BingoCell) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SurveyAnswer) ProtoMessage() {}
+func (*BingoCell) ProtoMessage() {}
 
-func (x *SurveyAnswer) ProtoReflect() protoreflect.Message {
-	mi := &file_gamedata_proto_msgTypes[8]
+func (x *BingoCell) ProtoReflect() protoreflect.Message {
+	mi := &file_gamedata_proto_msgTypes[24]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -899,50 +2680,58 @@ func (x *SurveyAnswer) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SurveyAnswer.ProtoReflect.Descriptor instead.
-func (*SurveyAnswer) Descriptor() ([]byte, []int) {
-	return file_gamedata_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use BingoCell.ProtoReflect.Descriptor instead.
+func (*BingoCell) Descriptor() ([]byte, []int) {
+	return file_gamedata_proto_rawDescGZIP(), []int{24}
 }
 
-func (x *SurveyAnswer) GetQuestionId() int64 {
-	if x != nil && x.QuestionId != nil {
-		return *x.QuestionId
+func (x *BingoCell) GetTrait() *BingoTrait {
+	if x != nil {
+		return x.Trait
 	}
-	return 0
+	return nil
 }
 
-func (x *SurveyAnswer) GetIsTrue() bool {
-	if x != nil && x.IsTrue != nil {
-		return *x.IsTrue
+func (x *BingoCell) GetFilledBy() string {
+	if x != nil && x.FilledBy != nil {
+		return *x.FilledBy
 	}
-	return false
+	return ""
 }
 
-type SurveySet struct {
+// AssassinConfig holds the settings for the assassin game mode.
+type AssassinConfig struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	SurveyQuestions []*SurveyQuestion `protobuf:"bytes,1,rep,name=survey_questions,json=surveyQuestions,proto3" json:"survey_questions,omitempty"`
+	// When the game stops, in RFC 3339 format, like
+	// "2022-10-21T19:30:00+05:30". If empty, the game runs until one player is
+	// left.
+	EndTime *string `protobuf:"bytes,1,opt,name=end_time,json=endTime,proto3,oneof" json:"end_time,omitempty"`
+	// If true, scanning anyone other than your target costs a life, and a
+	// player who runs out of lives leaves the chain. Off by default, so a
+	// misread badge never takes a guest out of the game.
+	WrongTagCostsLife *bool `protobuf:"varint,2,opt,name=wrong_tag_costs_life,json=wrongTagCostsLife,proto3,oneof" json:"wrong_tag_costs_life,omitempty"`
 }
 
-func (x *SurveySet) Reset() {
-	*x = SurveySet{}
+func (x *AssassinConfig) Reset() {
+	*x = AssassinConfig{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_gamedata_proto_msgTypes[9]
+		mi := &file_gamedata_proto_msgTypes[25]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *SurveySet) String() string {
+func (x *AssassinConfig) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SurveySet) ProtoMessage() {}
+func (*AssassinConfig) ProtoMessage() {}
 
-func (x *SurveySet) ProtoReflect() protoreflect.Message {
-	mi := &file_gamedata_proto_msgTypes[9]
+func (x *AssassinConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_gamedata_proto_msgTypes[25]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -953,23 +2742,30 @@ func (x *SurveySet) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SurveySet.ProtoReflect.Descriptor instead.
-func (*SurveySet) Descriptor() ([]byte, []int) {
-	return file_gamedata_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use AssassinConfig.ProtoReflect.Descriptor instead.
+func (*AssassinConfig) Descriptor() ([]byte, []int) {
+	return file_gamedata_proto_rawDescGZIP(), []int{25}
 }
 
-func (x *SurveySet) GetSurveyQuestions() []*SurveyQuestion {
-	if x != nil {
-		return x.SurveyQuestions
+func (x *AssassinConfig) GetEndTime() string {
+	if x != nil && x.EndTime != nil {
+		return *x.EndTime
 	}
-	return nil
+	return ""
+}
+
+func (x *AssassinConfig) GetWrongTagCostsLife() bool {
+	if x != nil && x.WrongTagCostsLife != nil {
+		return *x.WrongTagCostsLife
+	}
+	return false
 }
 
 var File_gamedata_proto protoreflect.FileDescriptor
 
 var file_gamedata_proto_rawDesc = []byte{
 	0x0a, 0x0e, 0x67, 0x61, 0x6d, 0x65, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x12, 0x04, 0x71, 0x72, 0x70, 0x62, 0x22, 0x98, 0x01, 0x0a, 0x05, 0x47, 0x55, 0x73, 0x65, 0x72,
+	0x12, 0x04, 0x71, 0x72, 0x70, 0x62, 0x22, 0xda, 0x01, 0x0a, 0x05, 0x47, 0x55, 0x73, 0x65, 0x72,
 	0x12, 0x1f, 0x0a, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01,
 	0x28, 0x09, 0x48, 0x00, 0x52, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x88, 0x01,
 	0x01, 0x12, 0x17, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48,
@@ -977,148 +2773,545 @@ var file_gamedata_proto_rawDesc = []byte{
 	0x72, 0x76, 0x65, 0x79, 0x5f, 0x61, 0x6e, 0x73, 0x77, 0x65, 0x72, 0x73, 0x18, 0x04, 0x20, 0x03,
 	0x28, 0x0b, 0x32, 0x12, 0x2e, 0x71, 0x72, 0x70, 0x62, 0x2e, 0x53, 0x75, 0x72, 0x76, 0x65, 0x79,
 	0x41, 0x6e, 0x73, 0x77, 0x65, 0x72, 0x52, 0x0d, 0x73, 0x75, 0x72, 0x76, 0x65, 0x79, 0x41, 0x6e,
-	0x73, 0x77, 0x65, 0x72, 0x73, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61,
-	0x6d, 0x65, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x4a, 0x04, 0x08, 0x01, 0x10,
-	0x02, 0x22, 0x8a, 0x02, 0x0a, 0x09, 0x51, 0x52, 0x4d, 0x61, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x12,
-	0x1f, 0x0a, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x48, 0x00, 0x52, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x88, 0x01, 0x01,
-	0x12, 0x26, 0x0a, 0x0c, 0x64, 0x69, 0x73, 0x70, 0x6c, 0x61, 0x79, 0x5f, 0x6e, 0x61, 0x6d, 0x65,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x0b, 0x64, 0x69, 0x73, 0x70, 0x6c, 0x61,
-	0x79, 0x4e, 0x61, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x12, 0x1b, 0x0a, 0x06, 0x71, 0x72, 0x63, 0x6f,
-	0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x06, 0x71, 0x72, 0x63, 0x6f,
-	0x64, 0x65, 0x88, 0x01, 0x01, 0x12, 0x30, 0x0a, 0x09, 0x63, 0x61, 0x72, 0x64, 0x5f, 0x73, 0x75,
-	0x69, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0e, 0x2e, 0x71, 0x72, 0x70, 0x62, 0x2e,
-	0x43, 0x61, 0x72, 0x64, 0x53, 0x75, 0x69, 0x74, 0x48, 0x03, 0x52, 0x08, 0x63, 0x61, 0x72, 0x64,
-	0x53, 0x75, 0x69, 0x74, 0x88, 0x01, 0x01, 0x12, 0x20, 0x0a, 0x09, 0x63, 0x61, 0x72, 0x64, 0x5f,
-	0x72, 0x61, 0x6e, 0x6b, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x48, 0x04, 0x52, 0x08, 0x63, 0x61,
-	0x72, 0x64, 0x52, 0x61, 0x6e, 0x6b, 0x88, 0x01, 0x01, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x75, 0x73,
-	0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x64, 0x69, 0x73, 0x70, 0x6c,
-	0x61, 0x79, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x71, 0x72, 0x63, 0x6f,
-	0x64, 0x65, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x63, 0x61, 0x72, 0x64, 0x5f, 0x73, 0x75, 0x69, 0x74,
-	0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x63, 0x61, 0x72, 0x64, 0x5f, 0x72, 0x61, 0x6e, 0x6b, 0x22, 0x40,
-	0x0a, 0x0c, 0x51, 0x52, 0x4d, 0x61, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x53, 0x65, 0x74, 0x12, 0x30,
-	0x0a, 0x0b, 0x71, 0x72, 0x5f, 0x6d, 0x61, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x01, 0x20,
-	0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x71, 0x72, 0x70, 0x62, 0x2e, 0x51, 0x52, 0x4d, 0x61, 0x70,
-	0x70, 0x69, 0x6e, 0x67, 0x52, 0x0a, 0x71, 0x72, 0x4d, 0x61, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x73,
-	0x22, 0xfc, 0x01, 0x0a, 0x09, 0x47, 0x61, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x22,
-	0x0a, 0x0a, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x03, 0x48, 0x00, 0x52, 0x09, 0x75, 0x73, 0x65, 0x72, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x88,
-	0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x6c, 0x69, 0x66, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03,
-	0x48, 0x01, 0x52, 0x04, 0x6c, 0x69, 0x66, 0x65, 0x88, 0x01, 0x01, 0x12, 0x1a, 0x0a, 0x06, 0x68,
-	0x61, 0x73, 0x5f, 0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x48, 0x02, 0x52, 0x05, 0x68,
-	0x61, 0x73, 0x41, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x1a, 0x0a, 0x06, 0x68, 0x61, 0x73, 0x5f, 0x63,
-	0x75, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x48, 0x03, 0x52, 0x05, 0x68, 0x61, 0x73, 0x43, 0x75,
-	0x88, 0x01, 0x01, 0x12, 0x1a, 0x0a, 0x06, 0x68, 0x61, 0x73, 0x5f, 0x73, 0x6e, 0x18, 0x05, 0x20,
-	0x01, 0x28, 0x08, 0x48, 0x04, 0x52, 0x05, 0x68, 0x61, 0x73, 0x53, 0x6e, 0x88, 0x01, 0x01, 0x12,
-	0x1a, 0x0a, 0x06, 0x68, 0x61, 0x73, 0x5f, 0x7a, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x48,
-	0x05, 0x52, 0x05, 0x68, 0x61, 0x73, 0x5a, 0x6e, 0x88, 0x01, 0x01, 0x42, 0x0d, 0x0a, 0x0b, 0x5f,
-	0x75, 0x73, 0x65, 0x72, 0x5f, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x6c,
-	0x69, 0x66, 0x65, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x68, 0x61, 0x73, 0x5f, 0x61, 0x6c, 0x42, 0x09,
-	0x0a, 0x07, 0x5f, 0x68, 0x61, 0x73, 0x5f, 0x63, 0x75, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x68, 0x61,
-	0x73, 0x5f, 0x73, 0x6e, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x68, 0x61, 0x73, 0x5f, 0x7a, 0x6e, 0x22,
-	0xcf, 0x04, 0x0a, 0x09, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4c, 0x6f, 0x67, 0x12, 0x2a, 0x0a,
-	0x0e, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x5f, 0x75, 0x73, 0x65, 0x63, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x0d, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
-	0x6d, 0x70, 0x55, 0x73, 0x65, 0x63, 0x88, 0x01, 0x01, 0x12, 0x2b, 0x0a, 0x0f, 0x63, 0x6c, 0x75,
-	0x65, 0x5f, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x09, 0x48, 0x01, 0x52, 0x0d, 0x63, 0x6c, 0x75, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x4e,
-	0x61, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x12, 0x33, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x71, 0x72, 0x70, 0x62, 0x2e, 0x41, 0x63, 0x74, 0x69,
-	0x6f, 0x6e, 0x4c, 0x6f, 0x67, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65,
-	0x48, 0x02, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x88, 0x01, 0x01, 0x12, 0x31, 0x0a, 0x09, 0x6f,
-	0x6c, 0x64, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f,
-	0x2e, 0x71, 0x72, 0x70, 0x62, 0x2e, 0x47, 0x61, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x48,
-	0x03, 0x52, 0x08, 0x6f, 0x6c, 0x64, 0x53, 0x74, 0x61, 0x74, 0x65, 0x88, 0x01, 0x01, 0x12, 0x39,
-	0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1c,
-	0x2e, 0x71, 0x72, 0x70, 0x62, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4c, 0x6f, 0x67, 0x2e,
-	0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x48, 0x04, 0x52, 0x06,
-	0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x88, 0x01, 0x01, 0x22, 0x3a, 0x0a, 0x0a, 0x41, 0x63, 0x74,
+	0x73, 0x77, 0x65, 0x72, 0x73, 0x12, 0x2c, 0x0a, 0x0f, 0x70, 0x72, 0x65, 0x73, 0x65, 0x6e, 0x63,
+	0x65, 0x5f, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02,
+	0x52, 0x0e, 0x70, 0x72, 0x65, 0x73, 0x65, 0x6e, 0x63, 0x65, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74,
+	0x88, 0x01, 0x01, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65,
+	0x42, 0x07, 0x0a, 0x05, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x42, 0x12, 0x0a, 0x10, 0x5f, 0x70, 0x72,
+	0x65, 0x73, 0x65, 0x6e, 0x63, 0x65, 0x5f, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x4a, 0x04, 0x08,
+	0x01, 0x10, 0x02, 0x22, 0x87, 0x03, 0x0a, 0x09, 0x51, 0x52, 0x4d, 0x61, 0x70, 0x70, 0x69, 0x6e,
+	0x67, 0x12, 0x1f, 0x0a, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x88,
+	0x01, 0x01, 0x12, 0x26, 0x0a, 0x0c, 0x64, 0x69, 0x73, 0x70, 0x6c, 0x61, 0x79, 0x5f, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x0b, 0x64, 0x69, 0x73, 0x70,
+	0x6c, 0x61, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x12, 0x1b, 0x0a, 0x06, 0x71, 0x72,
+	0x63, 0x6f, 0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x06, 0x71, 0x72,
+	0x63, 0x6f, 0x64, 0x65, 0x88, 0x01, 0x01, 0x12, 0x30, 0x0a, 0x09, 0x63, 0x61, 0x72, 0x64, 0x5f,
+	0x73, 0x75, 0x69, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0e, 0x2e, 0x71, 0x72, 0x70,
+	0x62, 0x2e, 0x43, 0x61, 0x72, 0x64, 0x53, 0x75, 0x69, 0x74, 0x48, 0x03, 0x52, 0x08, 0x63, 0x61,
+	0x72, 0x64, 0x53, 0x75, 0x69, 0x74, 0x88, 0x01, 0x01, 0x12, 0x20, 0x0a, 0x09, 0x63, 0x61, 0x72,
+	0x64, 0x5f, 0x72, 0x61, 0x6e, 0x6b, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x48, 0x04, 0x52, 0x08,
+	0x63, 0x61, 0x72, 0x64, 0x52, 0x61, 0x6e, 0x6b, 0x88, 0x01, 0x01, 0x12, 0x35, 0x0a, 0x08, 0x68,
+	0x61, 0x6e, 0x64, 0x69, 0x63, 0x61, 0x70, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e,
+	0x71, 0x72, 0x70, 0x62, 0x2e, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x48, 0x61, 0x6e, 0x64, 0x69,
+	0x63, 0x61, 0x70, 0x48, 0x05, 0x52, 0x08, 0x68, 0x61, 0x6e, 0x64, 0x69, 0x63, 0x61, 0x70, 0x88,
+	0x01, 0x01, 0x12, 0x26, 0x0a, 0x0c, 0x64, 0x69, 0x67, 0x69, 0x74, 0x61, 0x6c, 0x5f, 0x6f, 0x6e,
+	0x6c, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x48, 0x06, 0x52, 0x0b, 0x64, 0x69, 0x67, 0x69,
+	0x74, 0x61, 0x6c, 0x4f, 0x6e, 0x6c, 0x79, 0x88, 0x01, 0x01, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x75,
+	0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x64, 0x69, 0x73, 0x70,
+	0x6c, 0x61, 0x79, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x71, 0x72, 0x63,
+	0x6f, 0x64, 0x65, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x63, 0x61, 0x72, 0x64, 0x5f, 0x73, 0x75, 0x69,
+	0x74, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x63, 0x61, 0x72, 0x64, 0x5f, 0x72, 0x61, 0x6e, 0x6b, 0x42,
+	0x0b, 0x0a, 0x09, 0x5f, 0x68, 0x61, 0x6e, 0x64, 0x69, 0x63, 0x61, 0x70, 0x42, 0x0f, 0x0a, 0x0d,
+	0x5f, 0x64, 0x69, 0x67, 0x69, 0x74, 0x61, 0x6c, 0x5f, 0x6f, 0x6e, 0x6c, 0x79, 0x22, 0xda, 0x01,
+	0x0a, 0x0e, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x48, 0x61, 0x6e, 0x64, 0x69, 0x63, 0x61, 0x70,
+	0x12, 0x24, 0x0a, 0x0b, 0x65, 0x78, 0x74, 0x72, 0x61, 0x5f, 0x6c, 0x69, 0x76, 0x65, 0x73, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x0a, 0x65, 0x78, 0x74, 0x72, 0x61, 0x4c, 0x69,
+	0x76, 0x65, 0x73, 0x88, 0x01, 0x01, 0x12, 0x22, 0x0a, 0x0a, 0x6e, 0x6f, 0x5f, 0x70, 0x65, 0x6e,
+	0x61, 0x6c, 0x74, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x48, 0x01, 0x52, 0x09, 0x6e, 0x6f,
+	0x50, 0x65, 0x6e, 0x61, 0x6c, 0x74, 0x79, 0x88, 0x01, 0x01, 0x12, 0x1b, 0x0a, 0x06, 0x68, 0x65,
+	0x6c, 0x70, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x06, 0x68, 0x65,
+	0x6c, 0x70, 0x65, 0x72, 0x88, 0x01, 0x01, 0x12, 0x26, 0x0a, 0x0c, 0x68, 0x65, 0x6c, 0x70, 0x65,
+	0x72, 0x5f, 0x73, 0x63, 0x61, 0x6e, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x48, 0x03, 0x52,
+	0x0b, 0x68, 0x65, 0x6c, 0x70, 0x65, 0x72, 0x53, 0x63, 0x61, 0x6e, 0x73, 0x88, 0x01, 0x01, 0x42,
+	0x0e, 0x0a, 0x0c, 0x5f, 0x65, 0x78, 0x74, 0x72, 0x61, 0x5f, 0x6c, 0x69, 0x76, 0x65, 0x73, 0x42,
+	0x0d, 0x0a, 0x0b, 0x5f, 0x6e, 0x6f, 0x5f, 0x70, 0x65, 0x6e, 0x61, 0x6c, 0x74, 0x79, 0x42, 0x09,
+	0x0a, 0x07, 0x5f, 0x68, 0x65, 0x6c, 0x70, 0x65, 0x72, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x68, 0x65,
+	0x6c, 0x70, 0x65, 0x72, 0x5f, 0x73, 0x63, 0x61, 0x6e, 0x73, 0x22, 0x40, 0x0a, 0x0c, 0x51, 0x52,
+	0x4d, 0x61, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x53, 0x65, 0x74, 0x12, 0x30, 0x0a, 0x0b, 0x71, 0x72,
+	0x5f, 0x6d, 0x61, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x0f, 0x2e, 0x71, 0x72, 0x70, 0x62, 0x2e, 0x51, 0x52, 0x4d, 0x61, 0x70, 0x70, 0x69, 0x6e, 0x67,
+	0x52, 0x0a, 0x71, 0x72, 0x4d, 0x61, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x73, 0x22, 0x81, 0x09, 0x0a,
+	0x09, 0x47, 0x61, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x22, 0x0a, 0x0a, 0x75, 0x73,
+	0x65, 0x72, 0x5f, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00,
+	0x52, 0x09, 0x75, 0x73, 0x65, 0x72, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x17,
+	0x0a, 0x04, 0x6c, 0x69, 0x66, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x48, 0x01, 0x52, 0x04,
+	0x6c, 0x69, 0x66, 0x65, 0x88, 0x01, 0x01, 0x12, 0x1a, 0x0a, 0x06, 0x68, 0x61, 0x73, 0x5f, 0x61,
+	0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x48, 0x02, 0x52, 0x05, 0x68, 0x61, 0x73, 0x41, 0x6c,
+	0x88, 0x01, 0x01, 0x12, 0x1a, 0x0a, 0x06, 0x68, 0x61, 0x73, 0x5f, 0x63, 0x75, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x08, 0x48, 0x03, 0x52, 0x05, 0x68, 0x61, 0x73, 0x43, 0x75, 0x88, 0x01, 0x01, 0x12,
+	0x1a, 0x0a, 0x06, 0x68, 0x61, 0x73, 0x5f, 0x73, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x48,
+	0x04, 0x52, 0x05, 0x68, 0x61, 0x73, 0x53, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x1a, 0x0a, 0x06, 0x68,
+	0x61, 0x73, 0x5f, 0x7a, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x48, 0x05, 0x52, 0x05, 0x68,
+	0x61, 0x73, 0x5a, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x28, 0x0a, 0x0d, 0x70, 0x68, 0x6f, 0x74, 0x6f,
+	0x5f, 0x70, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x48, 0x06,
+	0x52, 0x0c, 0x70, 0x68, 0x6f, 0x74, 0x6f, 0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x88, 0x01,
+	0x01, 0x12, 0x2a, 0x0a, 0x0e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x5f, 0x70, 0x72, 0x6f, 0x67, 0x72,
+	0x65, 0x73, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x48, 0x07, 0x52, 0x0d, 0x72, 0x6f, 0x75,
+	0x74, 0x65, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x88, 0x01, 0x01, 0x12, 0x30, 0x0a,
+	0x0b, 0x62, 0x69, 0x6e, 0x67, 0x6f, 0x5f, 0x63, 0x65, 0x6c, 0x6c, 0x73, 0x18, 0x09, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x71, 0x72, 0x70, 0x62, 0x2e, 0x42, 0x69, 0x6e, 0x67, 0x6f, 0x43,
+	0x65, 0x6c, 0x6c, 0x52, 0x0a, 0x62, 0x69, 0x6e, 0x67, 0x6f, 0x43, 0x65, 0x6c, 0x6c, 0x73, 0x12,
+	0x24, 0x0a, 0x0b, 0x62, 0x69, 0x6e, 0x67, 0x6f, 0x5f, 0x6c, 0x69, 0x6e, 0x65, 0x73, 0x18, 0x0a,
+	0x20, 0x01, 0x28, 0x03, 0x48, 0x08, 0x52, 0x0a, 0x62, 0x69, 0x6e, 0x67, 0x6f, 0x4c, 0x69, 0x6e,
+	0x65, 0x73, 0x88, 0x01, 0x01, 0x12, 0x20, 0x0a, 0x09, 0x62, 0x69, 0x6e, 0x67, 0x6f, 0x5f, 0x77,
+	0x6f, 0x6e, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x08, 0x48, 0x09, 0x52, 0x08, 0x62, 0x69, 0x6e, 0x67,
+	0x6f, 0x57, 0x6f, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x2f, 0x0a, 0x07, 0x62, 0x75, 0x64, 0x64, 0x69,
+	0x65, 0x73, 0x18, 0x0c, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x71, 0x72, 0x70, 0x62, 0x2e,
+	0x42, 0x75, 0x64, 0x64, 0x79, 0x41, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x52,
+	0x07, 0x62, 0x75, 0x64, 0x64, 0x69, 0x65, 0x73, 0x12, 0x2e, 0x0a, 0x0a, 0x70, 0x6f, 0x6b, 0x65,
+	0x72, 0x5f, 0x68, 0x61, 0x6e, 0x64, 0x18, 0x0d, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x71,
+	0x72, 0x70, 0x62, 0x2e, 0x50, 0x6f, 0x6b, 0x65, 0x72, 0x43, 0x61, 0x72, 0x64, 0x52, 0x09, 0x70,
+	0x6f, 0x6b, 0x65, 0x72, 0x48, 0x61, 0x6e, 0x64, 0x12, 0x35, 0x0a, 0x0b, 0x70, 0x6f, 0x6b, 0x65,
+	0x72, 0x5f, 0x6f, 0x66, 0x66, 0x65, 0x72, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e,
+	0x71, 0x72, 0x70, 0x62, 0x2e, 0x50, 0x6f, 0x6b, 0x65, 0x72, 0x43, 0x61, 0x72, 0x64, 0x48, 0x0a,
+	0x52, 0x0a, 0x70, 0x6f, 0x6b, 0x65, 0x72, 0x4f, 0x66, 0x66, 0x65, 0x72, 0x88, 0x01, 0x01, 0x12,
+	0x1b, 0x0a, 0x06, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x03, 0x48,
+	0x0b, 0x52, 0x06, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x88, 0x01, 0x01, 0x12, 0x2a, 0x0a, 0x0e,
+	0x77, 0x72, 0x6f, 0x6e, 0x67, 0x5f, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x73, 0x18, 0x10,
+	0x20, 0x01, 0x28, 0x03, 0x48, 0x0c, 0x52, 0x0d, 0x77, 0x72, 0x6f, 0x6e, 0x67, 0x41, 0x74, 0x74,
+	0x65, 0x6d, 0x70, 0x74, 0x73, 0x88, 0x01, 0x01, 0x12, 0x22, 0x0a, 0x0a, 0x68, 0x69, 0x6e, 0x74,
+	0x5f, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x11, 0x20, 0x01, 0x28, 0x03, 0x48, 0x0d, 0x52, 0x09,
+	0x68, 0x69, 0x6e, 0x74, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x2a, 0x0a, 0x0e,
+	0x68, 0x69, 0x6e, 0x74, 0x73, 0x5f, 0x72, 0x65, 0x76, 0x65, 0x61, 0x6c, 0x65, 0x64, 0x18, 0x12,
+	0x20, 0x01, 0x28, 0x03, 0x48, 0x0e, 0x52, 0x0d, 0x68, 0x69, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x76,
+	0x65, 0x61, 0x6c, 0x65, 0x64, 0x88, 0x01, 0x01, 0x12, 0x2f, 0x0a, 0x11, 0x68, 0x65, 0x6c, 0x70,
+	0x65, 0x72, 0x5f, 0x73, 0x63, 0x61, 0x6e, 0x73, 0x5f, 0x75, 0x73, 0x65, 0x64, 0x18, 0x13, 0x20,
+	0x01, 0x28, 0x03, 0x48, 0x0f, 0x52, 0x0f, 0x68, 0x65, 0x6c, 0x70, 0x65, 0x72, 0x53, 0x63, 0x61,
+	0x6e, 0x73, 0x55, 0x73, 0x65, 0x64, 0x88, 0x01, 0x01, 0x12, 0x33, 0x0a, 0x08, 0x76, 0x61, 0x72,
+	0x69, 0x61, 0x6e, 0x74, 0x73, 0x18, 0x14, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x71, 0x72,
+	0x70, 0x62, 0x2e, 0x56, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x41, 0x73, 0x73, 0x69, 0x67, 0x6e,
+	0x6d, 0x65, 0x6e, 0x74, 0x52, 0x08, 0x76, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x73, 0x12, 0x28,
+	0x0a, 0x0d, 0x72, 0x65, 0x61, 0x63, 0x68, 0x65, 0x64, 0x5f, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18,
+	0x15, 0x20, 0x01, 0x28, 0x03, 0x48, 0x10, 0x52, 0x0c, 0x72, 0x65, 0x61, 0x63, 0x68, 0x65, 0x64,
+	0x4c, 0x65, 0x76, 0x65, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x26, 0x0a, 0x0c, 0x72, 0x65, 0x61, 0x63,
+	0x68, 0x65, 0x64, 0x5f, 0x75, 0x73, 0x65, 0x63, 0x18, 0x16, 0x20, 0x01, 0x28, 0x03, 0x48, 0x11,
+	0x52, 0x0b, 0x72, 0x65, 0x61, 0x63, 0x68, 0x65, 0x64, 0x55, 0x73, 0x65, 0x63, 0x88, 0x01, 0x01,
+	0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x42,
+	0x07, 0x0a, 0x05, 0x5f, 0x6c, 0x69, 0x66, 0x65, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x68, 0x61, 0x73,
+	0x5f, 0x61, 0x6c, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x68, 0x61, 0x73, 0x5f, 0x63, 0x75, 0x42, 0x09,
+	0x0a, 0x07, 0x5f, 0x68, 0x61, 0x73, 0x5f, 0x73, 0x6e, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x68, 0x61,
+	0x73, 0x5f, 0x7a, 0x6e, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x70, 0x68, 0x6f, 0x74, 0x6f, 0x5f, 0x70,
+	0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x72, 0x6f, 0x75, 0x74, 0x65,
+	0x5f, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x62, 0x69,
+	0x6e, 0x67, 0x6f, 0x5f, 0x6c, 0x69, 0x6e, 0x65, 0x73, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x62, 0x69,
+	0x6e, 0x67, 0x6f, 0x5f, 0x77, 0x6f, 0x6e, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x70, 0x6f, 0x6b, 0x65,
+	0x72, 0x5f, 0x6f, 0x66, 0x66, 0x65, 0x72, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x70, 0x6f, 0x69, 0x6e,
+	0x74, 0x73, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x77, 0x72, 0x6f, 0x6e, 0x67, 0x5f, 0x61, 0x74, 0x74,
+	0x65, 0x6d, 0x70, 0x74, 0x73, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x68, 0x69, 0x6e, 0x74, 0x5f, 0x6c,
+	0x65, 0x76, 0x65, 0x6c, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x68, 0x69, 0x6e, 0x74, 0x73, 0x5f, 0x72,
+	0x65, 0x76, 0x65, 0x61, 0x6c, 0x65, 0x64, 0x42, 0x14, 0x0a, 0x12, 0x5f, 0x68, 0x65, 0x6c, 0x70,
+	0x65, 0x72, 0x5f, 0x73, 0x63, 0x61, 0x6e, 0x73, 0x5f, 0x75, 0x73, 0x65, 0x64, 0x42, 0x10, 0x0a,
+	0x0e, 0x5f, 0x72, 0x65, 0x61, 0x63, 0x68, 0x65, 0x64, 0x5f, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x42,
+	0x0f, 0x0a, 0x0d, 0x5f, 0x72, 0x65, 0x61, 0x63, 0x68, 0x65, 0x64, 0x5f, 0x75, 0x73, 0x65, 0x63,
+	0x22, 0x74, 0x0a, 0x11, 0x56, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x41, 0x73, 0x73, 0x69, 0x67,
+	0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x24, 0x0a, 0x0b, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f,
+	0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x0a, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x88, 0x01, 0x01, 0x12, 0x1d, 0x0a, 0x07, 0x76,
+	0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x07,
+	0x76, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x88, 0x01, 0x01, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x76,
+	0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x22, 0x9b, 0x01, 0x0a, 0x09, 0x50, 0x6f, 0x6b, 0x65, 0x72,
+	0x43, 0x61, 0x72, 0x64, 0x12, 0x27, 0x0a, 0x04, 0x73, 0x75, 0x69, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x0e, 0x2e, 0x71, 0x72, 0x70, 0x62, 0x2e, 0x43, 0x61, 0x72, 0x64, 0x53, 0x75,
+	0x69, 0x74, 0x48, 0x00, 0x52, 0x04, 0x73, 0x75, 0x69, 0x74, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a,
+	0x04, 0x72, 0x61, 0x6e, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x48, 0x01, 0x52, 0x04, 0x72,
+	0x61, 0x6e, 0x6b, 0x88, 0x01, 0x01, 0x12, 0x28, 0x0a, 0x0d, 0x66, 0x72, 0x6f, 0x6d, 0x5f, 0x75,
+	0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52,
+	0x0c, 0x66, 0x72, 0x6f, 0x6d, 0x55, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x88, 0x01, 0x01,
+	0x42, 0x07, 0x0a, 0x05, 0x5f, 0x73, 0x75, 0x69, 0x74, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x72, 0x61,
+	0x6e, 0x6b, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x5f, 0x75, 0x73, 0x65, 0x72,
+	0x6e, 0x61, 0x6d, 0x65, 0x22, 0x97, 0x01, 0x0a, 0x0f, 0x42, 0x75, 0x64, 0x64, 0x79, 0x41, 0x73,
+	0x73, 0x69, 0x67, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x24, 0x0a, 0x0b, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52,
+	0x0a, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x88, 0x01, 0x01, 0x12, 0x1f,
+	0x0a, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x48, 0x01, 0x52, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x12,
+	0x17, 0x0a, 0x04, 0x63, 0x6c, 0x75, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52,
+	0x04, 0x63, 0x6c, 0x75, 0x65, 0x88, 0x01, 0x01, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x75, 0x73, 0x65,
+	0x72, 0x6e, 0x61, 0x6d, 0x65, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x63, 0x6c, 0x75, 0x65, 0x22, 0xc0,
+	0x08, 0x0a, 0x09, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4c, 0x6f, 0x67, 0x12, 0x2a, 0x0a, 0x0e,
+	0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x5f, 0x75, 0x73, 0x65, 0x63, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x0d, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x55, 0x73, 0x65, 0x63, 0x88, 0x01, 0x01, 0x12, 0x2b, 0x0a, 0x0f, 0x63, 0x6c, 0x75, 0x65,
+	0x5f, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x48, 0x01, 0x52, 0x0d, 0x63, 0x6c, 0x75, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x4e, 0x61,
+	0x6d, 0x65, 0x88, 0x01, 0x01, 0x12, 0x33, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x71, 0x72, 0x70, 0x62, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x4c, 0x6f, 0x67, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x48,
+	0x02, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x88, 0x01, 0x01, 0x12, 0x31, 0x0a, 0x09, 0x6f, 0x6c,
+	0x64, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e,
+	0x71, 0x72, 0x70, 0x62, 0x2e, 0x47, 0x61, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x48, 0x03,
+	0x52, 0x08, 0x6f, 0x6c, 0x64, 0x53, 0x74, 0x61, 0x74, 0x65, 0x88, 0x01, 0x01, 0x12, 0x39, 0x0a,
+	0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1c, 0x2e,
+	0x71, 0x72, 0x70, 0x62, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4c, 0x6f, 0x67, 0x2e, 0x41,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x48, 0x04, 0x52, 0x06, 0x72,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x88, 0x01, 0x01, 0x22, 0xd6, 0x01, 0x0a, 0x0a, 0x41, 0x63, 0x74,
 	0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x12, 0x16, 0x0a, 0x12, 0x41, 0x43, 0x54, 0x49, 0x4f,
 	0x4e, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12,
 	0x14, 0x0a, 0x10, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x53,
-	0x43, 0x41, 0x4e, 0x10, 0x01, 0x22, 0xc0, 0x01, 0x0a, 0x0c, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e,
-	0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x16, 0x0a, 0x12, 0x52, 0x45, 0x53, 0x55, 0x4c, 0x54,
-	0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x13,
-	0x0a, 0x0f, 0x52, 0x45, 0x53, 0x55, 0x4c, 0x54, 0x5f, 0x50, 0x52, 0x4f, 0x47, 0x52, 0x45, 0x53,
-	0x53, 0x10, 0x01, 0x12, 0x14, 0x0a, 0x10, 0x52, 0x45, 0x53, 0x55, 0x4c, 0x54, 0x5f, 0x4c, 0x4f,
-	0x53, 0x54, 0x5f, 0x4c, 0x49, 0x46, 0x45, 0x10, 0x02, 0x12, 0x1d, 0x0a, 0x19, 0x52, 0x45, 0x53,
-	0x55, 0x4c, 0x54, 0x5f, 0x41, 0x4c, 0x52, 0x45, 0x41, 0x44, 0x59, 0x5f, 0x56, 0x49, 0x43, 0x54,
-	0x4f, 0x52, 0x49, 0x4f, 0x55, 0x53, 0x10, 0x03, 0x12, 0x17, 0x0a, 0x13, 0x52, 0x45, 0x53, 0x55,
-	0x4c, 0x54, 0x5f, 0x41, 0x4c, 0x52, 0x45, 0x41, 0x44, 0x59, 0x5f, 0x44, 0x45, 0x41, 0x44, 0x10,
-	0x04, 0x12, 0x18, 0x0a, 0x14, 0x52, 0x45, 0x53, 0x55, 0x4c, 0x54, 0x5f, 0x47, 0x52, 0x41, 0x42,
-	0x42, 0x45, 0x44, 0x5f, 0x4d, 0x45, 0x54, 0x41, 0x4c, 0x10, 0x05, 0x12, 0x1b, 0x0a, 0x17, 0x52,
-	0x45, 0x53, 0x55, 0x4c, 0x54, 0x5f, 0x4e, 0x4f, 0x5f, 0x47, 0x52, 0x41, 0x42, 0x42, 0x45, 0x44,
-	0x5f, 0x4d, 0x45, 0x54, 0x41, 0x4c, 0x10, 0x06, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x74, 0x69, 0x6d,
+	0x43, 0x41, 0x4e, 0x10, 0x01, 0x12, 0x16, 0x0a, 0x12, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f,
+	0x54, 0x45, 0x58, 0x54, 0x5f, 0x41, 0x4e, 0x53, 0x57, 0x45, 0x52, 0x10, 0x02, 0x12, 0x17, 0x0a,
+	0x13, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x50, 0x48, 0x4f, 0x54, 0x4f, 0x5f, 0x55, 0x50,
+	0x4c, 0x4f, 0x41, 0x44, 0x10, 0x03, 0x12, 0x17, 0x0a, 0x13, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e,
+	0x5f, 0x50, 0x48, 0x4f, 0x54, 0x4f, 0x5f, 0x52, 0x45, 0x56, 0x49, 0x45, 0x57, 0x10, 0x04, 0x12,
+	0x11, 0x0a, 0x0d, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x54, 0x41, 0x47, 0x47, 0x45, 0x44,
+	0x10, 0x05, 0x12, 0x14, 0x0a, 0x10, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x43, 0x41, 0x52,
+	0x44, 0x5f, 0x53, 0x57, 0x41, 0x50, 0x10, 0x06, 0x12, 0x0f, 0x0a, 0x0b, 0x41, 0x43, 0x54, 0x49,
+	0x4f, 0x4e, 0x5f, 0x48, 0x49, 0x4e, 0x54, 0x10, 0x07, 0x12, 0x16, 0x0a, 0x12, 0x41, 0x43, 0x54,
+	0x49, 0x4f, 0x4e, 0x5f, 0x48, 0x45, 0x4c, 0x50, 0x45, 0x52, 0x5f, 0x53, 0x43, 0x41, 0x4e, 0x10,
+	0x08, 0x22, 0x94, 0x04, 0x0a, 0x0c, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x12, 0x16, 0x0a, 0x12, 0x52, 0x45, 0x53, 0x55, 0x4c, 0x54, 0x5f, 0x55, 0x4e, 0x53,
+	0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x13, 0x0a, 0x0f, 0x52, 0x45,
+	0x53, 0x55, 0x4c, 0x54, 0x5f, 0x50, 0x52, 0x4f, 0x47, 0x52, 0x45, 0x53, 0x53, 0x10, 0x01, 0x12,
+	0x14, 0x0a, 0x10, 0x52, 0x45, 0x53, 0x55, 0x4c, 0x54, 0x5f, 0x4c, 0x4f, 0x53, 0x54, 0x5f, 0x4c,
+	0x49, 0x46, 0x45, 0x10, 0x02, 0x12, 0x1d, 0x0a, 0x19, 0x52, 0x45, 0x53, 0x55, 0x4c, 0x54, 0x5f,
+	0x41, 0x4c, 0x52, 0x45, 0x41, 0x44, 0x59, 0x5f, 0x56, 0x49, 0x43, 0x54, 0x4f, 0x52, 0x49, 0x4f,
+	0x55, 0x53, 0x10, 0x03, 0x12, 0x17, 0x0a, 0x13, 0x52, 0x45, 0x53, 0x55, 0x4c, 0x54, 0x5f, 0x41,
+	0x4c, 0x52, 0x45, 0x41, 0x44, 0x59, 0x5f, 0x44, 0x45, 0x41, 0x44, 0x10, 0x04, 0x12, 0x18, 0x0a,
+	0x14, 0x52, 0x45, 0x53, 0x55, 0x4c, 0x54, 0x5f, 0x47, 0x52, 0x41, 0x42, 0x42, 0x45, 0x44, 0x5f,
+	0x4d, 0x45, 0x54, 0x41, 0x4c, 0x10, 0x05, 0x12, 0x1b, 0x0a, 0x17, 0x52, 0x45, 0x53, 0x55, 0x4c,
+	0x54, 0x5f, 0x4e, 0x4f, 0x5f, 0x47, 0x52, 0x41, 0x42, 0x42, 0x45, 0x44, 0x5f, 0x4d, 0x45, 0x54,
+	0x41, 0x4c, 0x10, 0x06, 0x12, 0x12, 0x0a, 0x0e, 0x52, 0x45, 0x53, 0x55, 0x4c, 0x54, 0x5f, 0x49,
+	0x47, 0x4e, 0x4f, 0x52, 0x45, 0x44, 0x10, 0x07, 0x12, 0x18, 0x0a, 0x14, 0x52, 0x45, 0x53, 0x55,
+	0x4c, 0x54, 0x5f, 0x50, 0x48, 0x4f, 0x54, 0x4f, 0x5f, 0x50, 0x45, 0x4e, 0x44, 0x49, 0x4e, 0x47,
+	0x10, 0x08, 0x12, 0x19, 0x0a, 0x15, 0x52, 0x45, 0x53, 0x55, 0x4c, 0x54, 0x5f, 0x50, 0x48, 0x4f,
+	0x54, 0x4f, 0x5f, 0x52, 0x45, 0x4a, 0x45, 0x43, 0x54, 0x45, 0x44, 0x10, 0x09, 0x12, 0x15, 0x0a,
+	0x11, 0x52, 0x45, 0x53, 0x55, 0x4c, 0x54, 0x5f, 0x43, 0x48, 0x45, 0x43, 0x4b, 0x50, 0x4f, 0x49,
+	0x4e, 0x54, 0x10, 0x0a, 0x12, 0x16, 0x0a, 0x12, 0x52, 0x45, 0x53, 0x55, 0x4c, 0x54, 0x5f, 0x52,
+	0x4f, 0x55, 0x54, 0x45, 0x5f, 0x52, 0x45, 0x53, 0x45, 0x54, 0x10, 0x0b, 0x12, 0x15, 0x0a, 0x11,
+	0x52, 0x45, 0x53, 0x55, 0x4c, 0x54, 0x5f, 0x42, 0x49, 0x4e, 0x47, 0x4f, 0x5f, 0x43, 0x45, 0x4c,
+	0x4c, 0x10, 0x0c, 0x12, 0x18, 0x0a, 0x14, 0x52, 0x45, 0x53, 0x55, 0x4c, 0x54, 0x5f, 0x54, 0x41,
+	0x47, 0x47, 0x45, 0x44, 0x5f, 0x54, 0x41, 0x52, 0x47, 0x45, 0x54, 0x10, 0x0d, 0x12, 0x15, 0x0a,
+	0x11, 0x52, 0x45, 0x53, 0x55, 0x4c, 0x54, 0x5f, 0x57, 0x41, 0x53, 0x5f, 0x54, 0x41, 0x47, 0x47,
+	0x45, 0x44, 0x10, 0x0e, 0x12, 0x19, 0x0a, 0x15, 0x52, 0x45, 0x53, 0x55, 0x4c, 0x54, 0x5f, 0x43,
+	0x41, 0x52, 0x44, 0x5f, 0x43, 0x4f, 0x4c, 0x4c, 0x45, 0x43, 0x54, 0x45, 0x44, 0x10, 0x0f, 0x12,
+	0x17, 0x0a, 0x13, 0x52, 0x45, 0x53, 0x55, 0x4c, 0x54, 0x5f, 0x43, 0x41, 0x52, 0x44, 0x5f, 0x4f,
+	0x46, 0x46, 0x45, 0x52, 0x45, 0x44, 0x10, 0x10, 0x12, 0x12, 0x0a, 0x0e, 0x52, 0x45, 0x53, 0x55,
+	0x4c, 0x54, 0x5f, 0x53, 0x4b, 0x49, 0x50, 0x50, 0x45, 0x44, 0x10, 0x11, 0x12, 0x17, 0x0a, 0x13,
+	0x52, 0x45, 0x53, 0x55, 0x4c, 0x54, 0x5f, 0x57, 0x52, 0x4f, 0x4e, 0x47, 0x5f, 0x41, 0x4e, 0x53,
+	0x57, 0x45, 0x52, 0x10, 0x12, 0x12, 0x18, 0x0a, 0x14, 0x52, 0x45, 0x53, 0x55, 0x4c, 0x54, 0x5f,
+	0x48, 0x49, 0x4e, 0x54, 0x5f, 0x52, 0x45, 0x56, 0x45, 0x41, 0x4c, 0x45, 0x44, 0x10, 0x13, 0x12,
+	0x17, 0x0a, 0x13, 0x52, 0x45, 0x53, 0x55, 0x4c, 0x54, 0x5f, 0x43, 0x41, 0x52, 0x44, 0x5f, 0x53,
+	0x57, 0x41, 0x50, 0x50, 0x45, 0x44, 0x10, 0x14, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x74, 0x69, 0x6d,
 	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x5f, 0x75, 0x73, 0x65, 0x63, 0x42, 0x12, 0x0a, 0x10, 0x5f,
 	0x63, 0x6c, 0x75, 0x65, 0x5f, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x42,
 	0x07, 0x0a, 0x05, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x6f, 0x6c, 0x64,
 	0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x72, 0x65, 0x73, 0x75, 0x6c,
-	0x74, 0x22, 0xda, 0x02, 0x0a, 0x0c, 0x47, 0x61, 0x6d, 0x65, 0x51, 0x75, 0x65, 0x73, 0x74, 0x69,
-	0x6f, 0x6e, 0x12, 0x24, 0x0a, 0x0b, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69,
-	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x0a, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x69, 0x6f, 0x6e, 0x49, 0x64, 0x88, 0x01, 0x01, 0x12, 0x25, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0c, 0x2e, 0x71, 0x72, 0x70, 0x62, 0x2e, 0x47, 0x51,
-	0x54, 0x79, 0x70, 0x65, 0x48, 0x01, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x88, 0x01, 0x01, 0x12,
-	0x28, 0x0a, 0x0d, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x68, 0x74, 0x6d, 0x6c,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x0c, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69,
-	0x6f, 0x6e, 0x48, 0x74, 0x6d, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x23, 0x0a, 0x0d, 0x61, 0x6e, 0x73,
-	0x5f, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09,
-	0x52, 0x0c, 0x61, 0x6e, 0x73, 0x55, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x12, 0x20,
-	0x0a, 0x09, 0x73, 0x75, 0x72, 0x76, 0x65, 0x79, 0x5f, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28,
-	0x03, 0x48, 0x03, 0x52, 0x08, 0x73, 0x75, 0x72, 0x76, 0x65, 0x79, 0x49, 0x64, 0x88, 0x01, 0x01,
-	0x12, 0x38, 0x0a, 0x16, 0x73, 0x75, 0x72, 0x76, 0x65, 0x79, 0x5f, 0x74, 0x72, 0x75, 0x65, 0x5f,
-	0x69, 0x73, 0x5f, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x63, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08,
-	0x48, 0x04, 0x52, 0x13, 0x73, 0x75, 0x72, 0x76, 0x65, 0x79, 0x54, 0x72, 0x75, 0x65, 0x49, 0x73,
-	0x43, 0x6f, 0x72, 0x72, 0x65, 0x63, 0x74, 0x88, 0x01, 0x01, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x74,
-	0x79, 0x70, 0x65, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e,
-	0x5f, 0x68, 0x74, 0x6d, 0x6c, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x73, 0x75, 0x72, 0x76, 0x65, 0x79,
-	0x5f, 0x69, 0x64, 0x42, 0x19, 0x0a, 0x17, 0x5f, 0x73, 0x75, 0x72, 0x76, 0x65, 0x79, 0x5f, 0x74,
-	0x72, 0x75, 0x65, 0x5f, 0x69, 0x73, 0x5f, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x63, 0x74, 0x22, 0x45,
-	0x0a, 0x08, 0x47, 0x61, 0x6d, 0x65, 0x51, 0x53, 0x65, 0x74, 0x12, 0x39, 0x0a, 0x0e, 0x67, 0x61,
-	0x6d, 0x65, 0x5f, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03,
-	0x28, 0x0b, 0x32, 0x12, 0x2e, 0x71, 0x72, 0x70, 0x62, 0x2e, 0x47, 0x61, 0x6d, 0x65, 0x51, 0x75,
-	0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0d, 0x67, 0x61, 0x6d, 0x65, 0x51, 0x75, 0x65, 0x73,
-	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0xb6, 0x01, 0x0a, 0x0e, 0x53, 0x75, 0x72, 0x76, 0x65, 0x79,
-	0x51, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x24, 0x0a, 0x0b, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52,
-	0x0a, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x88, 0x01, 0x01, 0x12, 0x28,
-	0x0a, 0x0d, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x74, 0x65, 0x78, 0x74, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x0c, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f,
-	0x6e, 0x54, 0x65, 0x78, 0x74, 0x88, 0x01, 0x01, 0x12, 0x29, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x10, 0x2e, 0x71, 0x72, 0x70, 0x62, 0x2e, 0x53, 0x75,
-	0x72, 0x76, 0x65, 0x79, 0x54, 0x79, 0x70, 0x65, 0x48, 0x02, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65,
-	0x88, 0x01, 0x01, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e,
-	0x5f, 0x69, 0x64, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e,
-	0x5f, 0x74, 0x65, 0x78, 0x74, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x22, 0x6e,
-	0x0a, 0x0c, 0x53, 0x75, 0x72, 0x76, 0x65, 0x79, 0x41, 0x6e, 0x73, 0x77, 0x65, 0x72, 0x12, 0x24,
+	0x74, 0x22, 0xd1, 0x01, 0x0a, 0x0b, 0x47, 0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x45, 0x76, 0x65, 0x6e,
+	0x74, 0x12, 0x2e, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x15, 0x2e, 0x71, 0x72, 0x70, 0x62, 0x2e, 0x47, 0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x4b, 0x69, 0x6e, 0x64, 0x48, 0x00, 0x52, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x88, 0x01,
+	0x01, 0x12, 0x22, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x75, 0x73, 0x65, 0x63, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x03, 0x48, 0x01, 0x52, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x55, 0x73,
+	0x65, 0x63, 0x88, 0x01, 0x01, 0x12, 0x1e, 0x0a, 0x08, 0x65, 0x6e, 0x64, 0x5f, 0x75, 0x73, 0x65,
+	0x63, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x48, 0x02, 0x52, 0x07, 0x65, 0x6e, 0x64, 0x55, 0x73,
+	0x65, 0x63, 0x88, 0x01, 0x01, 0x12, 0x1d, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x03, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x88, 0x01, 0x01, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x6b, 0x69, 0x6e, 0x64, 0x42, 0x0d, 0x0a,
+	0x0b, 0x5f, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x75, 0x73, 0x65, 0x63, 0x42, 0x0b, 0x0a, 0x09,
+	0x5f, 0x65, 0x6e, 0x64, 0x5f, 0x75, 0x73, 0x65, 0x63, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x6d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x3b, 0x0a, 0x0e, 0x47, 0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x53, 0x65, 0x74, 0x12, 0x29, 0x0a, 0x06, 0x65, 0x76, 0x65, 0x6e, 0x74,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x71, 0x72, 0x70, 0x62, 0x2e, 0x47,
+	0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x06, 0x65, 0x76, 0x65, 0x6e,
+	0x74, 0x73, 0x22, 0x82, 0x08, 0x0a, 0x0c, 0x47, 0x61, 0x6d, 0x65, 0x51, 0x75, 0x65, 0x73, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x24, 0x0a, 0x0b, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x0a, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x88, 0x01, 0x01, 0x12, 0x25, 0x0a, 0x04, 0x74, 0x79, 0x70,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0c, 0x2e, 0x71, 0x72, 0x70, 0x62, 0x2e, 0x47,
+	0x51, 0x54, 0x79, 0x70, 0x65, 0x48, 0x01, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x88, 0x01, 0x01,
+	0x12, 0x28, 0x0a, 0x0d, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x68, 0x74, 0x6d,
+	0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x0c, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x69, 0x6f, 0x6e, 0x48, 0x74, 0x6d, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x23, 0x0a, 0x0d, 0x61, 0x6e,
+	0x73, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x0c, 0x61, 0x6e, 0x73, 0x55, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x12,
+	0x20, 0x0a, 0x09, 0x73, 0x75, 0x72, 0x76, 0x65, 0x79, 0x5f, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x03, 0x48, 0x03, 0x52, 0x08, 0x73, 0x75, 0x72, 0x76, 0x65, 0x79, 0x49, 0x64, 0x88, 0x01,
+	0x01, 0x12, 0x38, 0x0a, 0x16, 0x73, 0x75, 0x72, 0x76, 0x65, 0x79, 0x5f, 0x74, 0x72, 0x75, 0x65,
+	0x5f, 0x69, 0x73, 0x5f, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x63, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x08, 0x48, 0x04, 0x52, 0x13, 0x73, 0x75, 0x72, 0x76, 0x65, 0x79, 0x54, 0x72, 0x75, 0x65, 0x49,
+	0x73, 0x43, 0x6f, 0x72, 0x72, 0x65, 0x63, 0x74, 0x88, 0x01, 0x01, 0x12, 0x21, 0x0a, 0x0c, 0x74,
+	0x65, 0x78, 0x74, 0x5f, 0x61, 0x6e, 0x73, 0x77, 0x65, 0x72, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x0b, 0x74, 0x65, 0x78, 0x74, 0x41, 0x6e, 0x73, 0x77, 0x65, 0x72, 0x73, 0x12, 0x27,
+	0x0a, 0x0f, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65,
+	0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x55, 0x73,
+	0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x12, 0x3c, 0x0a, 0x18, 0x72, 0x6f, 0x75, 0x74, 0x65,
+	0x5f, 0x6d, 0x69, 0x73, 0x74, 0x61, 0x6b, 0x65, 0x5f, 0x63, 0x6f, 0x73, 0x74, 0x73, 0x5f, 0x6c,
+	0x69, 0x66, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x08, 0x48, 0x05, 0x52, 0x15, 0x72, 0x6f, 0x75,
+	0x74, 0x65, 0x4d, 0x69, 0x73, 0x74, 0x61, 0x6b, 0x65, 0x43, 0x6f, 0x73, 0x74, 0x73, 0x4c, 0x69,
+	0x66, 0x65, 0x88, 0x01, 0x01, 0x12, 0x28, 0x0a, 0x0d, 0x77, 0x72, 0x6f, 0x6e, 0x67, 0x5f, 0x70,
+	0x65, 0x6e, 0x61, 0x6c, 0x74, 0x79, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x03, 0x48, 0x06, 0x52, 0x0c,
+	0x77, 0x72, 0x6f, 0x6e, 0x67, 0x50, 0x65, 0x6e, 0x61, 0x6c, 0x74, 0x79, 0x88, 0x01, 0x01, 0x12,
+	0x31, 0x0a, 0x06, 0x72, 0x65, 0x77, 0x61, 0x72, 0x64, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x14, 0x2e, 0x71, 0x72, 0x70, 0x62, 0x2e, 0x51, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x65, 0x77, 0x61, 0x72, 0x64, 0x48, 0x07, 0x52, 0x06, 0x72, 0x65, 0x77, 0x61, 0x72, 0x64, 0x88,
+	0x01, 0x01, 0x12, 0x26, 0x0a, 0x0c, 0x6d, 0x61, 0x78, 0x5f, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70,
+	0x74, 0x73, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x03, 0x48, 0x08, 0x52, 0x0b, 0x6d, 0x61, 0x78, 0x41,
+	0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x73, 0x88, 0x01, 0x01, 0x12, 0x14, 0x0a, 0x05, 0x68, 0x69,
+	0x6e, 0x74, 0x73, 0x18, 0x0d, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x68, 0x69, 0x6e, 0x74, 0x73,
+	0x12, 0x24, 0x0a, 0x0b, 0x75, 0x6e, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18,
+	0x0e, 0x20, 0x01, 0x28, 0x09, 0x48, 0x09, 0x52, 0x0a, 0x75, 0x6e, 0x6c, 0x6f, 0x63, 0x6b, 0x54,
+	0x69, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x12, 0x2f, 0x0a, 0x06, 0x62, 0x61, 0x63, 0x6b, 0x75, 0x70,
+	0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x71, 0x72, 0x70, 0x62, 0x2e, 0x47, 0x61,
+	0x6d, 0x65, 0x51, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x0a, 0x52, 0x06, 0x62, 0x61,
+	0x63, 0x6b, 0x75, 0x70, 0x88, 0x01, 0x01, 0x12, 0x28, 0x0a, 0x0d, 0x62, 0x61, 0x63, 0x6b, 0x75,
+	0x70, 0x5f, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x18, 0x10, 0x20, 0x01, 0x28, 0x08, 0x48, 0x0b,
+	0x52, 0x0c, 0x62, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x41, 0x63, 0x74, 0x69, 0x76, 0x65, 0x88, 0x01,
+	0x01, 0x12, 0x1d, 0x0a, 0x07, 0x76, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x18, 0x11, 0x20, 0x01,
+	0x28, 0x09, 0x48, 0x0c, 0x52, 0x07, 0x76, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x88, 0x01, 0x01,
+	0x12, 0x1d, 0x0a, 0x07, 0x72, 0x65, 0x74, 0x69, 0x72, 0x65, 0x64, 0x18, 0x12, 0x20, 0x01, 0x28,
+	0x08, 0x48, 0x0d, 0x52, 0x07, 0x72, 0x65, 0x74, 0x69, 0x72, 0x65, 0x64, 0x88, 0x01, 0x01, 0x12,
+	0x23, 0x0a, 0x0a, 0x64, 0x69, 0x66, 0x66, 0x69, 0x63, 0x75, 0x6c, 0x74, 0x79, 0x18, 0x13, 0x20,
+	0x01, 0x28, 0x03, 0x48, 0x0e, 0x52, 0x0a, 0x64, 0x69, 0x66, 0x66, 0x69, 0x63, 0x75, 0x6c, 0x74,
+	0x79, 0x88, 0x01, 0x01, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f,
+	0x6e, 0x5f, 0x69, 0x64, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x42, 0x10, 0x0a,
+	0x0e, 0x5f, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x68, 0x74, 0x6d, 0x6c, 0x42,
+	0x0c, 0x0a, 0x0a, 0x5f, 0x73, 0x75, 0x72, 0x76, 0x65, 0x79, 0x5f, 0x69, 0x64, 0x42, 0x19, 0x0a,
+	0x17, 0x5f, 0x73, 0x75, 0x72, 0x76, 0x65, 0x79, 0x5f, 0x74, 0x72, 0x75, 0x65, 0x5f, 0x69, 0x73,
+	0x5f, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x63, 0x74, 0x42, 0x1b, 0x0a, 0x19, 0x5f, 0x72, 0x6f, 0x75,
+	0x74, 0x65, 0x5f, 0x6d, 0x69, 0x73, 0x74, 0x61, 0x6b, 0x65, 0x5f, 0x63, 0x6f, 0x73, 0x74, 0x73,
+	0x5f, 0x6c, 0x69, 0x66, 0x65, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x77, 0x72, 0x6f, 0x6e, 0x67, 0x5f,
+	0x70, 0x65, 0x6e, 0x61, 0x6c, 0x74, 0x79, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x72, 0x65, 0x77, 0x61,
+	0x72, 0x64, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x61, 0x74, 0x74, 0x65, 0x6d,
+	0x70, 0x74, 0x73, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x75, 0x6e, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x74,
+	0x69, 0x6d, 0x65, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x62, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x42, 0x10,
+	0x0a, 0x0e, 0x5f, 0x62, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x5f, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65,
+	0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x76, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x42, 0x0a, 0x0a, 0x08,
+	0x5f, 0x72, 0x65, 0x74, 0x69, 0x72, 0x65, 0x64, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x64, 0x69, 0x66,
+	0x66, 0x69, 0x63, 0x75, 0x6c, 0x74, 0x79, 0x22, 0x82, 0x01, 0x0a, 0x0e, 0x51, 0x75, 0x65, 0x73,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x77, 0x61, 0x72, 0x64, 0x12, 0x1b, 0x0a, 0x06, 0x70, 0x6f,
+	0x69, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x06, 0x70, 0x6f,
+	0x69, 0x6e, 0x74, 0x73, 0x88, 0x01, 0x01, 0x12, 0x19, 0x0a, 0x05, 0x6c, 0x69, 0x76, 0x65, 0x73,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x48, 0x01, 0x52, 0x05, 0x6c, 0x69, 0x76, 0x65, 0x73, 0x88,
+	0x01, 0x01, 0x12, 0x19, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x48, 0x02, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x88, 0x01, 0x01, 0x42, 0x09, 0x0a,
+	0x07, 0x5f, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x6c, 0x69, 0x76,
+	0x65, 0x73, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0xc5, 0x01, 0x0a,
+	0x10, 0x51, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x44, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74,
+	0x73, 0x12, 0x28, 0x0a, 0x0d, 0x77, 0x72, 0x6f, 0x6e, 0x67, 0x5f, 0x70, 0x65, 0x6e, 0x61, 0x6c,
+	0x74, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x0c, 0x77, 0x72, 0x6f, 0x6e,
+	0x67, 0x50, 0x65, 0x6e, 0x61, 0x6c, 0x74, 0x79, 0x88, 0x01, 0x01, 0x12, 0x31, 0x0a, 0x06, 0x72,
+	0x65, 0x77, 0x61, 0x72, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x71, 0x72,
+	0x70, 0x62, 0x2e, 0x51, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x77, 0x61, 0x72,
+	0x64, 0x48, 0x01, 0x52, 0x06, 0x72, 0x65, 0x77, 0x61, 0x72, 0x64, 0x88, 0x01, 0x01, 0x12, 0x26,
+	0x0a, 0x0c, 0x6d, 0x61, 0x78, 0x5f, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x73, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x03, 0x48, 0x02, 0x52, 0x0b, 0x6d, 0x61, 0x78, 0x41, 0x74, 0x74, 0x65, 0x6d,
+	0x70, 0x74, 0x73, 0x88, 0x01, 0x01, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x77, 0x72, 0x6f, 0x6e, 0x67,
+	0x5f, 0x70, 0x65, 0x6e, 0x61, 0x6c, 0x74, 0x79, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x72, 0x65, 0x77,
+	0x61, 0x72, 0x64, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x61, 0x74, 0x74, 0x65,
+	0x6d, 0x70, 0x74, 0x73, 0x22, 0x84, 0x01, 0x0a, 0x08, 0x47, 0x61, 0x6d, 0x65, 0x51, 0x53, 0x65,
+	0x74, 0x12, 0x39, 0x0a, 0x0e, 0x67, 0x61, 0x6d, 0x65, 0x5f, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x71, 0x72, 0x70, 0x62,
+	0x2e, 0x47, 0x61, 0x6d, 0x65, 0x51, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0d, 0x67,
+	0x61, 0x6d, 0x65, 0x51, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x2a, 0x0a, 0x0e,
+	0x72, 0x65, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x65, 0x64, 0x5f, 0x75, 0x73, 0x65, 0x63, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x0d, 0x72, 0x65, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x65,
+	0x64, 0x55, 0x73, 0x65, 0x63, 0x88, 0x01, 0x01, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x72, 0x65, 0x6f,
+	0x72, 0x64, 0x65, 0x72, 0x65, 0x64, 0x5f, 0x75, 0x73, 0x65, 0x63, 0x22, 0xb6, 0x01, 0x0a, 0x0e,
+	0x53, 0x75, 0x72, 0x76, 0x65, 0x79, 0x51, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x24,
 	0x0a, 0x0b, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
 	0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x0a, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x49,
-	0x64, 0x88, 0x01, 0x01, 0x12, 0x1c, 0x0a, 0x07, 0x69, 0x73, 0x5f, 0x74, 0x72, 0x75, 0x65, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x08, 0x48, 0x01, 0x52, 0x06, 0x69, 0x73, 0x54, 0x72, 0x75, 0x65, 0x88,
-	0x01, 0x01, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x5f,
-	0x69, 0x64, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x69, 0x73, 0x5f, 0x74, 0x72, 0x75, 0x65, 0x22, 0x4c,
-	0x0a, 0x09, 0x53, 0x75, 0x72, 0x76, 0x65, 0x79, 0x53, 0x65, 0x74, 0x12, 0x3f, 0x0a, 0x10, 0x73,
-	0x75, 0x72, 0x76, 0x65, 0x79, 0x5f, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18,
-	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x71, 0x72, 0x70, 0x62, 0x2e, 0x53, 0x75, 0x72,
-	0x76, 0x65, 0x79, 0x51, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0f, 0x73, 0x75, 0x72,
-	0x76, 0x65, 0x79, 0x51, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2a, 0x56, 0x0a, 0x08,
-	0x43, 0x61, 0x72, 0x64, 0x53, 0x75, 0x69, 0x74, 0x12, 0x19, 0x0a, 0x15, 0x43, 0x41, 0x52, 0x44,
-	0x5f, 0x53, 0x55, 0x49, 0x54, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45,
-	0x44, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x50, 0x41, 0x44, 0x45, 0x53, 0x10, 0x01, 0x12,
-	0x0a, 0x0a, 0x06, 0x48, 0x45, 0x41, 0x52, 0x54, 0x53, 0x10, 0x02, 0x12, 0x09, 0x0a, 0x05, 0x43,
-	0x4c, 0x55, 0x42, 0x53, 0x10, 0x03, 0x12, 0x0c, 0x0a, 0x08, 0x44, 0x49, 0x41, 0x4d, 0x4f, 0x4e,
-	0x44, 0x53, 0x10, 0x04, 0x2a, 0x53, 0x0a, 0x06, 0x47, 0x51, 0x54, 0x79, 0x70, 0x65, 0x12, 0x16,
-	0x0a, 0x12, 0x47, 0x51, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49,
-	0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x11, 0x0a, 0x0d, 0x55, 0x53, 0x45, 0x52, 0x4e, 0x41,
-	0x4d, 0x45, 0x5f, 0x4c, 0x49, 0x53, 0x54, 0x10, 0x01, 0x12, 0x0e, 0x0a, 0x0a, 0x53, 0x55, 0x52,
-	0x56, 0x45, 0x59, 0x5f, 0x41, 0x4e, 0x53, 0x10, 0x02, 0x12, 0x0e, 0x0a, 0x0a, 0x41, 0x4e, 0x59,
-	0x5f, 0x50, 0x45, 0x52, 0x53, 0x4f, 0x4e, 0x10, 0x03, 0x2a, 0x36, 0x0a, 0x0a, 0x53, 0x75, 0x72,
-	0x76, 0x65, 0x79, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1b, 0x0a, 0x17, 0x53, 0x55, 0x52, 0x56, 0x45,
-	0x59, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49,
-	0x45, 0x44, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x42, 0x4f, 0x4f, 0x4c, 0x45, 0x41, 0x4e, 0x10,
-	0x01, 0x42, 0x08, 0x5a, 0x06, 0x2e, 0x2f, 0x71, 0x72, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x33,
+	0x64, 0x88, 0x01, 0x01, 0x12, 0x28, 0x0a, 0x0d, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e,
+	0x5f, 0x74, 0x65, 0x78, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x0c, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x65, 0x78, 0x74, 0x88, 0x01, 0x01, 0x12, 0x29,
+	0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x10, 0x2e, 0x71,
+	0x72, 0x70, 0x62, 0x2e, 0x53, 0x75, 0x72, 0x76, 0x65, 0x79, 0x54, 0x79, 0x70, 0x65, 0x48, 0x02,
+	0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x88, 0x01, 0x01, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x74, 0x65, 0x78, 0x74, 0x42, 0x07, 0x0a, 0x05, 0x5f,
+	0x74, 0x79, 0x70, 0x65, 0x22, 0x6e, 0x0a, 0x0c, 0x53, 0x75, 0x72, 0x76, 0x65, 0x79, 0x41, 0x6e,
+	0x73, 0x77, 0x65, 0x72, 0x12, 0x24, 0x0a, 0x0b, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e,
+	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x0a, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x88, 0x01, 0x01, 0x12, 0x1c, 0x0a, 0x07, 0x69, 0x73,
+	0x5f, 0x74, 0x72, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x48, 0x01, 0x52, 0x06, 0x69,
+	0x73, 0x54, 0x72, 0x75, 0x65, 0x88, 0x01, 0x01, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x69, 0x73, 0x5f,
+	0x74, 0x72, 0x75, 0x65, 0x22, 0x4c, 0x0a, 0x09, 0x53, 0x75, 0x72, 0x76, 0x65, 0x79, 0x53, 0x65,
+	0x74, 0x12, 0x3f, 0x0a, 0x10, 0x73, 0x75, 0x72, 0x76, 0x65, 0x79, 0x5f, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x71, 0x72,
+	0x70, 0x62, 0x2e, 0x53, 0x75, 0x72, 0x76, 0x65, 0x79, 0x51, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x0f, 0x73, 0x75, 0x72, 0x76, 0x65, 0x79, 0x51, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x22, 0x8c, 0x08, 0x0a, 0x0a, 0x47, 0x61, 0x6d, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x12, 0x20, 0x0a, 0x09, 0x67, 0x61, 0x6d, 0x65, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x08, 0x67, 0x61, 0x6d, 0x65, 0x4d, 0x6f, 0x64, 0x65,
+	0x88, 0x01, 0x01, 0x12, 0x2c, 0x0a, 0x05, 0x62, 0x69, 0x6e, 0x67, 0x6f, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x11, 0x2e, 0x71, 0x72, 0x70, 0x62, 0x2e, 0x42, 0x69, 0x6e, 0x67, 0x6f, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x48, 0x01, 0x52, 0x05, 0x62, 0x69, 0x6e, 0x67, 0x6f, 0x88, 0x01,
+	0x01, 0x12, 0x35, 0x0a, 0x08, 0x61, 0x73, 0x73, 0x61, 0x73, 0x73, 0x69, 0x6e, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x71, 0x72, 0x70, 0x62, 0x2e, 0x41, 0x73, 0x73, 0x61, 0x73,
+	0x73, 0x69, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x48, 0x02, 0x52, 0x08, 0x61, 0x73, 0x73,
+	0x61, 0x73, 0x73, 0x69, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x2c, 0x0a, 0x05, 0x70, 0x6f, 0x6b, 0x65,
+	0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x71, 0x72, 0x70, 0x62, 0x2e, 0x50,
+	0x6f, 0x6b, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x48, 0x03, 0x52, 0x05, 0x70, 0x6f,
+	0x6b, 0x65, 0x72, 0x88, 0x01, 0x01, 0x12, 0x48, 0x0a, 0x11, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69,
+	0x6f, 0x6e, 0x5f, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x16, 0x2e, 0x71, 0x72, 0x70, 0x62, 0x2e, 0x51, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f,
+	0x6e, 0x44, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x73, 0x48, 0x04, 0x52, 0x10, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x69, 0x6f, 0x6e, 0x44, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x73, 0x88, 0x01, 0x01,
+	0x12, 0x36, 0x0a, 0x09, 0x61, 0x75, 0x74, 0x6f, 0x5f, 0x68, 0x69, 0x6e, 0x74, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x71, 0x72, 0x70, 0x62, 0x2e, 0x41, 0x75, 0x74, 0x6f, 0x48,
+	0x69, 0x6e, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x48, 0x05, 0x52, 0x08, 0x61, 0x75, 0x74,
+	0x6f, 0x48, 0x69, 0x6e, 0x74, 0x88, 0x01, 0x01, 0x12, 0x28, 0x0a, 0x0d, 0x73, 0x74, 0x61, 0x72,
+	0x74, 0x69, 0x6e, 0x67, 0x5f, 0x6c, 0x69, 0x66, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x48,
+	0x06, 0x52, 0x0c, 0x73, 0x74, 0x61, 0x72, 0x74, 0x69, 0x6e, 0x67, 0x4c, 0x69, 0x66, 0x65, 0x88,
+	0x01, 0x01, 0x12, 0x2a, 0x0a, 0x0e, 0x73, 0x74, 0x61, 0x72, 0x74, 0x69, 0x6e, 0x67, 0x5f, 0x6c,
+	0x65, 0x76, 0x65, 0x6c, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x48, 0x07, 0x52, 0x0d, 0x73, 0x74,
+	0x61, 0x72, 0x74, 0x69, 0x6e, 0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x37,
+	0x0a, 0x15, 0x63, 0x6f, 0x6f, 0x6b, 0x69, 0x65, 0x5f, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x69, 0x74,
+	0x79, 0x5f, 0x68, 0x6f, 0x75, 0x72, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x48, 0x08, 0x52,
+	0x13, 0x63, 0x6f, 0x6f, 0x6b, 0x69, 0x65, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x69, 0x74, 0x79, 0x48,
+	0x6f, 0x75, 0x72, 0x73, 0x88, 0x01, 0x01, 0x12, 0x27, 0x0a, 0x0d, 0x63, 0x61, 0x63, 0x68, 0x65,
+	0x5f, 0x74, 0x74, 0x6c, 0x5f, 0x73, 0x65, 0x63, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x03, 0x48, 0x09,
+	0x52, 0x0b, 0x63, 0x61, 0x63, 0x68, 0x65, 0x54, 0x74, 0x6c, 0x53, 0x65, 0x63, 0x88, 0x01, 0x01,
+	0x12, 0x22, 0x0a, 0x0a, 0x6d, 0x61, 0x78, 0x5f, 0x68, 0x65, 0x61, 0x72, 0x74, 0x73, 0x18, 0x0b,
+	0x20, 0x01, 0x28, 0x03, 0x48, 0x0a, 0x52, 0x09, 0x6d, 0x61, 0x78, 0x48, 0x65, 0x61, 0x72, 0x74,
+	0x73, 0x88, 0x01, 0x01, 0x12, 0x2a, 0x0a, 0x0e, 0x66, 0x6c, 0x61, 0x67, 0x5f, 0x68, 0x61, 0x6e,
+	0x64, 0x69, 0x63, 0x61, 0x70, 0x73, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x08, 0x48, 0x0b, 0x52, 0x0d,
+	0x66, 0x6c, 0x61, 0x67, 0x48, 0x61, 0x6e, 0x64, 0x69, 0x63, 0x61, 0x70, 0x73, 0x88, 0x01, 0x01,
+	0x12, 0x36, 0x0a, 0x0b, 0x62, 0x61, 0x64, 0x67, 0x65, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x73, 0x18,
+	0x0d, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x10, 0x2e, 0x71, 0x72, 0x70, 0x62, 0x2e, 0x42, 0x61, 0x64,
+	0x67, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x73, 0x48, 0x0c, 0x52, 0x0a, 0x62, 0x61, 0x64, 0x67, 0x65,
+	0x43, 0x6f, 0x64, 0x65, 0x73, 0x88, 0x01, 0x01, 0x12, 0x3c, 0x0a, 0x18, 0x72, 0x6f, 0x74, 0x61,
+	0x74, 0x69, 0x6e, 0x67, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x5f, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64,
+	0x5f, 0x73, 0x65, 0x63, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x03, 0x48, 0x0d, 0x52, 0x15, 0x72, 0x6f,
+	0x74, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x43, 0x6f, 0x64, 0x65, 0x50, 0x65, 0x72, 0x69, 0x6f, 0x64,
+	0x53, 0x65, 0x63, 0x88, 0x01, 0x01, 0x12, 0x42, 0x0a, 0x0f, 0x73, 0x75, 0x72, 0x76, 0x65, 0x79,
+	0x5f, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x14, 0x2e, 0x71, 0x72, 0x70, 0x62, 0x2e, 0x53, 0x75, 0x72, 0x76, 0x65, 0x79, 0x43, 0x6f, 0x76,
+	0x65, 0x72, 0x61, 0x67, 0x65, 0x48, 0x0e, 0x52, 0x0e, 0x73, 0x75, 0x72, 0x76, 0x65, 0x79, 0x43,
+	0x6f, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x88, 0x01, 0x01, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x67,
+	0x61, 0x6d, 0x65, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x62, 0x69, 0x6e,
+	0x67, 0x6f, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x61, 0x73, 0x73, 0x61, 0x73, 0x73, 0x69, 0x6e, 0x42,
+	0x08, 0x0a, 0x06, 0x5f, 0x70, 0x6f, 0x6b, 0x65, 0x72, 0x42, 0x14, 0x0a, 0x12, 0x5f, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x73, 0x42,
+	0x0c, 0x0a, 0x0a, 0x5f, 0x61, 0x75, 0x74, 0x6f, 0x5f, 0x68, 0x69, 0x6e, 0x74, 0x42, 0x10, 0x0a,
+	0x0e, 0x5f, 0x73, 0x74, 0x61, 0x72, 0x74, 0x69, 0x6e, 0x67, 0x5f, 0x6c, 0x69, 0x66, 0x65, 0x42,
+	0x11, 0x0a, 0x0f, 0x5f, 0x73, 0x74, 0x61, 0x72, 0x74, 0x69, 0x6e, 0x67, 0x5f, 0x6c, 0x65, 0x76,
+	0x65, 0x6c, 0x42, 0x18, 0x0a, 0x16, 0x5f, 0x63, 0x6f, 0x6f, 0x6b, 0x69, 0x65, 0x5f, 0x76, 0x61,
+	0x6c, 0x69, 0x64, 0x69, 0x74, 0x79, 0x5f, 0x68, 0x6f, 0x75, 0x72, 0x73, 0x42, 0x10, 0x0a, 0x0e,
+	0x5f, 0x63, 0x61, 0x63, 0x68, 0x65, 0x5f, 0x74, 0x74, 0x6c, 0x5f, 0x73, 0x65, 0x63, 0x42, 0x0d,
+	0x0a, 0x0b, 0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x68, 0x65, 0x61, 0x72, 0x74, 0x73, 0x42, 0x11, 0x0a,
+	0x0f, 0x5f, 0x66, 0x6c, 0x61, 0x67, 0x5f, 0x68, 0x61, 0x6e, 0x64, 0x69, 0x63, 0x61, 0x70, 0x73,
+	0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x62, 0x61, 0x64, 0x67, 0x65, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x73,
+	0x42, 0x1b, 0x0a, 0x19, 0x5f, 0x72, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x5f, 0x63, 0x6f,
+	0x64, 0x65, 0x5f, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x5f, 0x73, 0x65, 0x63, 0x42, 0x12, 0x0a,
+	0x10, 0x5f, 0x73, 0x75, 0x72, 0x76, 0x65, 0x79, 0x5f, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x61, 0x67,
+	0x65, 0x22, 0xbb, 0x01, 0x0a, 0x0e, 0x53, 0x75, 0x72, 0x76, 0x65, 0x79, 0x43, 0x6f, 0x76, 0x65,
+	0x72, 0x61, 0x67, 0x65, 0x12, 0x24, 0x0a, 0x0b, 0x6d, 0x69, 0x6e, 0x5f, 0x70, 0x6c, 0x61, 0x79,
+	0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x0a, 0x6d, 0x69, 0x6e,
+	0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x73, 0x88, 0x01, 0x01, 0x12, 0x24, 0x0a, 0x0b, 0x61, 0x75,
+	0x74, 0x6f, 0x5f, 0x62, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x48,
+	0x01, 0x52, 0x0a, 0x61, 0x75, 0x74, 0x6f, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x88, 0x01, 0x01,
+	0x12, 0x2a, 0x0a, 0x0e, 0x6d, 0x69, 0x6e, 0x5f, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72,
+	0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x48, 0x02, 0x52, 0x0d, 0x6d, 0x69, 0x6e, 0x52,
+	0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x65, 0x64, 0x88, 0x01, 0x01, 0x42, 0x0e, 0x0a, 0x0c,
+	0x5f, 0x6d, 0x69, 0x6e, 0x5f, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x73, 0x42, 0x0e, 0x0a, 0x0c,
+	0x5f, 0x61, 0x75, 0x74, 0x6f, 0x5f, 0x62, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x42, 0x11, 0x0a, 0x0f,
+	0x5f, 0x6d, 0x69, 0x6e, 0x5f, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x65, 0x64, 0x22,
+	0x93, 0x01, 0x0a, 0x0e, 0x41, 0x75, 0x74, 0x6f, 0x48, 0x69, 0x6e, 0x74, 0x43, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x12, 0x28, 0x0a, 0x0d, 0x61, 0x66, 0x74, 0x65, 0x72, 0x5f, 0x73, 0x65, 0x63, 0x6f,
+	0x6e, 0x64, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x0c, 0x61, 0x66, 0x74,
+	0x65, 0x72, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x88, 0x01, 0x01, 0x12, 0x2f, 0x0a, 0x11,
+	0x61, 0x66, 0x74, 0x65, 0x72, 0x5f, 0x77, 0x72, 0x6f, 0x6e, 0x67, 0x5f, 0x73, 0x63, 0x61, 0x6e,
+	0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x48, 0x01, 0x52, 0x0f, 0x61, 0x66, 0x74, 0x65, 0x72,
+	0x57, 0x72, 0x6f, 0x6e, 0x67, 0x53, 0x63, 0x61, 0x6e, 0x73, 0x88, 0x01, 0x01, 0x42, 0x10, 0x0a,
+	0x0e, 0x5f, 0x61, 0x66, 0x74, 0x65, 0x72, 0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x42,
+	0x14, 0x0a, 0x12, 0x5f, 0x61, 0x66, 0x74, 0x65, 0x72, 0x5f, 0x77, 0x72, 0x6f, 0x6e, 0x67, 0x5f,
+	0x73, 0x63, 0x61, 0x6e, 0x73, 0x22, 0x6d, 0x0a, 0x0b, 0x50, 0x6f, 0x6b, 0x65, 0x72, 0x43, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x12, 0x1e, 0x0a, 0x08, 0x65, 0x6e, 0x64, 0x5f, 0x74, 0x69, 0x6d, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x07, 0x65, 0x6e, 0x64, 0x54, 0x69, 0x6d,
+	0x65, 0x88, 0x01, 0x01, 0x12, 0x22, 0x0a, 0x0a, 0x61, 0x66, 0x74, 0x65, 0x72, 0x5f, 0x68, 0x75,
+	0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x48, 0x01, 0x52, 0x09, 0x61, 0x66, 0x74, 0x65,
+	0x72, 0x48, 0x75, 0x6e, 0x74, 0x88, 0x01, 0x01, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x65, 0x6e, 0x64,
+	0x5f, 0x74, 0x69, 0x6d, 0x65, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x61, 0x66, 0x74, 0x65, 0x72, 0x5f,
+	0x68, 0x75, 0x6e, 0x74, 0x22, 0xa8, 0x01, 0x0a, 0x0b, 0x42, 0x69, 0x6e, 0x67, 0x6f, 0x43, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x12, 0x20, 0x0a, 0x09, 0x63, 0x61, 0x72, 0x64, 0x5f, 0x73, 0x69, 0x7a,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x08, 0x63, 0x61, 0x72, 0x64, 0x53,
+	0x69, 0x7a, 0x65, 0x88, 0x01, 0x01, 0x12, 0x2b, 0x0a, 0x0f, 0x62, 0x6c, 0x61, 0x63, 0x6b, 0x6f,
+	0x75, 0x74, 0x5f, 0x74, 0x6f, 0x5f, 0x77, 0x69, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x48,
+	0x01, 0x52, 0x0d, 0x62, 0x6c, 0x61, 0x63, 0x6b, 0x6f, 0x75, 0x74, 0x54, 0x6f, 0x57, 0x69, 0x6e,
+	0x88, 0x01, 0x01, 0x12, 0x28, 0x0a, 0x06, 0x74, 0x72, 0x61, 0x69, 0x74, 0x73, 0x18, 0x03, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x71, 0x72, 0x70, 0x62, 0x2e, 0x42, 0x69, 0x6e, 0x67, 0x6f,
+	0x54, 0x72, 0x61, 0x69, 0x74, 0x52, 0x06, 0x74, 0x72, 0x61, 0x69, 0x74, 0x73, 0x42, 0x0c, 0x0a,
+	0x0a, 0x5f, 0x63, 0x61, 0x72, 0x64, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x42, 0x12, 0x0a, 0x10, 0x5f,
+	0x62, 0x6c, 0x61, 0x63, 0x6b, 0x6f, 0x75, 0x74, 0x5f, 0x74, 0x6f, 0x5f, 0x77, 0x69, 0x6e, 0x22,
+	0xc1, 0x02, 0x0a, 0x0a, 0x42, 0x69, 0x6e, 0x67, 0x6f, 0x54, 0x72, 0x61, 0x69, 0x74, 0x12, 0x17,
+	0x0a, 0x04, 0x74, 0x65, 0x78, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x04,
+	0x74, 0x65, 0x78, 0x74, 0x88, 0x01, 0x01, 0x12, 0x1c, 0x0a, 0x09, 0x75, 0x73, 0x65, 0x72, 0x6e,
+	0x61, 0x6d, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x75, 0x73, 0x65, 0x72,
+	0x6e, 0x61, 0x6d, 0x65, 0x73, 0x12, 0x20, 0x0a, 0x09, 0x73, 0x75, 0x72, 0x76, 0x65, 0x79, 0x5f,
+	0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x48, 0x01, 0x52, 0x08, 0x73, 0x75, 0x72, 0x76,
+	0x65, 0x79, 0x49, 0x64, 0x88, 0x01, 0x01, 0x12, 0x38, 0x0a, 0x16, 0x73, 0x75, 0x72, 0x76, 0x65,
+	0x79, 0x5f, 0x74, 0x72, 0x75, 0x65, 0x5f, 0x69, 0x73, 0x5f, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x63,
+	0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x48, 0x02, 0x52, 0x13, 0x73, 0x75, 0x72, 0x76, 0x65,
+	0x79, 0x54, 0x72, 0x75, 0x65, 0x49, 0x73, 0x43, 0x6f, 0x72, 0x72, 0x65, 0x63, 0x74, 0x88, 0x01,
+	0x01, 0x12, 0x30, 0x0a, 0x09, 0x63, 0x61, 0x72, 0x64, 0x5f, 0x73, 0x75, 0x69, 0x74, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x0e, 0x2e, 0x71, 0x72, 0x70, 0x62, 0x2e, 0x43, 0x61, 0x72, 0x64,
+	0x53, 0x75, 0x69, 0x74, 0x48, 0x03, 0x52, 0x08, 0x63, 0x61, 0x72, 0x64, 0x53, 0x75, 0x69, 0x74,
+	0x88, 0x01, 0x01, 0x12, 0x20, 0x0a, 0x09, 0x63, 0x61, 0x72, 0x64, 0x5f, 0x72, 0x61, 0x6e, 0x6b,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x48, 0x04, 0x52, 0x08, 0x63, 0x61, 0x72, 0x64, 0x52, 0x61,
+	0x6e, 0x6b, 0x88, 0x01, 0x01, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x74, 0x65, 0x78, 0x74, 0x42, 0x0c,
+	0x0a, 0x0a, 0x5f, 0x73, 0x75, 0x72, 0x76, 0x65, 0x79, 0x5f, 0x69, 0x64, 0x42, 0x19, 0x0a, 0x17,
+	0x5f, 0x73, 0x75, 0x72, 0x76, 0x65, 0x79, 0x5f, 0x74, 0x72, 0x75, 0x65, 0x5f, 0x69, 0x73, 0x5f,
+	0x63, 0x6f, 0x72, 0x72, 0x65, 0x63, 0x74, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x63, 0x61, 0x72, 0x64,
+	0x5f, 0x73, 0x75, 0x69, 0x74, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x63, 0x61, 0x72, 0x64, 0x5f, 0x72,
+	0x61, 0x6e, 0x6b, 0x22, 0x72, 0x0a, 0x09, 0x42, 0x69, 0x6e, 0x67, 0x6f, 0x43, 0x65, 0x6c, 0x6c,
+	0x12, 0x2b, 0x0a, 0x05, 0x74, 0x72, 0x61, 0x69, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x10, 0x2e, 0x71, 0x72, 0x70, 0x62, 0x2e, 0x42, 0x69, 0x6e, 0x67, 0x6f, 0x54, 0x72, 0x61, 0x69,
+	0x74, 0x48, 0x00, 0x52, 0x05, 0x74, 0x72, 0x61, 0x69, 0x74, 0x88, 0x01, 0x01, 0x12, 0x20, 0x0a,
+	0x09, 0x66, 0x69, 0x6c, 0x6c, 0x65, 0x64, 0x5f, 0x62, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x48, 0x01, 0x52, 0x08, 0x66, 0x69, 0x6c, 0x6c, 0x65, 0x64, 0x42, 0x79, 0x88, 0x01, 0x01, 0x42,
+	0x08, 0x0a, 0x06, 0x5f, 0x74, 0x72, 0x61, 0x69, 0x74, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x66, 0x69,
+	0x6c, 0x6c, 0x65, 0x64, 0x5f, 0x62, 0x79, 0x22, 0x8c, 0x01, 0x0a, 0x0e, 0x41, 0x73, 0x73, 0x61,
+	0x73, 0x73, 0x69, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x1e, 0x0a, 0x08, 0x65, 0x6e,
+	0x64, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x07,
+	0x65, 0x6e, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x12, 0x34, 0x0a, 0x14, 0x77, 0x72,
+	0x6f, 0x6e, 0x67, 0x5f, 0x74, 0x61, 0x67, 0x5f, 0x63, 0x6f, 0x73, 0x74, 0x73, 0x5f, 0x6c, 0x69,
+	0x66, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x48, 0x01, 0x52, 0x11, 0x77, 0x72, 0x6f, 0x6e,
+	0x67, 0x54, 0x61, 0x67, 0x43, 0x6f, 0x73, 0x74, 0x73, 0x4c, 0x69, 0x66, 0x65, 0x88, 0x01, 0x01,
+	0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x65, 0x6e, 0x64, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x42, 0x17, 0x0a,
+	0x15, 0x5f, 0x77, 0x72, 0x6f, 0x6e, 0x67, 0x5f, 0x74, 0x61, 0x67, 0x5f, 0x63, 0x6f, 0x73, 0x74,
+	0x73, 0x5f, 0x6c, 0x69, 0x66, 0x65, 0x2a, 0x56, 0x0a, 0x08, 0x43, 0x61, 0x72, 0x64, 0x53, 0x75,
+	0x69, 0x74, 0x12, 0x19, 0x0a, 0x15, 0x43, 0x41, 0x52, 0x44, 0x5f, 0x53, 0x55, 0x49, 0x54, 0x5f,
+	0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x0a, 0x0a,
+	0x06, 0x53, 0x50, 0x41, 0x44, 0x45, 0x53, 0x10, 0x01, 0x12, 0x0a, 0x0a, 0x06, 0x48, 0x45, 0x41,
+	0x52, 0x54, 0x53, 0x10, 0x02, 0x12, 0x09, 0x0a, 0x05, 0x43, 0x4c, 0x55, 0x42, 0x53, 0x10, 0x03,
+	0x12, 0x0c, 0x0a, 0x08, 0x44, 0x49, 0x41, 0x4d, 0x4f, 0x4e, 0x44, 0x53, 0x10, 0x04, 0x2a, 0x74,
+	0x0a, 0x0f, 0x47, 0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x4b, 0x69, 0x6e,
+	0x64, 0x12, 0x1c, 0x0a, 0x18, 0x47, 0x4c, 0x4f, 0x42, 0x41, 0x4c, 0x5f, 0x45, 0x56, 0x45, 0x4e,
+	0x54, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12,
+	0x17, 0x0a, 0x13, 0x44, 0x4f, 0x55, 0x42, 0x4c, 0x45, 0x5f, 0x54, 0x4f, 0x4b, 0x45, 0x4e, 0x5f,
+	0x43, 0x48, 0x41, 0x4e, 0x43, 0x45, 0x10, 0x01, 0x12, 0x14, 0x0a, 0x10, 0x46, 0x52, 0x45, 0x45,
+	0x5f, 0x57, 0x52, 0x4f, 0x4e, 0x47, 0x5f, 0x53, 0x43, 0x41, 0x4e, 0x53, 0x10, 0x02, 0x12, 0x14,
+	0x0a, 0x10, 0x4c, 0x49, 0x46, 0x45, 0x5f, 0x50, 0x45, 0x52, 0x5f, 0x43, 0x4f, 0x52, 0x52, 0x45,
+	0x43, 0x54, 0x10, 0x03, 0x2a, 0x9d, 0x01, 0x0a, 0x06, 0x47, 0x51, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x16, 0x0a, 0x12, 0x47, 0x51, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43,
+	0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x11, 0x0a, 0x0d, 0x55, 0x53, 0x45, 0x52, 0x4e,
+	0x41, 0x4d, 0x45, 0x5f, 0x4c, 0x49, 0x53, 0x54, 0x10, 0x01, 0x12, 0x0e, 0x0a, 0x0a, 0x53, 0x55,
+	0x52, 0x56, 0x45, 0x59, 0x5f, 0x41, 0x4e, 0x53, 0x10, 0x02, 0x12, 0x0e, 0x0a, 0x0a, 0x41, 0x4e,
+	0x59, 0x5f, 0x50, 0x45, 0x52, 0x53, 0x4f, 0x4e, 0x10, 0x03, 0x12, 0x0f, 0x0a, 0x0b, 0x54, 0x45,
+	0x58, 0x54, 0x5f, 0x41, 0x4e, 0x53, 0x57, 0x45, 0x52, 0x10, 0x04, 0x12, 0x0f, 0x0a, 0x0b, 0x50,
+	0x48, 0x4f, 0x54, 0x4f, 0x5f, 0x50, 0x52, 0x4f, 0x4f, 0x46, 0x10, 0x05, 0x12, 0x14, 0x0a, 0x10,
+	0x43, 0x48, 0x45, 0x43, 0x4b, 0x50, 0x4f, 0x49, 0x4e, 0x54, 0x5f, 0x52, 0x4f, 0x55, 0x54, 0x45,
+	0x10, 0x06, 0x12, 0x10, 0x0a, 0x0c, 0x53, 0x45, 0x43, 0x52, 0x45, 0x54, 0x5f, 0x42, 0x55, 0x44,
+	0x44, 0x59, 0x10, 0x07, 0x2a, 0x66, 0x0a, 0x0b, 0x50, 0x68, 0x6f, 0x74, 0x6f, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x12, 0x1c, 0x0a, 0x18, 0x50, 0x48, 0x4f, 0x54, 0x4f, 0x5f, 0x53, 0x54, 0x41,
+	0x54, 0x55, 0x53, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10,
+	0x00, 0x12, 0x11, 0x0a, 0x0d, 0x50, 0x48, 0x4f, 0x54, 0x4f, 0x5f, 0x50, 0x45, 0x4e, 0x44, 0x49,
+	0x4e, 0x47, 0x10, 0x01, 0x12, 0x12, 0x0a, 0x0e, 0x50, 0x48, 0x4f, 0x54, 0x4f, 0x5f, 0x41, 0x50,
+	0x50, 0x52, 0x4f, 0x56, 0x45, 0x44, 0x10, 0x02, 0x12, 0x12, 0x0a, 0x0e, 0x50, 0x48, 0x4f, 0x54,
+	0x4f, 0x5f, 0x52, 0x45, 0x4a, 0x45, 0x43, 0x54, 0x45, 0x44, 0x10, 0x03, 0x2a, 0x36, 0x0a, 0x0a,
+	0x53, 0x75, 0x72, 0x76, 0x65, 0x79, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1b, 0x0a, 0x17, 0x53, 0x55,
+	0x52, 0x56, 0x45, 0x59, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43,
+	0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x42, 0x4f, 0x4f, 0x4c, 0x45,
+	0x41, 0x4e, 0x10, 0x01, 0x2a, 0x67, 0x0a, 0x0a, 0x42, 0x61, 0x64, 0x67, 0x65, 0x43, 0x6f, 0x64,
+	0x65, 0x73, 0x12, 0x1b, 0x0a, 0x17, 0x42, 0x41, 0x44, 0x47, 0x45, 0x5f, 0x43, 0x4f, 0x44, 0x45,
+	0x53, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12,
+	0x10, 0x0a, 0x0c, 0x50, 0x52, 0x49, 0x4e, 0x54, 0x45, 0x44, 0x5f, 0x4f, 0x4e, 0x4c, 0x59, 0x10,
+	0x01, 0x12, 0x11, 0x0a, 0x0d, 0x52, 0x4f, 0x54, 0x41, 0x54, 0x49, 0x4e, 0x47, 0x5f, 0x4f, 0x4e,
+	0x4c, 0x59, 0x10, 0x02, 0x12, 0x17, 0x0a, 0x13, 0x50, 0x52, 0x49, 0x4e, 0x54, 0x45, 0x44, 0x5f,
+	0x4f, 0x52, 0x5f, 0x52, 0x4f, 0x54, 0x41, 0x54, 0x49, 0x4e, 0x47, 0x10, 0x03, 0x2a, 0x70, 0x0a,
+	0x0e, 0x41, 0x73, 0x73, 0x61, 0x73, 0x73, 0x69, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12,
+	0x1f, 0x0a, 0x1b, 0x41, 0x53, 0x53, 0x41, 0x53, 0x53, 0x49, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54,
+	0x55, 0x53, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00,
+	0x12, 0x12, 0x0a, 0x0e, 0x41, 0x53, 0x53, 0x41, 0x53, 0x53, 0x49, 0x4e, 0x5f, 0x41, 0x4c, 0x49,
+	0x56, 0x45, 0x10, 0x01, 0x12, 0x13, 0x0a, 0x0f, 0x41, 0x53, 0x53, 0x41, 0x53, 0x53, 0x49, 0x4e,
+	0x5f, 0x54, 0x41, 0x47, 0x47, 0x45, 0x44, 0x10, 0x02, 0x12, 0x14, 0x0a, 0x10, 0x41, 0x53, 0x53,
+	0x41, 0x53, 0x53, 0x49, 0x4e, 0x5f, 0x44, 0x52, 0x4f, 0x50, 0x50, 0x45, 0x44, 0x10, 0x03, 0x42,
+	0x08, 0x5a, 0x06, 0x2e, 0x2f, 0x71, 0x72, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
 }
 
 var (
@@ -1133,41 +3326,83 @@ func file_gamedata_proto_rawDescGZIP() []byte {
 	return file_gamedata_proto_rawDescData
 }
 
-var file_gamedata_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
-var file_gamedata_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_gamedata_proto_enumTypes = make([]protoimpl.EnumInfo, 9)
+var file_gamedata_proto_msgTypes = make([]protoimpl.MessageInfo, 26)
 var file_gamedata_proto_goTypes = []interface{}{
 	(CardSuit)(0),               // 0: qrpb.CardSuit
-	(GQType)(0),                 // 1: qrpb.GQType
-	(SurveyType)(0),             // 2: qrpb.SurveyType
-	(ActionLog_ActionType)(0),   // 3: qrpb.ActionLog.ActionType
-	(ActionLog_ActionResult)(0), // 4: qrpb.ActionLog.ActionResult
-	(*GUser)(nil),               // 5: qrpb.GUser
-	(*QRMapping)(nil),           // 6: qrpb.QRMapping
-	(*QRMappingSet)(nil),        // 7: qrpb.QRMappingSet
-	(*GameState)(nil),           // 8: qrpb.GameState
-	(*ActionLog)(nil),           // 9: qrpb.ActionLog
-	(*GameQuestion)(nil),        // 10: qrpb.GameQuestion
-	(*GameQSet)(nil),            // 11: qrpb.GameQSet
-	(*SurveyQuestion)(nil),      // 12: qrpb.SurveyQuestion
-	(*SurveyAnswer)(nil),        // 13: qrpb.SurveyAnswer
-	(*SurveySet)(nil),           // 14: qrpb.SurveySet
+	(GlobalEventKind)(0),        // 1: qrpb.GlobalEventKind
+	(GQType)(0),                 // 2: qrpb.GQType
+	(PhotoStatus)(0),            // 3: qrpb.PhotoStatus
+	(SurveyType)(0),             // 4: qrpb.SurveyType
+	(BadgeCodes)(0),             // 5: qrpb.BadgeCodes
+	(AssassinStatus)(0),         // 6: qrpb.AssassinStatus
+	(ActionLog_ActionType)(0),   // 7: qrpb.ActionLog.ActionType
+	(ActionLog_ActionResult)(0), // 8: qrpb.ActionLog.ActionResult
+	(*GUser)(nil),               // 9: qrpb.GUser
+	(*QRMapping)(nil),           // 10: qrpb.QRMapping
+	(*PlayerHandicap)(nil),      // 11: qrpb.PlayerHandicap
+	(*QRMappingSet)(nil),        // 12: qrpb.QRMappingSet
+	(*GameState)(nil),           // 13: qrpb.GameState
+	(*VariantAssignment)(nil),   // 14: qrpb.VariantAssignment
+	(*PokerCard)(nil),           // 15: qrpb.PokerCard
+	(*BuddyAssignment)(nil),     // 16: qrpb.BuddyAssignment
+	(*ActionLog)(nil),           // 17: qrpb.ActionLog
+	(*GlobalEvent)(nil),         // 18: qrpb.GlobalEvent
+	(*GlobalEventSet)(nil),      // 19: qrpb.GlobalEventSet
+	(*GameQuestion)(nil),        // 20: qrpb.GameQuestion
+	(*QuestionReward)(nil),      // 21: qrpb.QuestionReward
+	(*QuestionDefaults)(nil),    // 22: qrpb.QuestionDefaults
+	(*GameQSet)(nil),            // 23: qrpb.GameQSet
+	(*SurveyQuestion)(nil),      // 24: qrpb.SurveyQuestion
+	(*SurveyAnswer)(nil),        // 25: qrpb.SurveyAnswer
+	(*SurveySet)(nil),           // 26: qrpb.SurveySet
+	(*GameConfig)(nil),          // 27: qrpb.GameConfig
+	(*SurveyCoverage)(nil),      // 28: qrpb.SurveyCoverage
+	(*AutoHintConfig)(nil),      // 29: qrpb.AutoHintConfig
+	(*PokerConfig)(nil),         // 30: qrpb.PokerConfig
+	(*BingoConfig)(nil),         // 31: qrpb.BingoConfig
+	(*BingoTrait)(nil),          // 32: qrpb.BingoTrait
+	(*BingoCell)(nil),           // 33: qrpb.BingoCell
+	(*AssassinConfig)(nil),      // 34: qrpb.AssassinConfig
 }
 var file_gamedata_proto_depIdxs = []int32{
-	13, // 0: qrpb.GUser.survey_answers:type_name -> qrpb.SurveyAnswer
+	25, // 0: qrpb.GUser.survey_answers:type_name -> qrpb.SurveyAnswer
 	0,  // 1: qrpb.QRMapping.card_suit:type_name -> qrpb.CardSuit
-	6,  // 2: qrpb.QRMappingSet.qr_mappings:type_name -> qrpb.QRMapping
-	3,  // 3: qrpb.ActionLog.type:type_name -> qrpb.ActionLog.ActionType
-	8,  // 4: qrpb.ActionLog.old_state:type_name -> qrpb.GameState
-	4,  // 5: qrpb.ActionLog.result:type_name -> qrpb.ActionLog.ActionResult
-	1,  // 6: qrpb.GameQuestion.type:type_name -> qrpb.GQType
-	10, // 7: qrpb.GameQSet.game_questions:type_name -> qrpb.GameQuestion
-	2,  // 8: qrpb.SurveyQuestion.type:type_name -> qrpb.SurveyType
-	12, // 9: qrpb.SurveySet.survey_questions:type_name -> qrpb.SurveyQuestion
-	10, // [10:10] is the sub-list for method output_type
-	10, // [10:10] is the sub-list for method input_type
-	10, // [10:10] is the sub-list for extension type_name
-	10, // [10:10] is the sub-list for extension extendee
-	0,  // [0:10] is the sub-list for field type_name
+	11, // 2: qrpb.QRMapping.handicap:type_name -> qrpb.PlayerHandicap
+	10, // 3: qrpb.QRMappingSet.qr_mappings:type_name -> qrpb.QRMapping
+	33, // 4: qrpb.GameState.bingo_cells:type_name -> qrpb.BingoCell
+	16, // 5: qrpb.GameState.buddies:type_name -> qrpb.BuddyAssignment
+	15, // 6: qrpb.GameState.poker_hand:type_name -> qrpb.PokerCard
+	15, // 7: qrpb.GameState.poker_offer:type_name -> qrpb.PokerCard
+	14, // 8: qrpb.GameState.variants:type_name -> qrpb.VariantAssignment
+	0,  // 9: qrpb.PokerCard.suit:type_name -> qrpb.CardSuit
+	7,  // 10: qrpb.ActionLog.type:type_name -> qrpb.ActionLog.ActionType
+	13, // 11: qrpb.ActionLog.old_state:type_name -> qrpb.GameState
+	8,  // 12: qrpb.ActionLog.result:type_name -> qrpb.ActionLog.ActionResult
+	1,  // 13: qrpb.GlobalEvent.kind:type_name -> qrpb.GlobalEventKind
+	18, // 14: qrpb.GlobalEventSet.events:type_name -> qrpb.GlobalEvent
+	2,  // 15: qrpb.GameQuestion.type:type_name -> qrpb.GQType
+	21, // 16: qrpb.GameQuestion.reward:type_name -> qrpb.QuestionReward
+	20, // 17: qrpb.GameQuestion.backup:type_name -> qrpb.GameQuestion
+	21, // 18: qrpb.QuestionDefaults.reward:type_name -> qrpb.QuestionReward
+	20, // 19: qrpb.GameQSet.game_questions:type_name -> qrpb.GameQuestion
+	4,  // 20: qrpb.SurveyQuestion.type:type_name -> qrpb.SurveyType
+	24, // 21: qrpb.SurveySet.survey_questions:type_name -> qrpb.SurveyQuestion
+	31, // 22: qrpb.GameConfig.bingo:type_name -> qrpb.BingoConfig
+	34, // 23: qrpb.GameConfig.assassin:type_name -> qrpb.AssassinConfig
+	30, // 24: qrpb.GameConfig.poker:type_name -> qrpb.PokerConfig
+	22, // 25: qrpb.GameConfig.question_defaults:type_name -> qrpb.QuestionDefaults
+	29, // 26: qrpb.GameConfig.auto_hint:type_name -> qrpb.AutoHintConfig
+	5,  // 27: qrpb.GameConfig.badge_codes:type_name -> qrpb.BadgeCodes
+	28, // 28: qrpb.GameConfig.survey_coverage:type_name -> qrpb.SurveyCoverage
+	32, // 29: qrpb.BingoConfig.traits:type_name -> qrpb.BingoTrait
+	0,  // 30: qrpb.BingoTrait.card_suit:type_name -> qrpb.CardSuit
+	32, // 31: qrpb.BingoCell.trait:type_name -> qrpb.BingoTrait
+	32, // [32:32] is the sub-list for method output_type
+	32, // [32:32] is the sub-list for method input_type
+	32, // [32:32] is the sub-list for extension type_name
+	32, // [32:32] is the sub-list for extension extendee
+	0,  // [0:32] is the sub-list for field type_name
 }
 
 func init() { file_gamedata_proto_init() }
@@ -1201,7 +3436,7 @@ func file_gamedata_proto_init() {
 			}
 		}
 		file_gamedata_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*QRMappingSet); i {
+			switch v := v.(*PlayerHandicap); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1213,7 +3448,7 @@ func file_gamedata_proto_init() {
 			}
 		}
 		file_gamedata_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GameState); i {
+			switch v := v.(*QRMappingSet); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1225,7 +3460,7 @@ func file_gamedata_proto_init() {
 			}
 		}
 		file_gamedata_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ActionLog); i {
+			switch v := v.(*GameState); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1237,7 +3472,7 @@ func file_gamedata_proto_init() {
 			}
 		}
 		file_gamedata_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GameQuestion); i {
+			switch v := v.(*VariantAssignment); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1249,7 +3484,7 @@ func file_gamedata_proto_init() {
 			}
 		}
 		file_gamedata_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GameQSet); i {
+			switch v := v.(*PokerCard); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1261,7 +3496,7 @@ func file_gamedata_proto_init() {
 			}
 		}
 		file_gamedata_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SurveyQuestion); i {
+			switch v := v.(*BuddyAssignment); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1273,7 +3508,7 @@ func file_gamedata_proto_init() {
 			}
 		}
 		file_gamedata_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SurveyAnswer); i {
+			switch v := v.(*ActionLog); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1285,6 +3520,102 @@ func file_gamedata_proto_init() {
 			}
 		}
 		file_gamedata_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GlobalEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gamedata_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GlobalEventSet); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gamedata_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GameQuestion); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gamedata_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QuestionReward); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gamedata_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QuestionDefaults); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gamedata_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GameQSet); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gamedata_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SurveyQuestion); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gamedata_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SurveyAnswer); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gamedata_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*SurveySet); i {
 			case 0:
 				return &v.state
@@ -1296,21 +3627,133 @@ func file_gamedata_proto_init() {
 				return nil
 			}
 		}
+		file_gamedata_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GameConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gamedata_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SurveyCoverage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gamedata_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AutoHintConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gamedata_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PokerConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gamedata_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BingoConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gamedata_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BingoTrait); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gamedata_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BingoCell); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gamedata_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AssassinConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	file_gamedata_proto_msgTypes[0].OneofWrappers = []interface{}{}
 	file_gamedata_proto_msgTypes[1].OneofWrappers = []interface{}{}
-	file_gamedata_proto_msgTypes[3].OneofWrappers = []interface{}{}
+	file_gamedata_proto_msgTypes[2].OneofWrappers = []interface{}{}
 	file_gamedata_proto_msgTypes[4].OneofWrappers = []interface{}{}
 	file_gamedata_proto_msgTypes[5].OneofWrappers = []interface{}{}
+	file_gamedata_proto_msgTypes[6].OneofWrappers = []interface{}{}
 	file_gamedata_proto_msgTypes[7].OneofWrappers = []interface{}{}
 	file_gamedata_proto_msgTypes[8].OneofWrappers = []interface{}{}
+	file_gamedata_proto_msgTypes[9].OneofWrappers = []interface{}{}
+	file_gamedata_proto_msgTypes[11].OneofWrappers = []interface{}{}
+	file_gamedata_proto_msgTypes[12].OneofWrappers = []interface{}{}
+	file_gamedata_proto_msgTypes[13].OneofWrappers = []interface{}{}
+	file_gamedata_proto_msgTypes[14].OneofWrappers = []interface{}{}
+	file_gamedata_proto_msgTypes[15].OneofWrappers = []interface{}{}
+	file_gamedata_proto_msgTypes[16].OneofWrappers = []interface{}{}
+	file_gamedata_proto_msgTypes[18].OneofWrappers = []interface{}{}
+	file_gamedata_proto_msgTypes[19].OneofWrappers = []interface{}{}
+	file_gamedata_proto_msgTypes[20].OneofWrappers = []interface{}{}
+	file_gamedata_proto_msgTypes[21].OneofWrappers = []interface{}{}
+	file_gamedata_proto_msgTypes[22].OneofWrappers = []interface{}{}
+	file_gamedata_proto_msgTypes[23].OneofWrappers = []interface{}{}
+	file_gamedata_proto_msgTypes[24].OneofWrappers = []interface{}{}
+	file_gamedata_proto_msgTypes[25].OneofWrappers = []interface{}{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_gamedata_proto_rawDesc,
-			NumEnums:      5,
-			NumMessages:   10,
+			NumEnums:      9,
+			NumMessages:   26,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
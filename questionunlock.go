@@ -0,0 +1,68 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/sushovande/qr-mixer-game/qrpb"
+)
+
+// UNLOCK_TIME_FORMAT is how unlock times are shown to players, in the time zone the organizer wrote them in.
+const UNLOCK_TIME_FORMAT = "15:04"
+
+// QuestionLockedUntil returns the unlock time of the question, and true if it has not passed yet.
+func QuestionLockedUntil(sq *qrpb.GameQuestion, now time.Time) (time.Time, bool) {
+	if len(sq.GetUnlockTime()) == 0 {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339, sq.GetUnlockTime())
+	if err != nil {
+		log.Printf("could not parse unlock_time of question %v: %v", sq.GetQuestionId(), err)
+		return time.Time{}, false
+	}
+	return t, now.Before(t)
+}
+
+// StepLocked ignores the player's answer if their question is still locked, and returns true if it was.
+func StepLocked(result *StepResponse, sq *qrpb.GameQuestion, now time.Time) bool {
+	t, locked := QuestionLockedUntil(sq, now)
+	if !locked {
+		return false
+	}
+	result.actionString = fmt.Sprintf("Locked until %v!", t.Format(UNLOCK_TIME_FORMAT))
+	result.actionResult = *qrpb.ActionLog_RESULT_IGNORED.Enum()
+	return true
+}
+
+// LockedClueHTML is shown instead of a question that is still locked.
+func LockedClueHTML(t time.Time) string {
+	return fmt.Sprintf(`<div class="lockedclue"><p>🔒 This clue is locked until %v.</p><p>Take a break, and refresh this page then.</p></div>`,
+		t.Format(UNLOCK_TIME_FORMAT))
+}
+
+// CheckUnlockTime returns an error if the unlock time is set but cannot be parsed.
+// what names the question in the error message.
+func CheckUnlockTime(what string, unlockTime string) error {
+	if len(unlockTime) == 0 {
+		return nil
+	}
+	if _, err := time.Parse(time.RFC3339, unlockTime); err != nil {
+		return fmt.Errorf("%v: unlock_time must look like 2022-11-05T19:30:00+05:30: %v", what, err)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sushovande/qr-mixer-game/qrpb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestQuestionLockedUntil(t *testing.T) {
+	sq := &qrpb.GameQuestion{UnlockTime: proto.String("2022-11-05T19:30:00+05:30")}
+	at, locked := QuestionLockedUntil(sq, time.Date(2022, 11, 5, 13, 0, 0, 0, time.UTC))
+	if !locked || at.Format(UNLOCK_TIME_FORMAT) != "19:30" {
+		t.Errorf("expected the question to be locked until 19:30. got: %v %v", locked, at)
+	}
+	if _, locked := QuestionLockedUntil(sq, time.Date(2022, 11, 5, 14, 0, 0, 0, time.UTC)); locked {
+		t.Errorf("expected the question to be unlocked after 19:30 IST")
+	}
+	if _, locked := QuestionLockedUntil(&qrpb.GameQuestion{}, time.Now()); locked {
+		t.Errorf("expected a question without an unlock time to be unlocked")
+	}
+}
+
+func TestLockedQuestionInGame(t *testing.T) {
+	env, err := createEnv(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer env.db.Close()
+	setupSynthetic(env.cgo, 10)
+	sqs, _ := env.cgo.GetGameQSet()
+	sqs.GameQuestions[1].UnlockTime = proto.String(time.Now().Add(time.Hour).Format(time.RFC3339))
+	env.cgo.SetGameQSet(sqs)
+
+	u1 := GetSyntheticStateRow(1, 2)
+	mr, _ := env.Step(u1.State, "qrcode-9")
+	if !strings.HasPrefix(mr.actionString, "Locked until") || mr.newState.GetLife() != 3 || mr.newState.GetUserLevel() != 2 {
+		t.Errorf("expected the scan to be ignored. got: %v %v", mr.actionString, mr.newState)
+	}
+	mr, _ = env.Step(u1.State, "qrcode-2")
+	if mr.newState.GetUserLevel() != 2 {
+		t.Errorf("expected even the right answer to wait. got: %v %v", mr.actionString, mr.newState)
+	}
+	clue, _ := huntMode{}.Clue(env, u1)
+	if !strings.Contains(clue.HTML, "locked until") || strings.Contains(clue.HTML, sqs.GameQuestions[1].GetQuestionHtml()) {
+		t.Errorf("expected the locked placeholder. got: %v", clue.HTML)
+	}
+
+	sqs.GameQuestions[1].UnlockTime = proto.String(time.Now().Add(-time.Minute).Format(time.RFC3339))
+	env.cgo.SetGameQSet(sqs)
+	mr, _ = env.Step(u1.State, "qrcode-2")
+	if mr.actionString != "Correct!" {
+		t.Errorf("expected the question to unlock. got: %v", mr.actionString)
+	}
+
+	f := callController("POST", "/saveQuestions", "gameq="+`game_questions: { question_id: 1 type: ANY_PERSON unlock_time: "7:30pm" }`, nil, env.adminSaveQuestions)
+	if f.statuscode != http.StatusInternalServerError {
+		t.Errorf("expected a bad unlock time to be rejected. got: %v", f.resptext)
+	}
+}
@@ -0,0 +1,281 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/sushovande/qr-mixer-game/common"
+	"github.com/sushovande/qr-mixer-game/qrpb"
+
+	proto "google.golang.org/protobuf/proto"
+)
+
+// QuestionVariants returns every question written for level n, retired or not, in the order they
+// appear in the GameQSet.
+func QuestionVariants(sqs *qrpb.GameQSet, n int64) []*qrpb.GameQuestion {
+	vs := make([]*qrpb.GameQuestion, 0)
+	for _, q := range sqs.GetGameQuestions() {
+		if q.GetQuestionId() == n {
+			vs = append(vs, q)
+		}
+	}
+	return vs
+}
+
+// ActiveVariants returns the questions for level n that can still be given to players.
+func ActiveVariants(sqs *qrpb.GameQSet, n int64) []*qrpb.GameQuestion {
+	vs := make([]*qrpb.GameQuestion, 0)
+	for _, q := range QuestionVariants(sqs, n) {
+		if !q.GetRetired() {
+			vs = append(vs, q)
+		}
+	}
+	return vs
+}
+
+// VariantFor returns the variant of level n's question the player was given, if any.
+func VariantFor(gs *qrpb.GameState, n int64) (string, bool) {
+	for _, a := range gs.GetVariants() {
+		if a.GetQuestionId() == n {
+			return a.GetVariant(), true
+		}
+	}
+	return "", false
+}
+
+// SetVariant records that the player was given the named variant of level n's question.
+func SetVariant(gs *qrpb.GameState, n int64, variant string) {
+	for _, a := range gs.Variants {
+		if a.GetQuestionId() == n {
+			a.Variant = proto.String(variant)
+			return
+		}
+	}
+	gs.Variants = append(gs.Variants, &qrpb.VariantAssignment{
+		QuestionId: proto.Int64(n),
+		Variant:    proto.String(variant),
+	})
+}
+
+// PickVariant deterministically spreads players over the active variants of a level, so the same
+// player always lands on the same one.
+func PickVariant(username string, n int64, active []*qrpb.GameQuestion) *qrpb.GameQuestion {
+	if len(active) == 0 {
+		return nil
+	}
+	h := fnv.New32a()
+	fmt.Fprintf(h, "%v/%v", username, n)
+	return active[h.Sum32()%uint32(len(active))]
+}
+
+// GetQuestionVariant returns the named variant of level n's question. A variant that is missing or
+// retired falls back to the first active one. Like every lookup, it returns the question's backup
+// once that has been swapped in.
+func GetQuestionVariant(sqs *qrpb.GameQSet, n int64, variant string) *qrpb.GameQuestion {
+	vs := QuestionVariants(sqs, n)
+	if len(vs) == 0 {
+		return nil
+	}
+	var chosen *qrpb.GameQuestion
+	for _, q := range vs {
+		if !q.GetRetired() && q.GetVariant() == variant {
+			chosen = q
+			break
+		}
+	}
+	if chosen == nil {
+		if active := ActiveVariants(sqs, n); len(active) > 0 {
+			chosen = active[0]
+		} else {
+			chosen = vs[0]
+		}
+	}
+	if chosen.GetBackupActive() && chosen.Backup != nil {
+		b := proto.Clone(chosen.Backup).(*qrpb.GameQuestion)
+		b.QuestionId = proto.Int64(n)
+		b.Variant = chosen.Variant
+		return b
+	}
+	return chosen
+}
+
+// GetQuestionForPlayer returns the question the player is asked at their current level, following
+// the variant they were given.
+func GetQuestionForPlayer(sqs *qrpb.GameQSet, gs *qrpb.GameState) *qrpb.GameQuestion {
+	n := gs.GetUserLevel()
+	v, _ := VariantFor(gs, n)
+	return GetQuestionVariant(sqs, n, v)
+}
+
+// MaybeAssignVariant gives the player one of the variants of their current question, if it has
+// several and they don't hold an active one yet. It returns true if u.State was changed.
+func (env *Env) MaybeAssignVariant(u *StateRow) (bool, error) {
+	sqs, err := env.cgo.GetGameQSet()
+	if err != nil {
+		return false, err
+	}
+	return AssignVariant(sqs, u), nil
+}
+
+// AssignVariant is MaybeAssignVariant for a given GameQSet.
+func AssignVariant(sqs *qrpb.GameQSet, u *StateRow) bool {
+	n := u.State.GetUserLevel()
+	active := ActiveVariants(sqs, n)
+	if len(QuestionVariants(sqs, n)) < 2 || len(active) == 0 {
+		return false
+	}
+	if v, ok := VariantFor(u.State, n); ok {
+		for _, q := range active {
+			if q.GetVariant() == v {
+				return false
+			}
+		}
+	}
+	SetVariant(u.State, n, PickVariant(u.Username, n, active).GetVariant())
+	return true
+}
+
+// VariantUsage is one variant of a level's question, with the number of players currently on it.
+type VariantUsage struct {
+	Variant string
+	Retired bool
+	Players int
+}
+
+// LevelVariants lists the variants of a level that has more than one.
+type LevelVariants struct {
+	Level    int64
+	Variants []VariantUsage
+}
+
+// QuestionVariantUsage returns every level with several variants, and how the players on that
+// level are split between them.
+func QuestionVariantUsage(srs []StateRow, sqs *qrpb.GameQSet) []LevelVariants {
+	levels := make([]LevelVariants, 0)
+	seen := make(map[int64]bool)
+	for _, q := range sqs.GetGameQuestions() {
+		n := q.GetQuestionId()
+		vs := QuestionVariants(sqs, n)
+		if seen[n] || len(vs) < 2 {
+			continue
+		}
+		seen[n] = true
+		lv := LevelVariants{Level: n}
+		for _, v := range vs {
+			lv.Variants = append(lv.Variants, VariantUsage{Variant: v.GetVariant(), Retired: v.GetRetired()})
+		}
+		for _, sr := range srs {
+			if sr.State.GetUserLevel() != n || IsDead(sr.State) {
+				continue
+			}
+			given := GetQuestionForPlayer(sqs, sr.State).GetVariant()
+			for i := range lv.Variants {
+				if lv.Variants[i].Variant == given {
+					lv.Variants[i].Players++
+				}
+			}
+		}
+		levels = append(levels, lv)
+	}
+	return levels
+}
+
+// RetireVariant stops giving out one variant of level n's question, and moves every player who
+// was on it to the variant called to. Their attempts, route and hints on the level start over,
+// since they now have a different question. It returns the number of players moved.
+func (env *Env) RetireVariant(n int64, variant, to string) (int, error) {
+	if variant == to {
+		return 0, fmt.Errorf("players on variant %q of question %v need to move to a different variant", variant, n)
+	}
+	// sqs is the questions as they were before the variant was retired, to find who was on it.
+	var sqs *qrpb.GameQSet
+	err := env.cgo.UpdateGameQSet(func(updated *qrpb.GameQSet) (bool, error) {
+		sqs = proto.Clone(updated).(*qrpb.GameQSet)
+		var retiring, target *qrpb.GameQuestion
+		for _, q := range QuestionVariants(updated, n) {
+			if q.GetVariant() == variant {
+				retiring = q
+			}
+			if q.GetVariant() == to && !q.GetRetired() {
+				target = q
+			}
+		}
+		if retiring == nil {
+			return false, fmt.Errorf("question %v has no variant %q", n, variant)
+		}
+		if target == nil {
+			return false, fmt.Errorf("question %v has no active variant %q to move players to", n, to)
+		}
+		retiring.Retired = proto.Bool(true)
+		return true, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	srs, err := AdminGetAllUserStates(env.db)
+	if err != nil {
+		return 0, err
+	}
+	moved := 0
+	for _, sr := range srs {
+		if sr.State.GetUserLevel() != n {
+			continue
+		}
+		// The player may have moved on since the list was read, so only their variant and the
+		// progress on this level are changed, on their latest state.
+		ok, err := PatchUserState(env.db, sr.Username, func(gs *qrpb.GameState) bool {
+			if gs.GetUserLevel() != n || GetQuestionForPlayer(sqs, gs).GetVariant() != variant {
+				return false
+			}
+			SetVariant(gs, n, to)
+			gs.WrongAttempts = nil
+			gs.RouteProgress = nil
+			gs.HintLevel = nil
+			gs.HintsRevealed = nil
+			return true
+		})
+		if err != nil {
+			return moved, err
+		}
+		if ok {
+			moved++
+		}
+	}
+	return moved, nil
+}
+
+func (env *Env) adminRetireQuestion(w http.ResponseWriter, r *http.Request) {
+	log.Println("Req: ", r.URL)
+	if common.Should500(r.ParseForm(), w, "error parsing the variant to retire") {
+		return
+	}
+
+	level, err := strconv.ParseInt(r.FormValue("level"), 10, 64)
+	if common.Should500(err, w, "could not parse the question number") {
+		return
+	}
+	moved, err := env.RetireVariant(level, r.FormValue("variant"), r.FormValue("to"))
+	if common.Should500(err, w, "could not retire that variant") {
+		return
+	}
+	log.Printf("retired variant %q of question %v, moved %v players to %q", r.FormValue("variant"), level, moved, r.FormValue("to"))
+	fmt.Fprint(w, "ok")
+}
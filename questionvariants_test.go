@@ -0,0 +1,199 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/sushovande/qr-mixer-game/qrpb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// addVariantB splits question 2 into variant "a", the synthetic question, and variant "b", which
+// wants username-9.
+func addVariantB(cgo *CachedGameOptions) {
+	sqs, _ := cgo.GetGameQSet()
+	sqs = proto.Clone(sqs).(*qrpb.GameQSet)
+	sqs.GameQuestions[1].Variant = proto.String("a")
+	sqs.GameQuestions = append(sqs.GameQuestions, &qrpb.GameQuestion{
+		QuestionId:   proto.Int64(2),
+		Variant:      proto.String("b"),
+		QuestionHtml: proto.String("qHtml-2b"),
+		Type:         qrpb.GQType_USERNAME_LIST.Enum(),
+		AnsUsernames: []string{"username-9"},
+	})
+	cgo.SetGameQSet(sqs)
+}
+
+func TestQuestionVariants(t *testing.T) {
+	env, err := createEnv(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer env.db.Close()
+	setupGlobals(env.cgo)
+	addVariantB(env.cgo)
+	sqs, _ := env.cgo.GetGameQSet()
+
+	given := make(map[string]int)
+	for i := 1; i <= 20; i++ {
+		sr := GetSyntheticStateRow(i, 2)
+		if !AssignVariant(sqs, sr) {
+			t.Fatalf("expected username-%v to be given a variant", i)
+		}
+		v, _ := VariantFor(sr.State, 2)
+		given[v]++
+		if AssignVariant(sqs, sr) {
+			t.Errorf("expected username-%v to keep their variant", i)
+		}
+		again := GetSyntheticStateRow(i, 2)
+		AssignVariant(sqs, again)
+		if w, _ := VariantFor(again.State, 2); w != v {
+			t.Errorf("expected username-%v to always get variant %q. got: %q", i, v, w)
+		}
+
+		want := map[string]string{"a": "qrcode-2", "b": "qrcode-9"}[v]
+		mr, _ := env.Step(sr.State, want)
+		if mr.actionString != "Correct!" {
+			t.Errorf("expected %v to answer variant %q. got: %v", want, v, mr.actionString)
+		}
+	}
+	if given["a"] == 0 || given["b"] == 0 {
+		t.Errorf("expected players to be split between the variants. got: %v", given)
+	}
+
+	sr := GetSyntheticStateRow(1, 3)
+	if AssignVariant(sqs, sr) || len(sr.State.GetVariants()) != 0 {
+		t.Errorf("expected no variant on a level with one question. got: %v", sr.State)
+	}
+	if GetQuestionByIndex(sqs, 2).GetVariant() != "a" {
+		t.Errorf("expected the first variant for a player who has none. got: %v", GetQuestionByIndex(sqs, 2))
+	}
+}
+
+func TestRetireVariant(t *testing.T) {
+	env, err := createEnv(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer env.db.Close()
+	setupGlobals(env.cgo)
+	addVariantB(env.cgo)
+
+	for i := 1; i <= 10; i++ {
+		sr := GetSyntheticStateRow(i, 2)
+		sr.State.WrongAttempts = proto.Int64(1)
+		AddUser(env.db, sr)
+		ck := http.Cookie{Name: "sid", Value: sr.Cookie}
+		callController("GET", "/game", "", &ck, env.gameHandler)
+	}
+	srs, _ := AdminGetAllUserStates(env.db)
+	sqs, _ := env.cgo.GetGameQSet()
+	wasOnA := make(map[string]bool)
+	for _, sr := range srs {
+		v, _ := VariantFor(sr.State, 2)
+		wasOnA[sr.Username] = v == "a"
+	}
+	usage := QuestionVariantUsage(srs, sqs)
+	if len(usage) != 1 || usage[0].Level != 2 || usage[0].Variants[0].Players == 0 || usage[0].Variants[1].Players == 0 {
+		t.Fatalf("expected players on both variants of question 2. got: %v", usage)
+	}
+
+	if _, err := env.RetireVariant(2, "a", "a"); err == nil {
+		t.Errorf("expected an error moving players onto the variant being retired")
+	}
+	if _, err := env.RetireVariant(2, "a", "c"); err == nil {
+		t.Errorf("expected an error moving players to a variant that does not exist")
+	}
+
+	admin := "/9283e316-beaa-4182-b3a6-0937046251ee/retireQuestion"
+	f := callController("POST", admin, "level=2&variant=a&to=b", nil, env.adminRetireQuestion)
+	if f.statuscode != http.StatusOK {
+		t.Fatalf("expected the variant to be retired. got: %v %v", f.statuscode, f.resptext)
+	}
+	sqs, _ = env.cgo.GetGameQSet()
+	if !QuestionVariants(sqs, 2)[0].GetRetired() {
+		t.Errorf("expected variant a to be retired. got: %v", QuestionVariants(sqs, 2))
+	}
+	srs, _ = AdminGetAllUserStates(env.db)
+	for _, sr := range srs {
+		if v, _ := VariantFor(sr.State, 2); v != "b" {
+			t.Errorf("expected %v to be on variant b. got: %q", sr.Username, v)
+		}
+		if wasOnA[sr.Username] == (sr.State.GetWrongAttempts() != 0) {
+			t.Errorf("expected only the moved players to start the question over. got: %v %v", sr.Username, sr.State)
+		}
+	}
+	cookie := "cookie-1"
+	for u, moved := range wasOnA {
+		if moved {
+			cookie = "cookie-" + strings.TrimPrefix(u, "username-")
+		}
+	}
+	ck := http.Cookie{Name: "sid", Value: cookie}
+	f = callController("GET", "/game", "", &ck, env.gameHandler)
+	if !strings.Contains(f.resptext, "qHtml-2b") {
+		t.Errorf("expected the game page to show variant b. got: %v", f.resptext)
+	}
+
+	sr := GetSyntheticStateRow(11, 2)
+	AssignVariant(sqs, sr)
+	if v, _ := VariantFor(sr.State, 2); v != "b" {
+		t.Errorf("expected new players to get variant b. got: %v", sr.State)
+	}
+	if _, err := env.RetireVariant(2, "b", "a"); err == nil {
+		t.Errorf("expected an error moving players back onto a retired variant")
+	}
+}
+
+func TestValidateQuestionVariants(t *testing.T) {
+	env, err := createEnv(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer env.db.Close()
+	setupGlobals(env.cgo)
+	addVariantB(env.cgo)
+
+	def, err := env.CurrentGameDefinition()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vr := ValidateGame(def); len(vr.Errors) != 0 {
+		t.Errorf("expected variants with their own names to be valid. got: %v", vr)
+	}
+
+	qs := def.Questions.GetGameQuestions()
+	qs[len(qs)-1].Variant = proto.String("a")
+	qs[3].Retired = proto.Bool(true)
+	vr := ValidateGame(def)
+	for _, want := range []string{
+		`question 2 has two variants called "a"`,
+		"question 4 is retired, and has no other variant to give players",
+	} {
+		if !hasIssue(vr.Errors, PART_QUESTIONS, want) {
+			t.Errorf("expected the error %q. got: %v", want, vr)
+		}
+	}
+
+	qs[1].Variant = nil
+	vr = ValidateGame(def)
+	if !hasIssue(vr.Errors, PART_QUESTIONS, "question 2 appears more than once") {
+		t.Errorf("expected an error for a copy without a variant name. got: %v", vr)
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/sushovande/qr-mixer-game/qrpb"
+)
+
+// questionVarPattern matches a variable in question_html, like {{name}}.
+var questionVarPattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_]+)\s*\}\}`)
+
+// QUESTION_VARS are the variables question_html can use, and what each becomes.
+var QUESTION_VARS = map[string]string{
+	"name":         "the player's name",
+	"card":         "the card on the player's badge, like Q♥",
+	"level":        "the number of the question the player is on",
+	"players_left": "how many players are neither out nor finished",
+	"last_scanned": "the name of the last person the player scanned",
+}
+
+// QuestionVars returns the names of the variables used in the question html, in order of first use.
+func QuestionVars(questionHTML string) []string {
+	names := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, m := range questionVarPattern.FindAllStringSubmatch(questionHTML, -1) {
+		if !seen[m[1]] {
+			seen[m[1]] = true
+			names = append(names, m[1])
+		}
+	}
+	return names
+}
+
+// UnknownQuestionVars returns the variables in the question html that are not in QUESTION_VARS.
+func UnknownQuestionVars(questionHTML string) []string {
+	unknown := make([]string, 0)
+	for _, name := range QuestionVars(questionHTML) {
+		if _, ok := QUESTION_VARS[name]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	return unknown
+}
+
+// QuestionVarList lists the known variables, for error messages.
+func QuestionVarList() string {
+	names := make([]string, 0, len(QUESTION_VARS))
+	for name := range QUESTION_VARS {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// RenderQuestionVars replaces the variables in the question html with the given values, escaped
+// for html. Variables without a value are left as they are.
+func RenderQuestionVars(questionHTML string, values map[string]string) string {
+	return questionVarPattern.ReplaceAllStringFunc(questionHTML, func(m string) string {
+		v, ok := values[questionVarPattern.FindStringSubmatch(m)[1]]
+		if !ok {
+			return m
+		}
+		return html.EscapeString(v)
+	})
+}
+
+// questionVarValues works out the value of each variable the question html uses, for the player.
+// Only the variables in use are looked up, since some of them read every player or log.
+func (env *Env) questionVarValues(u *StateRow, questionHTML string) (map[string]string, error) {
+	values := make(map[string]string)
+	for _, name := range QuestionVars(questionHTML) {
+		switch name {
+		case "name":
+			values[name] = u.UserInfo.GetName()
+		case "card":
+			qrm, err := env.cgo.GetQRMappings()
+			if err != nil {
+				return nil, err
+			}
+			if m := qrm.LookupByUsername(u.Username); m != nil && m.GetCardRank() > 0 {
+				values[name] = PokerCardName(&qrpb.PokerCard{Suit: m.CardSuit, Rank: m.CardRank})
+			}
+		case "level":
+			values[name] = fmt.Sprint(u.State.GetUserLevel())
+		case "players_left":
+			srs, err := AdminGetAllUserStates(env.db)
+			if err != nil {
+				return nil, err
+			}
+			n := 0
+			for _, sr := range srs {
+				if !IsDead(sr.State) && !HasWon(sr.State) {
+					n++
+				}
+			}
+			values[name] = fmt.Sprint(n)
+		case "last_scanned":
+			last, err := env.lastScannedName(u.Username)
+			if err != nil {
+				return nil, err
+			}
+			values[name] = last
+		}
+	}
+	return values, nil
+}
+
+// lastScannedName returns the name of the last person the player scanned, or "nobody yet".
+func (env *Env) lastScannedName(username string) (string, error) {
+	logs, err := GetAllLogsForUser(env.db, username)
+	if err != nil {
+		return "", err
+	}
+	qrm, err := env.cgo.GetQRMappings()
+	if err != nil {
+		return "", err
+	}
+	// The logs are newest first.
+	for _, lr := range logs {
+		if lr.GameLog.GetType() != qrpb.ActionLog_ACTION_CODE_SCAN {
+			continue
+		}
+		if m := qrm.LookupByUsername(lr.GameLog.GetClueShortName()); m != nil {
+			return m.GetDisplayName(), nil
+		}
+	}
+	return "nobody yet", nil
+}
+
+// PersonalizeQuestion fills in the variables of the question html for the player.
+func (env *Env) PersonalizeQuestion(u *StateRow, questionHTML string) (string, error) {
+	if !strings.Contains(questionHTML, "{{") {
+		return questionHTML, nil
+	}
+	values, err := env.questionVarValues(u, questionHTML)
+	if err != nil {
+		return "", err
+	}
+	return RenderQuestionVars(questionHTML, values), nil
+}
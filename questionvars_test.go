@@ -0,0 +1,70 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/sushovande/qr-mixer-game/qrpb"
+
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestRenderQuestionVars(t *testing.T) {
+	got := RenderQuestionVars("Hi {{name}}, find {{ card }}. {{other}}", map[string]string{"name": "<b>Ann</b>", "card": "Q♥"})
+	if want := "Hi &lt;b&gt;Ann&lt;/b&gt;, find Q♥. {{other}}"; got != want {
+		t.Errorf("expected %q. got: %q", want, got)
+	}
+	if u := UnknownQuestionVars("{{name}} {{nme}} {{level}} {{nme}}"); len(u) != 1 || u[0] != "nme" {
+		t.Errorf("expected nme to be the only unknown variable. got: %v", u)
+	}
+}
+
+func TestPersonalizedQuestions(t *testing.T) {
+	env, err := createEnv(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer env.db.Close()
+	setupGlobals(env.cgo)
+	sqs, _ := env.cgo.GetGameQSet()
+	GetQuestionByIndex(sqs, 1).QuestionHtml = proto.String("Hi {{name}}, you hold the {{ card }}. {{players_left}} left, Q{{level}}. Last: {{last_scanned}}.")
+	GetQuestionByIndex(sqs, 2).QuestionHtml = proto.String("Last: {{last_scanned}}.")
+	env.cgo.SetGameQSet(sqs)
+	AddUser(env.db, GetSyntheticStateRow(1, 1))
+	AddUser(env.db, GetSyntheticStateRow(2, 1))
+
+	ck := http.Cookie{Name: "sid", Value: "cookie-1"}
+	f := callController("GET", "/game", "", &ck, env.gameHandler)
+	if !strings.Contains(f.resptext, "Hi name-1, you hold the A♠. 2 left, Q1. Last: nobody yet.") {
+		t.Errorf("expected the question to be filled in for the player. got: %v", f.resptext)
+	}
+
+	f = callController("POST", "/makemove", "answer=qrcode-2", &ck, env.makeMove)
+	if !strings.Contains(f.resptext, "Last: name-2.") {
+		t.Errorf("expected the next question to name who was just scanned. got: %v", f.resptext)
+	}
+
+	sqs = proto.Clone(sqs).(*qrpb.GameQSet)
+	GetQuestionByIndex(sqs, 3).QuestionHtml = proto.String("Find {{nmae}}'s twin.")
+	f = callController("POST", "/saveQuestions", "gameq="+url.QueryEscape(prototext.Format(sqs)), nil, env.adminSaveQuestions)
+	if f.statuscode != http.StatusInternalServerError || !strings.Contains(f.resptext, "question 3: question_html uses {{nmae}}") {
+		t.Errorf("expected an unknown variable to be rejected. got: %v %v", f.statuscode, f.resptext)
+	}
+}
@@ -0,0 +1,248 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sushovande/qr-mixer-game/common"
+	"github.com/sushovande/qr-mixer-game/qrpb"
+
+	qrcode "github.com/skip2/go-qrcode"
+	proto "google.golang.org/protobuf/proto"
+)
+
+// ROTATING_CODE_PREFIX starts every rotating code, so they can be told apart from printed ones.
+const ROTATING_CODE_PREFIX = "qrmix-live:"
+
+const DEFAULT_ROTATING_CODE_PERIOD_SEC int64 = 30
+
+// MIN_ROTATING_CODE_PERIOD_SEC leaves time to point a camera at the phone before the code changes.
+const MIN_ROTATING_CODE_PERIOD_SEC int64 = 10
+
+// NewPresenceSecret returns a fresh random secret for a player's rotating code.
+func NewPresenceSecret() (string, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// RotatingCodePeriod returns how long each rotating code is shown for.
+func RotatingCodePeriod(gc *qrpb.GameConfig) time.Duration {
+	if gc.GetRotatingCodePeriodSec() > 0 {
+		return time.Duration(gc.GetRotatingCodePeriodSec()) * time.Second
+	}
+	return time.Duration(DEFAULT_ROTATING_CODE_PERIOD_SEC) * time.Second
+}
+
+// RotatingCode returns the six digit code for the secret in the given time window, the same way
+// TOTP authenticator apps do.
+func RotatingCode(secret string, window int64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(window))
+	mac := hmac.New(sha1.New, []byte(secret))
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+	off := sum[len(sum)-1] & 0x0f
+	n := binary.BigEndian.Uint32(sum[off:off+4]) & 0x7fffffff
+	return fmt.Sprintf("%06d", n%1000000)
+}
+
+func rotatingWindow(now time.Time, period time.Duration) int64 {
+	return now.UnixNano() / int64(period)
+}
+
+// RotatingQRString is what the player's phone shows as a QR code at the given time.
+func RotatingQRString(username, secret string, now time.Time, period time.Duration) string {
+	return ROTATING_CODE_PREFIX + username + ":" + RotatingCode(secret, rotatingWindow(now, period))
+}
+
+// ParseRotatingQR splits a scanned rotating code into the username and the code. ok is false if
+// the scan is not a rotating code.
+func ParseRotatingQR(answer string) (username, code string, ok bool) {
+	rest := strings.TrimPrefix(answer, ROTATING_CODE_PREFIX)
+	if rest == answer {
+		return "", "", false
+	}
+	i := strings.LastIndex(rest, ":")
+	if i <= 0 {
+		return "", "", false
+	}
+	return rest[:i], rest[i+1:], true
+}
+
+// VerifyRotatingCode reports whether the code is the one for the current time window, or the one
+// just before it, to allow for a slow scan.
+func VerifyRotatingCode(secret, code string, now time.Time, period time.Duration) bool {
+	if len(secret) == 0 {
+		return false
+	}
+	w := rotatingWindow(now, period)
+	for _, cw := range []int64{w, w - 1} {
+		if hmac.Equal([]byte(RotatingCode(secret, cw)), []byte(code)) {
+			return true
+		}
+	}
+	return false
+}
+
+// acceptsPrinted and acceptsRotating tell which codes the organizer allowed.
+func acceptsPrinted(gc *qrpb.GameConfig) bool {
+	return gc.GetBadgeCodes() != qrpb.BadgeCodes_ROTATING_ONLY
+}
+
+func acceptsRotating(gc *qrpb.GameConfig) bool {
+	bc := gc.GetBadgeCodes()
+	return bc == qrpb.BadgeCodes_ROTATING_ONLY || bc == qrpb.BadgeCodes_PRINTED_OR_ROTATING
+}
+
+// ResolveScan works out whose badge or phone was scanned. It returns nil for a code nobody has.
+// If the scan should not count, for example because the rotating code has expired, rejection
+// says why and the step should be ignored.
+func (env *Env) ResolveScan(answer string, now time.Time) (m *qrpb.QRMapping, rejection string, err error) {
+	qrm, err := env.cgo.GetQRMappings()
+	if err != nil {
+		return nil, "", err
+	}
+	gc, err := env.cgo.GetGameConfig()
+	if err != nil {
+		return nil, "", err
+	}
+
+	if username, code, ok := ParseRotatingQR(answer); ok {
+		if !acceptsRotating(gc) {
+			return nil, "Scan their badge instead!", nil
+		}
+		gu, err := GetUserInfoByUsername(env.db, username)
+		if err != nil {
+			return nil, "", err
+		}
+		if !VerifyRotatingCode(gu.GetPresenceSecret(), code, now, RotatingCodePeriod(gc)) {
+			return nil, "That code has expired!", nil
+		}
+		return qrm.LookupByUsername(username), "", nil
+	}
+
+	m = qrm.LookupByQrCode(answer)
+	if m == nil || acceptsPrinted(gc) {
+		return m, "", nil
+	}
+	// Props and people who never registered have no phone to show a code on.
+	gu, err := GetUserInfoByUsername(env.db, m.GetUsername())
+	if err != nil {
+		return nil, "", err
+	}
+	if gu != nil {
+		return nil, "Scan the code on their phone instead!", nil
+	}
+	return m, "", nil
+}
+
+// stepRejected fills in the result for a scan that ResolveScan rejected.
+func stepRejected(result *StepResponse, rejection string) {
+	result.actionString = rejection
+	result.actionResult = *qrpb.ActionLog_RESULT_IGNORED.Enum()
+}
+
+// ensurePresenceSecret gives a player who registered before rotating codes were turned on a
+// secret. It returns true if u.UserInfo was changed.
+func ensurePresenceSecret(u *StateRow) (bool, error) {
+	if len(u.UserInfo.GetPresenceSecret()) > 0 {
+		return false, nil
+	}
+	secret, err := NewPresenceSecret()
+	if err != nil {
+		return false, err
+	}
+	u.UserInfo.PresenceSecret = proto.String(secret)
+	return true, nil
+}
+
+// myCode renders the page that shows the player's rotating code, for others to scan.
+func (env *Env) myCode(w http.ResponseWriter, r *http.Request) {
+	log.Println("Req: ", r.URL)
+	u, gc, ok := env.rotatingCodePlayer(w, r)
+	if !ok {
+		return
+	}
+	common.RenderTemplate(w, env.tem, "mycode.html", struct {
+		U         *StateRow
+		Enabled   bool
+		PeriodSec int64
+	}{
+		U:         u,
+		Enabled:   acceptsRotating(gc),
+		PeriodSec: int64(RotatingCodePeriod(gc).Seconds()),
+	})
+}
+
+// myCodeImage is the QR code image of the player's current rotating code.
+func (env *Env) myCodeImage(w http.ResponseWriter, r *http.Request) {
+	u, gc, ok := env.rotatingCodePlayer(w, r)
+	if !ok {
+		return
+	}
+	if !acceptsRotating(gc) {
+		http.NotFound(w, r)
+		return
+	}
+	png, err := qrcode.Encode(RotatingQRString(u.Username, u.UserInfo.GetPresenceSecret(), time.Now(), RotatingCodePeriod(gc)), qrcode.High, 450)
+	if common.Should500(err, w, "could not encode qrcode") {
+		return
+	}
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Write(png)
+}
+
+// rotatingCodePlayer looks up the player from their cookie and makes sure they have a secret.
+// It writes the error response and returns false if it could not.
+func (env *Env) rotatingCodePlayer(w http.ResponseWriter, r *http.Request) (*StateRow, *qrpb.GameConfig, bool) {
+	ck, err := r.Cookie("sid")
+	if err != nil {
+		common.RespondHTTP401(w)
+		return nil, nil, false
+	}
+	u, err := GetUserStateByCookie(env.db, ck.Value)
+	if err != nil || u == nil {
+		common.RespondHTTP401(w)
+		return nil, nil, false
+	}
+	gc, err := env.cgo.GetGameConfig()
+	if common.Should500(err, w, "There was a problem reading the game settings, maybe try again?") {
+		return nil, nil, false
+	}
+	changed, err := ensurePresenceSecret(u)
+	if common.Should500(err, w, "There was a problem setting up your code, maybe try again?") {
+		return nil, nil, false
+	}
+	if changed {
+		if common.Should500(UpdateUserDetails(env.GetDb(), u), w, "There was a problem saving your code, maybe try again?") {
+			return nil, nil, false
+		}
+	}
+	return u, gc, true
+}
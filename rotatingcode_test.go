@@ -0,0 +1,109 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sushovande/qr-mixer-game/qrpb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestRotatingCode(t *testing.T) {
+	// The SHA1 test vector from RFC 6238, cut down to six digits.
+	if c := RotatingCode("12345678901234567890", 1); c != "287082" {
+		t.Errorf("expected the RFC 6238 code. got: %v", c)
+	}
+
+	now := time.Unix(1000000, 0)
+	period := 30 * time.Second
+	qr := RotatingQRString("username-2", "s3cret", now, period)
+	username, code, ok := ParseRotatingQR(qr)
+	if !ok || username != "username-2" {
+		t.Fatalf("expected to parse %v. got: %v %v", qr, username, ok)
+	}
+	if !VerifyRotatingCode("s3cret", code, now.Add(period), period) {
+		t.Errorf("expected the code to still work one period later")
+	}
+	if VerifyRotatingCode("s3cret", code, now.Add(2*period), period) {
+		t.Errorf("expected the code to expire after two periods")
+	}
+	if VerifyRotatingCode("other", code, now, period) {
+		t.Errorf("expected the code to need the right secret")
+	}
+	if _, _, ok := ParseRotatingQR("qrcode-2"); ok {
+		t.Errorf("expected a printed code not to parse")
+	}
+}
+
+func TestRotatingCodesInGame(t *testing.T) {
+	env, err := createEnv(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer env.db.Close()
+	setupSynthetic(env.cgo, 10)
+	u2 := GetSyntheticStateRow(2, 1)
+	u2.UserInfo.PresenceSecret = proto.String("s3cret")
+	AddUser(env.db, u2)
+	period := 30 * time.Second
+
+	u1 := GetSyntheticStateRow(1, 2)
+	live := RotatingQRString("username-2", "s3cret", time.Now(), period)
+	mr, _ := env.Step(u1.State, live)
+	if mr.actionString != "Scan their badge instead!" || mr.newState.GetLife() != 3 {
+		t.Errorf("expected rotating codes to be off by default. got: %v %v", mr.actionString, mr.newState)
+	}
+
+	env.cgo.SetGameConfig(&qrpb.GameConfig{BadgeCodes: qrpb.BadgeCodes_ROTATING_ONLY.Enum()})
+	mr, _ = env.Step(u1.State, "qrcode-2")
+	if mr.actionString != "Scan the code on their phone instead!" || mr.newState.GetUserLevel() != 2 {
+		t.Errorf("expected the printed badge of a registered player to be rejected. got: %v %v", mr.actionString, mr.newState)
+	}
+	mr, _ = env.Step(u1.State, "qrcode-3")
+	if mr.actionString != "Correct!" {
+		t.Errorf("expected the badge of someone without a phone to count. got: %v", mr.actionString)
+	}
+	mr, _ = env.Step(u1.State, live)
+	if mr.actionString != "Correct!" {
+		t.Errorf("expected the rotating code to count. got: %v", mr.actionString)
+	}
+	old := RotatingQRString("username-2", "s3cret", time.Now().Add(-3*period), period)
+	mr, _ = env.Step(u1.State, old)
+	if mr.actionString != "That code has expired!" || mr.newState.GetLife() != 3 {
+		t.Errorf("expected an old code to be rejected without a penalty. got: %v %v", mr.actionString, mr.newState)
+	}
+
+	// A player who registered before codes were turned on gets a secret when they open the page.
+	u3 := GetSyntheticStateRow(3, 1)
+	AddUser(env.db, u3)
+	ck := http.Cookie{Name: "sid", Value: u3.Cookie}
+	f := callController("GET", "/mycode", "", &ck, env.myCode)
+	if !strings.Contains(f.resptext, `src="/mycode.png"`) {
+		t.Errorf("expected the code page. got: %v", f.resptext)
+	}
+	gu, _ := GetUserInfoByUsername(env.db, "username-3")
+	if len(gu.GetPresenceSecret()) == 0 {
+		t.Errorf("expected a secret to be issued")
+	}
+	f = callController("GET", "/mycode.png", "", &ck, env.myCodeImage)
+	if f.statuscode != http.StatusOK {
+		t.Errorf("expected the code image. got: %v", f.statuscode)
+	}
+}
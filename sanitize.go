@@ -0,0 +1,323 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+
+	"github.com/sushovande/qr-mixer-game/qrpb"
+)
+
+// JSQR_URL is the one file of the jsQR library the scanning pages load. The CSP names the file
+// rather than the CDN, since the CDN serves any package.
+const JSQR_URL = "https://cdn.jsdelivr.net/npm/jsqr@1/dist/jsQR.min.js"
+
+// PLAYER_CSP is the Content-Security-Policy on the pages players see. Scripts only come from our
+// own static files and the jsQR library, so even html that gets past the sanitizer cannot run code.
+const PLAYER_CSP = "default-src 'self'; script-src 'self' " + JSQR_URL + "; " +
+	"img-src 'self' data: blob: https:; style-src 'self'; object-src 'none'; base-uri 'none'; " +
+	"frame-ancestors 'none'; form-action 'self'"
+
+// allowedHTML lists the tags that question html can use, and the attributes each of them can have.
+// Every allowed tag can also have a class, to pick up the styles in cashier.css.
+var allowedHTML = map[string][]string{
+	"a": {"href", "title"}, "abbr": {"title"}, "b": nil, "blockquote": nil, "br": nil, "code": nil,
+	"div": nil, "em": nil, "h1": nil, "h2": nil, "h3": nil, "h4": nil, "h5": nil, "h6": nil, "hr": nil,
+	"i": nil, "img": {"src", "alt", "width", "height"}, "li": nil, "ol": nil, "p": nil, "pre": nil,
+	"s": nil, "small": nil, "span": nil, "strong": nil, "sub": nil, "sup": nil, "table": nil,
+	"tbody": nil, "td": {"colspan", "rowspan"}, "th": {"colspan", "rowspan"}, "thead": nil, "tr": nil,
+	"u": nil, "ul": nil,
+}
+
+// voidTags have no closing tag.
+var voidTags = map[string]bool{"br": true, "hr": true, "img": true}
+
+// droppedWithContent are removed along with everything inside them, since their content is code,
+// or is not shown as text.
+var droppedWithContent = map[string]bool{
+	"script": true, "style": true, "iframe": true, "object": true, "noscript": true, "template": true,
+	"textarea": true, "title": true, "xmp": true, "noembed": true, "noframes": true, "svg": true,
+	"math": true, "select": true,
+}
+
+// urlSchemes lists the schemes each url attribute can use. Relative urls are always allowed.
+var urlSchemes = map[string][]string{"href": {"http", "https", "mailto"}, "src": {"http", "https"}}
+
+type htmlAttr struct {
+	name, value string
+}
+
+// SanitizeHTML keeps only the tags and attributes in allowedHTML, and only safe urls. It returns
+// the cleaned html and a description of each kind of thing it removed.
+func SanitizeHTML(s string) (string, []string) {
+	return sanitize(s, allowedHTML)
+}
+
+// SanitizeText removes every tag from s, for fields that are shown as plain text. The result is
+// still text, so a < that does not start a tag is left as it is.
+func SanitizeText(s string) (string, []string) {
+	return sanitize(s, nil)
+}
+
+func sanitize(s string, allowed map[string][]string) (string, []string) {
+	var sb strings.Builder
+	var removed []string
+	seen := make(map[string]bool)
+	report := func(format string, a ...interface{}) {
+		m := fmt.Sprintf(format, a...)
+		if !seen[m] {
+			seen[m] = true
+			removed = append(removed, m)
+		}
+	}
+
+	lt := "&lt;"
+	if allowed == nil {
+		lt = "<"
+	}
+	i := 0
+	for i < len(s) {
+		j := strings.IndexByte(s[i:], '<')
+		if j < 0 {
+			sb.WriteString(s[i:])
+			break
+		}
+		sb.WriteString(s[i : i+j])
+		i += j
+
+		rest := s[i:]
+		if strings.HasPrefix(rest, "<!--") {
+			end := strings.Index(rest[4:], "-->")
+			if end < 0 {
+				i = len(s)
+			} else {
+				i += 4 + end + 3
+			}
+			report("an html comment")
+			continue
+		}
+		if len(rest) > 1 && (rest[1] == '!' || rest[1] == '?') {
+			end := strings.IndexByte(rest, '>')
+			if end < 0 {
+				i = len(s)
+			} else {
+				i += end + 1
+			}
+			report("a <%c...> declaration", rest[1])
+			continue
+		}
+
+		name, attrs, closing, n, ok := parseTag(rest)
+		if !ok {
+			// Not a tag, so it is shown as text.
+			sb.WriteString(lt)
+			i++
+			continue
+		}
+		i += n
+
+		if droppedWithContent[name] {
+			if !closing {
+				i += skipElement(s[i:], name)
+				report("<%v> and everything in it", name)
+			}
+			continue
+		}
+		allowedAttrs, ok := allowed[name]
+		if !ok {
+			report("<%v>", name)
+			continue
+		}
+		if closing {
+			if !voidTags[name] {
+				sb.WriteString("</" + name + ">")
+			}
+			continue
+		}
+
+		sb.WriteString("<" + name)
+		for _, a := range attrs {
+			if a.name != "class" && !ListHasString(allowedAttrs, a.name) {
+				report("the %v attribute on <%v>", a.name, name)
+				continue
+			}
+			v := html.UnescapeString(a.value)
+			if schemes, ok := urlSchemes[a.name]; ok && !safeURL(v, schemes) {
+				report("the %v %q on <%v>", a.name, v, name)
+				continue
+			}
+			sb.WriteString(" " + a.name + `="` + html.EscapeString(v) + `"`)
+		}
+		sb.WriteString(">")
+	}
+	return sb.String(), removed
+}
+
+// parseTag reads the tag at the start of s, which begins with '<'. It returns the lowercased tag
+// name, its attributes, whether it is a closing tag, and how many bytes it took up. ok is false if s
+// does not start with a well formed tag.
+func parseTag(s string) (name string, attrs []htmlAttr, closing bool, n int, ok bool) {
+	i := 1
+	if i < len(s) && s[i] == '/' {
+		closing = true
+		i++
+	}
+	start := i
+	if i >= len(s) || !isASCIILetter(s[i]) {
+		return "", nil, false, 0, false
+	}
+	for i < len(s) && (isASCIILetter(s[i]) || (s[i] >= '0' && s[i] <= '9') || s[i] == '-') {
+		i++
+	}
+	name = strings.ToLower(s[start:i])
+
+	for {
+		for i < len(s) && (isHTMLSpace(s[i]) || s[i] == '/') {
+			i++
+		}
+		if i >= len(s) {
+			return "", nil, false, 0, false
+		}
+		if s[i] == '>' {
+			return name, attrs, closing, i + 1, true
+		}
+		start = i
+		i++
+		for i < len(s) && !isHTMLSpace(s[i]) && s[i] != '/' && s[i] != '>' && s[i] != '=' {
+			i++
+		}
+		a := htmlAttr{name: strings.ToLower(s[start:i])}
+		for i < len(s) && isHTMLSpace(s[i]) {
+			i++
+		}
+		if i < len(s) && s[i] == '=' {
+			i++
+			for i < len(s) && isHTMLSpace(s[i]) {
+				i++
+			}
+			if i < len(s) && (s[i] == '"' || s[i] == '\'') {
+				end := strings.IndexByte(s[i+1:], s[i])
+				if end < 0 {
+					return "", nil, false, 0, false
+				}
+				a.value = s[i+1 : i+1+end]
+				i += end + 2
+			} else {
+				start = i
+				for i < len(s) && !isHTMLSpace(s[i]) && s[i] != '>' {
+					i++
+				}
+				a.value = s[start:i]
+			}
+		}
+		attrs = append(attrs, a)
+	}
+}
+
+// skipElement returns how many bytes of s come before the end of the element called name,
+// including its closing tag. An element that is never closed runs to the end of s.
+func skipElement(s string, name string) int {
+	lower := strings.ToLower(s)
+	end := strings.Index(lower, "</"+name)
+	if end < 0 {
+		return len(s)
+	}
+	gt := strings.IndexByte(s[end:], '>')
+	if gt < 0 {
+		return len(s)
+	}
+	return end + gt + 1
+}
+
+// safeURL reports whether u is relative, or uses one of the given schemes. Browsers ignore
+// whitespace and control characters in a scheme, so those are dropped before looking at it.
+func safeURL(u string, schemes []string) bool {
+	u = strings.Map(func(r rune) rune {
+		if r <= ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, u)
+	colon := strings.IndexByte(u, ':')
+	if colon < 0 || strings.ContainsAny(u[:colon], "/?#") {
+		return true
+	}
+	return ListHasString(schemes, strings.ToLower(u[:colon]))
+}
+
+func isASCIILetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isHTMLSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+// SanitizeQuestions cleans the html of every question, backup and hint in place. It returns one
+// line for each field that lost something, naming the field and what was removed from it.
+func SanitizeQuestions(sqs *qrpb.GameQSet) []string {
+	var lines []string
+	clean := func(label string, s *string) {
+		c, removed := SanitizeHTML(*s)
+		*s = c
+		if len(removed) > 0 {
+			lines = append(lines, fmt.Sprintf("%v: %v", label, strings.Join(removed, ", ")))
+		}
+	}
+	var walk func(label string, q *qrpb.GameQuestion)
+	walk = func(label string, q *qrpb.GameQuestion) {
+		if q.QuestionHtml != nil {
+			clean(label+" question_html", q.QuestionHtml)
+		}
+		for i := range q.Hints {
+			clean(fmt.Sprintf("%v hint %v", label, i+1), &q.Hints[i])
+		}
+		if q.Backup != nil {
+			walk(label+" backup", q.Backup)
+		}
+	}
+	for _, q := range sqs.GetGameQuestions() {
+		walk(fmt.Sprintf("question %v", q.GetQuestionId()), q)
+	}
+	return lines
+}
+
+// SanitizeSurvey removes any tags from the survey question text in place, and returns what was
+// removed in the same form as SanitizeQuestions.
+func SanitizeSurvey(ss *qrpb.SurveySet) []string {
+	var lines []string
+	for _, q := range ss.GetSurveyQuestions() {
+		if q.QuestionText == nil {
+			continue
+		}
+		c, removed := SanitizeText(q.GetQuestionText())
+		q.QuestionText = &c
+		if len(removed) > 0 {
+			lines = append(lines, fmt.Sprintf("survey question %v: %v", q.GetQuestionId(), strings.Join(removed, ", ")))
+		}
+	}
+	return lines
+}
+
+// withPlayerCSP sets PLAYER_CSP on the pages that players load.
+func withPlayerCSP(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Security-Policy", PLAYER_CSP)
+		h(w, r)
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/sushovande/qr-mixer-game/qrpb"
+
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSanitizeHTML(t *testing.T) {
+	for _, tc := range []struct {
+		in, want, removed string
+	}{
+		{`<p class="big">Find <b>the</b> person &amp; say hi</p>`, `<p class="big">Find <b>the</b> person &amp; say hi</p>`, ""},
+		{`<P>hi<br/></P>`, `<p>hi<br></p>`, ""},
+		{`a<script>alert(1)</script>b`, `ab`, "<script> and everything in it"},
+		{`a<SCRIPT src=x>`, `a`, "<script> and everything in it"},
+		{`<img src=x onerror="alert(1)">`, `<img src="x">`, "the onerror attribute on <img>"},
+		{`<img/src=x/onerror=alert(1)>`, `<img src="x/onerror=alert(1)">`, ""},
+		{`<a href="java&#x09;script:alert(1)">x</a>`, `<a>x</a>`, `the href "java\tscript:alert(1)" on <a>`},
+		{`<a href="JavaScript&colon;alert(1)">x</a>`, `<a>x</a>`, `the href "JavaScript:alert(1)" on <a>`},
+		{`<a href="/game?a=1&b=2">x</a>`, `<a href="/game?a=1&amp;b=2">x</a>`, ""},
+		{`<img src="data:image/png;base64,AA">`, `<img>`, `the src "data:image/png;base64,AA" on <img>`},
+		{`<font color=red>red</font>`, `red`, "<font>"},
+		{`<svg><script>alert(1)</script></svg>ok`, `ok`, "<svg> and everything in it"},
+		{`x<!-- <script> -->y`, `xy`, "an html comment"},
+		{`1 < 2 and <3`, `1 &lt; 2 and &lt;3`, ""},
+		{`<a title='"><script>'>x</a>`, `<a title="&#34;&gt;&lt;script&gt;">x</a>`, ""},
+		{`<b onclick=x`, `&lt;b onclick=x`, ""},
+	} {
+		got, removed := SanitizeHTML(tc.in)
+		if got != tc.want {
+			t.Errorf("SanitizeHTML(%q) = %q, expected %q", tc.in, got, tc.want)
+		}
+		if r := strings.Join(removed, ", "); (tc.removed == "" && r != "") || !strings.Contains(r, tc.removed) {
+			t.Errorf("SanitizeHTML(%q) removed %q, expected %q", tc.in, r, tc.removed)
+		}
+		if again, removed := SanitizeHTML(got); again != got || len(removed) != 0 {
+			t.Errorf("expected %q to already be clean. got: %q %v", got, again, removed)
+		}
+	}
+
+	got, removed := SanitizeText(`Do you like <b>cats</b> more than 1 < 2?<script>x</script>`)
+	if got != "Do you like cats more than 1 < 2?" || len(removed) != 2 {
+		t.Errorf("expected the survey text to lose its tags. got: %q %v", got, removed)
+	}
+}
+
+func TestSanitizeOnSave(t *testing.T) {
+	env, err := createEnv(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer env.db.Close()
+	setupGlobals(env.cgo)
+
+	sqs, _ := env.cgo.GetGameQSet()
+	sqs = proto.Clone(sqs).(*qrpb.GameQSet)
+	sqs.GameQuestions[0].QuestionHtml = proto.String(`<p onclick="steal()">Find them</p><script>steal()</script>`)
+	sqs.GameQuestions[1].Hints = []string{`<img src=x onerror=steal()>`}
+	ss, _ := env.cgo.GetSurveySet()
+	ss = proto.Clone(ss).(*qrpb.SurveySet)
+	ss.SurveyQuestions[0].QuestionText = proto.String(`Do you <i>code</i>?`)
+
+	body := "gameq=" + url.QueryEscape(prototext.Format(sqs)) + "&survey=" + url.QueryEscape(prototext.Format(ss))
+	f := callController("POST", "/saveQuestions", body, nil, env.adminSaveQuestions)
+	if f.statuscode != http.StatusOK {
+		t.Fatalf("expected the questions to be saved. got: %v %v", f.statuscode, f.resptext)
+	}
+	for _, want := range []string{
+		"questions: stripped from question 1 question_html: the onclick attribute on <p>, <script> and everything in it",
+		"questions: stripped from question 2 hint 1: the onerror attribute on <img>",
+		"survey: stripped from survey question 1: <i>",
+	} {
+		if !strings.Contains(f.resptext, want) {
+			t.Errorf("expected the report to say %q. got: %v", want, f.resptext)
+		}
+	}
+
+	saved, _ := env.cgo.GetGameQSet()
+	if h := saved.GameQuestions[0].GetQuestionHtml(); h != "<p>Find them</p>" {
+		t.Errorf("expected the clean html to be saved. got: %q", h)
+	}
+	if h := saved.GameQuestions[1].Hints[0]; h != `<img src="x">` {
+		t.Errorf("expected the clean hint to be saved. got: %q", h)
+	}
+	savedSurvey, _ := env.cgo.GetSurveySet()
+	if q := savedSurvey.SurveyQuestions[0].GetQuestionText(); q != "Do you code?" {
+		t.Errorf("expected the clean survey text to be saved. got: %q", q)
+	}
+
+	// Questions from a file are checked the same way by the validator.
+	def, _ := env.CurrentGameDefinition()
+	def.Questions = sqs
+	vr := ValidateGame(def)
+	if !hasIssue(vr.Warnings, PART_QUESTIONS, "unsafe html in question 1 question_html: the onclick attribute on <p>") {
+		t.Errorf("expected a warning about the unsafe html. got: %v", vr)
+	}
+}
+
+func TestPlayerCSP(t *testing.T) {
+	resp := httptest.NewRecorder()
+	withPlayerCSP(func(w http.ResponseWriter, r *http.Request) {})(resp, httptest.NewRequest("GET", "/game", nil))
+	csp := resp.Header().Get("Content-Security-Policy")
+	if !strings.Contains(csp, "script-src 'self' https://cdn.jsdelivr.net/npm/jsqr@1/dist/jsQR.min.js;") || !strings.Contains(csp, "object-src 'none'") {
+		t.Errorf("expected the player pages to only run our own scripts. got: %q", csp)
+	}
+}
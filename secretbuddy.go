@@ -0,0 +1,217 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"math/rand"
+	"strings"
+
+	"github.com/sushovande/qr-mixer-game/qrpb"
+
+	proto "google.golang.org/protobuf/proto"
+)
+
+// BuddyFor returns the buddy assigned to the player for the given question, or nil if none was assigned yet.
+func BuddyFor(gs *qrpb.GameState, questionID int64) *qrpb.BuddyAssignment {
+	for _, b := range gs.GetBuddies() {
+		if b.GetQuestionId() == questionID {
+			return b
+		}
+	}
+	return nil
+}
+
+// StepSecretBuddy applies a scan on a SECRET_BUDDY question to the result.
+func StepSecretBuddy(result *StepResponse, sq *qrpb.GameQuestion, old *qrpb.GameState, rules AnswerRules) {
+	b := BuddyFor(old, sq.GetQuestionId())
+	if b == nil {
+		result.actionString = "Refresh the page to meet your buddy!"
+		result.actionResult = *qrpb.ActionLog_RESULT_IGNORED.Enum()
+		return
+	}
+	if b.GetUsername() == result.scannedClue {
+		AnswerCorrect(result, rules)
+		return
+	}
+	AnswerWrong(result, rules)
+}
+
+// MaybeAssignBuddy picks a secret buddy for the player if their current question is a SECRET_BUDDY
+// question and they don't have one yet. It returns true if u.State was changed.
+func (env *Env) MaybeAssignBuddy(u *StateRow) (bool, error) {
+	sqs, err := env.cgo.GetGameQSet()
+	if err != nil {
+		return false, err
+	}
+	sq := GetQuestionForPlayer(sqs, u.State)
+	if sq.GetType() != qrpb.GQType_SECRET_BUDDY || BuddyFor(u.State, sq.GetQuestionId()) != nil {
+		return false, nil
+	}
+
+	all, err := AdminGetAllUserStates(env.db)
+	if err != nil {
+		return false, err
+	}
+	candidates := make([]string, 0)
+	picks := make(map[string]int)
+	infos := make(map[string]*qrpb.GUser)
+	for _, sr := range all {
+		candidates = append(candidates, sr.Username)
+		infos[sr.Username] = sr.UserInfo
+		for _, b := range sr.State.GetBuddies() {
+			picks[b.GetUsername()]++
+		}
+	}
+
+	logs, err := GetAllLogsForUser(env.db, u.Username)
+	if err != nil {
+		return false, err
+	}
+	met := make(map[string]bool)
+	for _, lr := range logs {
+		if lr.GameLog.GetType() == qrpb.ActionLog_ACTION_CODE_SCAN {
+			met[lr.GameLog.GetClueShortName()] = true
+		}
+	}
+	for _, b := range u.State.GetBuddies() {
+		met[b.GetUsername()] = true
+	}
+
+	buddy := PickBuddy(u.Username, candidates, met, picks, env.rnd)
+	if len(buddy) == 0 {
+		return false, nil
+	}
+
+	qrm, err := env.cgo.GetQRMappings()
+	if err != nil {
+		return false, err
+	}
+	ss, err := env.cgo.GetSurveySet()
+	if err != nil {
+		return false, err
+	}
+	u.State.Buddies = append(u.State.Buddies, &qrpb.BuddyAssignment{
+		QuestionId: proto.Int64(sq.GetQuestionId()),
+		Username:   proto.String(buddy),
+		Clue:       proto.String(DescribeBuddy(infos[buddy], qrm.LookupByUsername(buddy), ss, env.rnd)),
+	})
+	return true, nil
+}
+
+// PickBuddy chooses a buddy for the player from the candidates. People the player has not met yet
+// are preferred, then people who have been picked as someone's buddy the fewest times. It returns
+// an empty string if there is nobody else to pick.
+func PickBuddy(username string, candidates []string, met map[string]bool, picks map[string]int, rnd *rand.Rand) string {
+	others := make([]string, 0)
+	fresh := make([]string, 0)
+	for _, c := range candidates {
+		if c == username {
+			continue
+		}
+		others = append(others, c)
+		if !met[c] {
+			fresh = append(fresh, c)
+		}
+	}
+	pool := fresh
+	if len(pool) == 0 {
+		pool = others
+	}
+	if len(pool) == 0 {
+		return ""
+	}
+
+	least := make([]string, 0)
+	for _, c := range pool {
+		if len(least) == 0 || picks[c] < picks[least[0]] {
+			least = []string{c}
+		} else if picks[c] == picks[least[0]] {
+			least = append(least, c)
+		}
+	}
+	return least[rnd.Intn(len(least))]
+}
+
+// DescribeBuddy writes a clue about the buddy from two of their survey answers and their badge card.
+// gu and m may be nil if the buddy has no survey answers or no badge.
+func DescribeBuddy(gu *qrpb.GUser, m *qrpb.QRMapping, ss *qrpb.SurveySet, rnd *rand.Rand) string {
+	facts := make([]string, 0)
+	answers := gu.GetSurveyAnswers()
+	for _, i := range rnd.Perm(len(answers)) {
+		if len(facts) == 2 {
+			break
+		}
+		sq := GetSurveyQuestionByIndex(ss, answers[i].GetQuestionId())
+		if sq == nil {
+			continue
+		}
+		yesno := "no"
+		if answers[i].GetIsTrue() {
+			yesno = "yes"
+		}
+		facts = append(facts, fmt.Sprintf("said %v to “%v”", yesno, sq.GetQuestionText()))
+	}
+	if card := describeCard(m); len(card) > 0 {
+		facts = append(facts, "holds "+card)
+	}
+
+	switch len(facts) {
+	case 0:
+		return "Your buddy is keeping a low profile. Ask around!"
+	case 1:
+		return fmt.Sprintf("Your buddy %v.", facts[0])
+	}
+	return fmt.Sprintf("Your buddy %v and %v.", strings.Join(facts[:len(facts)-1], ", "), facts[len(facts)-1])
+}
+
+// describeCard returns a phrase like "a red face card" for the card on the badge.
+func describeCard(m *qrpb.QRMapping) string {
+	colour := ""
+	switch m.GetCardSuit() {
+	case qrpb.CardSuit_HEARTS, qrpb.CardSuit_DIAMONDS:
+		colour = "red "
+	case qrpb.CardSuit_SPADES, qrpb.CardSuit_CLUBS:
+		colour = "black "
+	}
+	rank := m.GetCardRank()
+	switch {
+	case rank == 1 && len(colour) > 0:
+		return "a " + colour + "ace"
+	case rank == 1:
+		return "an ace"
+	case rank >= 11 && rank <= 13:
+		return "a " + colour + "face card"
+	case rank >= 2 && rank <= 10:
+		return "a " + colour + "number card"
+	case len(colour) > 0:
+		return "a " + colour + "card"
+	}
+	return ""
+}
+
+// BuddyClueHTML shows the generated buddy clue on a SECRET_BUDDY question. It returns an empty
+// string for every other question type.
+func BuddyClueHTML(sq *qrpb.GameQuestion, gs *qrpb.GameState) string {
+	if sq.GetType() != qrpb.GQType_SECRET_BUDDY {
+		return ""
+	}
+	b := BuddyFor(gs, sq.GetQuestionId())
+	if b == nil {
+		return ""
+	}
+	return fmt.Sprintf(`<p class="buddyclue">%v</p>`, template.HTMLEscapeString(b.GetClue()))
+}
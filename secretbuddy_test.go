@@ -0,0 +1,129 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sushovande/qr-mixer-game/qrpb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestPickBuddy(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	candidates := []string{"me", "a", "b", "c"}
+	met := map[string]bool{"a": true}
+	picks := map[string]int{"b": 2, "c": 1}
+	if b := PickBuddy("me", candidates, met, picks, rnd); b != "c" {
+		t.Errorf("expected the least picked person not yet met. got: %v", b)
+	}
+
+	met = map[string]bool{"a": true, "b": true, "c": true}
+	if b := PickBuddy("me", candidates, met, picks, rnd); b != "a" {
+		t.Errorf("expected the least picked person once everyone is met. got: %v", b)
+	}
+
+	if b := PickBuddy("me", []string{"me"}, nil, nil, rnd); b != "" {
+		t.Errorf("expected nobody when the player is alone. got: %v", b)
+	}
+}
+
+func TestDescribeBuddy(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	ss := &qrpb.SurveySet{SurveyQuestions: []*qrpb.SurveyQuestion{
+		{QuestionId: proto.Int64(1), QuestionText: proto.String("Do you like chocolate?")},
+	}}
+	gu := &qrpb.GUser{SurveyAnswers: []*qrpb.SurveyAnswer{
+		{QuestionId: proto.Int64(1), IsTrue: proto.Bool(true)},
+	}}
+	m := &qrpb.QRMapping{CardSuit: qrpb.CardSuit_HEARTS.Enum(), CardRank: proto.Int64(12)}
+
+	want := "Your buddy said yes to “Do you like chocolate?” and holds a red face card."
+	if got := DescribeBuddy(gu, m, ss, rnd); got != want {
+		t.Errorf("expected %q. got: %q", want, got)
+	}
+	if got := DescribeBuddy(nil, nil, ss, rnd); !strings.Contains(got, "low profile") {
+		t.Errorf("expected a fallback clue. got: %q", got)
+	}
+}
+
+func TestSecretBuddyGame(t *testing.T) {
+	env, err := createEnv(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer env.db.Close()
+	setupSynthetic(env.cgo, 4)
+	sqs, _ := env.cgo.GetGameQSet()
+	sqs.GameQuestions[1].Type = qrpb.GQType_SECRET_BUDDY.Enum()
+	sqs.GameQuestions[1].AnsUsernames = []string{}
+	env.cgo.SetGameQSet(sqs)
+
+	cookies := make([]http.Cookie, 0)
+	for i := 1; i <= 3; i++ {
+		ck := http.Cookie{Name: "sid", Value: fmt.Sprintf("cookie-%v", i), Expires: time.Now().Add(24 * 30 * time.Hour)}
+		callController("POST", "/submitsurvey", fmt.Sprintf("qr=qrcode-%v&dqans1=true&dqans2=false", i), &ck, env.submitSurvey)
+		cookies = append(cookies, ck)
+	}
+
+	// Answering Q1 moves the player onto the buddy question, which picks a buddy.
+	sqs.GameQuestions[0].AnsUsernames = []string{"username-2"}
+	env.cgo.SetGameQSet(sqs)
+	var mr MoveResponse
+	f := callController("POST", "/makemove", "answer=qrcode-2", &cookies[0], env.makeMove)
+	json.Unmarshal([]byte(f.resptext), &mr)
+	if mr.State.GetUserLevel() != 2 {
+		t.Fatalf("expected to reach the buddy question. got: %v", f.resptext)
+	}
+	b := BuddyFor(mr.State, 2)
+	if b.GetUsername() != "username-3" {
+		t.Errorf("expected the buddy to be the one person not yet met. got: %v", b)
+	}
+	if !strings.Contains(mr.PortHTML, "Your buddy said") {
+		t.Errorf("expected the clue to describe the buddy. got: %v", mr.PortHTML)
+	}
+
+	// Refreshing the page keeps the same buddy.
+	callController("GET", "/game", "", &cookies[0], env.gameHandler)
+	u1, _ := GetUserStateByUsername(env.db, "username-1")
+	if len(u1.State.GetBuddies()) != 1 || BuddyFor(u1.State, 2).GetClue() != b.GetClue() {
+		t.Errorf("expected the buddy to be kept. got: %v", u1.State.GetBuddies())
+	}
+
+	mr = MoveResponse{}
+	f = callController("POST", "/makemove", "answer=qrcode-2", &cookies[0], env.makeMove)
+	json.Unmarshal([]byte(f.resptext), &mr)
+	if mr.State.GetLife() != STARTING_LIFE-1 {
+		t.Errorf("expected the wrong person to cost a life. got: %v", f.resptext)
+	}
+	mr = MoveResponse{}
+	f = callController("POST", "/makemove", "answer=qrcode-3", &cookies[0], env.makeMove)
+	json.Unmarshal([]byte(f.resptext), &mr)
+	if mr.GameArtifacts["action"] != "Correct!" || mr.State.GetUserLevel() != 3 {
+		t.Errorf("expected the buddy to be the answer. got: %v", f.resptext)
+	}
+
+	f = callController("GET", "/allUsers", "", nil, env.adminAllUsers)
+	if !strings.Contains(f.resptext, "Q2: username-3") {
+		t.Errorf("expected admins to see the buddy. got: %v", f.resptext)
+	}
+}
@@ -0,0 +1,499 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sushovande/qr-mixer-game/qrpb"
+
+	proto "google.golang.org/protobuf/proto"
+)
+
+// SIM_SAMPLE_EVERY is how often the simulator counts who is needed by whom.
+const SIM_SAMPLE_EVERY = time.Minute
+
+// SIM_BUCKET is the width of the bars in the completion-time histogram.
+const SIM_BUCKET = 15 * time.Minute
+
+// SimOptions is how the bots in a simulated game behave.
+type SimOptions struct {
+	// Players is how many bots play. They are the first players on the roster.
+	Players int
+	// Accuracy is the chance that a bot scans someone who is a right answer.
+	Accuracy float64
+	// ScanInterval is the average time between two scans by the same bot.
+	ScanInterval time.Duration
+	// SurveyYes is the chance that a bot answers yes to each survey question.
+	SurveyYes float64
+	// MaxDuration is when the simulated game stops, even if bots are still playing.
+	MaxDuration time.Duration
+	Seed        int64
+}
+
+// SimCongestion is a player that many bots needed to scan at the same time.
+type SimCongestion struct {
+	Username string
+	Peak     int
+	At       time.Duration
+}
+
+// SimReport is the outcome of a simulated game.
+type SimReport struct {
+	Options SimOptions
+	// Finished has how long each bot that won took, fastest first.
+	Finished []time.Duration
+	// DeathsByLevel counts the bots that ran out of lives on each level.
+	DeathsByLevel map[int64]int
+	// TokensAtEndgame counts the bots by how many tokens they held on reaching the metal grab.
+	TokensAtEndgame map[int]int
+	// StillPlaying counts the bots that were neither dead nor finished when time ran out, by level.
+	StillPlaying map[int64]int
+	// Congestion lists the players that were needed by the most bots at once, most needed first.
+	Congestion []SimCongestion
+}
+
+// simBot is a simulated player.
+type simBot struct {
+	sr     *StateRow
+	qrcode string
+	nextAt time.Duration
+}
+
+// newSimEnv returns an Env with a fresh in-memory database holding the game. Badge scans are always
+// accepted, since bots have no phones to show rotating codes on.
+func newSimEnv(def GameDefinition) (*Env, error) {
+	db, err := DbInit(":memory:")
+	if err != nil {
+		return nil, err
+	}
+	env := &Env{db: db, cgo: CreateCachedGameOptions(db)}
+	gc := proto.Clone(def.Config).(*qrpb.GameConfig)
+	gc.BadgeCodes = nil
+	qrm := &QRMappings{mappings: def.Roster}
+	qrm.RefreshMappings()
+	if err := env.cgo.SetGameQSet(def.Questions); err != nil {
+		return nil, err
+	}
+	if err := env.cgo.SetSurveySet(def.Survey); err != nil {
+		return nil, err
+	}
+	if err := env.cgo.SetGameConfig(gc); err != nil {
+		return nil, err
+	}
+	if err := env.cgo.SetQRMappings(qrm); err != nil {
+		return nil, err
+	}
+	return env, nil
+}
+
+// Simulate plays a treasure hunt with bots and reports how it went. It registers the bots in env's
+// database, so env should be a fresh one from newSimEnv.
+func (env *Env) Simulate(opts SimOptions) (*SimReport, error) {
+	mode, err := env.CurrentGameMode()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := mode.(huntMode); !ok {
+		return nil, fmt.Errorf("only the treasure hunt can be simulated")
+	}
+	qrm, err := env.cgo.GetQRMappings()
+	if err != nil {
+		return nil, err
+	}
+	ss, err := env.cgo.GetSurveySet()
+	if err != nil {
+		return nil, err
+	}
+	roster := qrm.mappings.GetQrMappings()
+	if opts.Players < 1 || opts.Players > len(roster) {
+		return nil, fmt.Errorf("can simulate 1 to %v players, the size of the roster, not %v", len(roster), opts.Players)
+	}
+	if opts.ScanInterval <= 0 {
+		return nil, fmt.Errorf("the time between scans must be positive, got %v", opts.ScanInterval)
+	}
+
+	// The game logic draws from the same seeded source, so runs can be repeated.
+	rnd := rand.New(rand.NewSource(opts.Seed))
+	env.rnd = rnd
+	wait := func() time.Duration {
+		return time.Duration(rnd.ExpFloat64() * float64(opts.ScanInterval))
+	}
+
+	bots := make([]*simBot, 0, opts.Players)
+	for i, m := range roster[:opts.Players] {
+		gu := &qrpb.GUser{
+			Username: proto.String(m.GetUsername()),
+			Name:     proto.String(m.GetDisplayName()),
+		}
+		for _, sq := range ss.GetSurveyQuestions() {
+			gu.SurveyAnswers = append(gu.SurveyAnswers, &qrpb.SurveyAnswer{
+				QuestionId: proto.Int64(sq.GetQuestionId()),
+				IsTrue:     proto.Bool(rnd.Float64() < opts.SurveyYes),
+			})
+		}
+		gs, err := mode.InitialState(env, m.GetUsername())
+		if err != nil {
+			return nil, err
+		}
+		sr := &StateRow{Cookie: fmt.Sprintf("simulated-player-%v", i), Username: m.GetUsername(), UserInfo: gu, State: gs}
+		if err := AddUser(env.db, sr); err != nil {
+			return nil, err
+		}
+		bots = append(bots, &simBot{sr: sr, qrcode: m.GetQrcode(), nextAt: wait()})
+	}
+	// Prepare once everyone is registered, so secret buddies can be anyone.
+	for _, b := range bots {
+		if err := env.simPrepare(mode, b.sr); err != nil {
+			return nil, err
+		}
+	}
+
+	report := &SimReport{
+		Options:         opts,
+		DeathsByLevel:   make(map[int64]int),
+		TokensAtEndgame: make(map[int]int),
+		StillPlaying:    make(map[int64]int),
+	}
+	peaks := make(map[string]*SimCongestion)
+	nextSample := time.Duration(0)
+	for {
+		var b *simBot
+		for _, c := range bots {
+			if !HasWon(c.sr.State) && !IsDead(c.sr.State) && (b == nil || c.nextAt < b.nextAt) {
+				b = c
+			}
+		}
+		if b == nil || b.nextAt > opts.MaxDuration {
+			break
+		}
+		for ; nextSample <= b.nextAt; nextSample += SIM_SAMPLE_EVERY {
+			if err := env.sampleCongestion(bots, peaks, nextSample); err != nil {
+				return nil, err
+			}
+		}
+		if err := env.simMove(mode, b, bots, opts.Accuracy, rnd, report); err != nil {
+			return nil, err
+		}
+		if HasWon(b.sr.State) {
+			report.Finished = append(report.Finished, b.nextAt)
+		}
+		b.nextAt += wait()
+	}
+
+	for _, b := range bots {
+		if !HasWon(b.sr.State) && !IsDead(b.sr.State) {
+			report.StillPlaying[b.sr.State.GetUserLevel()]++
+		}
+	}
+	for _, c := range peaks {
+		report.Congestion = append(report.Congestion, *c)
+	}
+	sort.Slice(report.Congestion, func(i, j int) bool {
+		a, b := report.Congestion[i], report.Congestion[j]
+		if a.Peak != b.Peak {
+			return a.Peak > b.Peak
+		}
+		return a.Username < b.Username
+	})
+	return report, nil
+}
+
+// simPrepare runs the game mode's preparation for a bot and saves it, like a page load would.
+func (env *Env) simPrepare(mode GameMode, sr *StateRow) error {
+	return env.prepareAndSave(mode, sr)
+}
+
+// simTargets returns the players who are a right answer for the bot now. specific is false when
+// the answer is typed, a photo, or anyone at all, since those do not crowd around one person.
+func (env *Env) simTargets(b *simBot, bots []*simBot) (targets []string, sq *qrpb.GameQuestion, specific bool, err error) {
+	gs := b.sr.State
+	if gs.GetUserLevel() == 20 {
+		for _, o := range bots {
+			if o != b && (o.sr.State.GetHasAl() && !gs.GetHasAl() || o.sr.State.GetHasCu() && !gs.GetHasCu() ||
+				o.sr.State.GetHasSn() && !gs.GetHasSn() || o.sr.State.GetHasZn() && !gs.GetHasZn()) {
+				targets = append(targets, o.sr.Username)
+			}
+		}
+		return targets, nil, true, nil
+	}
+
+	sqs, err := env.cgo.GetGameQSet()
+	if err != nil {
+		return nil, nil, false, err
+	}
+	sq = GetQuestionForPlayer(sqs, gs)
+	switch sq.GetType() {
+	case qrpb.GQType_USERNAME_LIST:
+		return sq.GetAnsUsernames(), sq, true, nil
+	case qrpb.GQType_SURVEY_ANS:
+		for _, o := range bots {
+			if getSurveyResponse(o.sr.UserInfo, sq.GetSurveyId()) == sq.GetSurveyTrueIsCorrect() {
+				targets = append(targets, o.sr.Username)
+			}
+		}
+		return targets, sq, true, nil
+	case qrpb.GQType_CHECKPOINT_ROUTE:
+		if p := gs.GetRouteProgress(); p < int64(len(sq.GetRouteUsernames())) {
+			targets = append(targets, sq.GetRouteUsernames()[p])
+		}
+		return targets, sq, true, nil
+	case qrpb.GQType_SECRET_BUDDY:
+		if buddy := BuddyFor(gs, sq.GetQuestionId()); buddy != nil {
+			targets = append(targets, buddy.GetUsername())
+		}
+		return targets, sq, true, nil
+	case qrpb.GQType_ANY_PERSON:
+		for _, o := range bots {
+			if o != b {
+				targets = append(targets, o.sr.Username)
+			}
+		}
+	}
+	return targets, sq, false, nil
+}
+
+// simMove makes one move for the bot: a right answer with the given accuracy, and a wrong one
+// otherwise.
+func (env *Env) simMove(mode GameMode, b *simBot, bots []*simBot, accuracy float64, rnd *rand.Rand, report *SimReport) error {
+	targets, sq, _, err := env.simTargets(b, bots)
+	if err != nil {
+		return err
+	}
+	right := rnd.Float64() < accuracy
+	old := b.sr.State
+
+	var result StepResponse
+	switch {
+	case sq != nil && sq.GetType() == qrpb.GQType_TEXT_ANSWER:
+		answer := "not the answer"
+		if right && len(sq.GetTextAnswers()) > 0 {
+			answer = sq.GetTextAnswers()[0]
+		}
+		result, err = env.StepTextWithHandicap(old, answer, nil)
+	case sq != nil && sq.GetType() == qrpb.GQType_PHOTO_PROOF:
+		result, err = env.StepPhotoReview(old, right, nil)
+	default:
+		qrm, qerr := env.cgo.GetQRMappings()
+		if qerr != nil {
+			return qerr
+		}
+		result, err = env.Step(old, qrm.LookupByUsername(pickScan(b, bots, targets, right, rnd)).GetQrcode())
+	}
+	if errors.Is(err, errNotRegistered) {
+		// A real player would see an error and scan someone else.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	b.sr.State = result.newState
+	if err := UpdateUserDetails(env.db, b.sr); err != nil {
+		return err
+	}
+	if err := env.simPrepare(mode, b.sr); err != nil {
+		return err
+	}
+	if IsDead(b.sr.State) && !IsDead(old) {
+		report.DeathsByLevel[old.GetUserLevel()]++
+	}
+	if b.sr.State.GetUserLevel() == 20 && old.GetUserLevel() != 20 {
+		report.TokensAtEndgame[tokenCount(b.sr.State)]++
+	}
+	return nil
+}
+
+// pickScan returns the username the bot scans: one of the targets if it gets this one right, and
+// any other registered player if not.
+func pickScan(b *simBot, bots []*simBot, targets []string, right bool, rnd *rand.Rand) string {
+	if right && len(targets) > 0 {
+		return targets[rnd.Intn(len(targets))]
+	}
+	wrong := make([]string, 0)
+	for _, o := range bots {
+		if o != b && !ListHasString(targets, o.sr.Username) {
+			wrong = append(wrong, o.sr.Username)
+		}
+	}
+	if len(wrong) == 0 {
+		return bots[rnd.Intn(len(bots))].sr.Username
+	}
+	return wrong[rnd.Intn(len(wrong))]
+}
+
+// sampleCongestion counts, for each player, how many bots need to scan them right now, and keeps
+// the highest count seen.
+func (env *Env) sampleCongestion(bots []*simBot, peaks map[string]*SimCongestion, at time.Duration) error {
+	needed := make(map[string]int)
+	for _, b := range bots {
+		if HasWon(b.sr.State) || IsDead(b.sr.State) {
+			continue
+		}
+		targets, _, specific, err := env.simTargets(b, bots)
+		if err != nil {
+			return err
+		}
+		if !specific {
+			continue
+		}
+		for _, t := range targets {
+			needed[t]++
+		}
+	}
+	for u, n := range needed {
+		if p, ok := peaks[u]; !ok || n > p.Peak {
+			peaks[u] = &SimCongestion{Username: u, Peak: n, At: at}
+		}
+	}
+	return nil
+}
+
+func tokenCount(gs *qrpb.GameState) int {
+	n := 0
+	for _, has := range []bool{gs.GetHasAl(), gs.GetHasCu(), gs.GetHasSn(), gs.GetHasZn()} {
+		if has {
+			n++
+		}
+	}
+	return n
+}
+
+// percentile returns the duration below which the given fraction of the sorted ds fall.
+func percentile(ds []time.Duration, f float64) time.Duration {
+	return ds[int(f*float64(len(ds)-1))]
+}
+
+func (sr *SimReport) String() string {
+	var b strings.Builder
+	o := sr.Options
+	fmt.Fprintf(&b, "Simulated %v players for up to %v: %.0f%% of scans right, one every %v on average.\n\n",
+		o.Players, o.MaxDuration, o.Accuracy*100, o.ScanInterval)
+
+	fmt.Fprintf(&b, "Completion times (%v of %v finished):\n", len(sr.Finished), o.Players)
+	if len(sr.Finished) > 0 {
+		fmt.Fprintf(&b, "  fastest %v, median %v, 90th percentile %v, slowest %v\n",
+			sr.Finished[0].Round(time.Minute), percentile(sr.Finished, 0.5).Round(time.Minute),
+			percentile(sr.Finished, 0.9).Round(time.Minute), sr.Finished[len(sr.Finished)-1].Round(time.Minute))
+		buckets := make(map[int]int)
+		last := 0
+		for _, d := range sr.Finished {
+			i := int(d / SIM_BUCKET)
+			buckets[i]++
+			last = i
+		}
+		for i := int(sr.Finished[0] / SIM_BUCKET); i <= last; i++ {
+			fmt.Fprintf(&b, "  %8v  %-20s %v\n", time.Duration(i)*SIM_BUCKET, strings.Repeat("#", buckets[i]*20/len(sr.Finished)), buckets[i])
+		}
+	}
+
+	fmt.Fprintf(&b, "\nDeaths by level:\n")
+	writeLevelCounts(&b, sr.DeathsByLevel)
+
+	fmt.Fprintf(&b, "\nTokens on reaching the metal grab (level 20):\n")
+	if len(sr.TokensAtEndgame) == 0 {
+		fmt.Fprintf(&b, "  nobody got there\n")
+	}
+	for n := 0; n <= 4; n++ {
+		if c, ok := sr.TokensAtEndgame[n]; ok {
+			fmt.Fprintf(&b, "  %v tokens: %v players\n", n, c)
+		}
+	}
+
+	fmt.Fprintf(&b, "\nStill playing when time ran out:\n")
+	writeLevelCounts(&b, sr.StillPlaying)
+
+	fmt.Fprintf(&b, "\nMost needed players (bots that needed them at the same time):\n")
+	if len(sr.Congestion) == 0 {
+		fmt.Fprintf(&b, "  none\n")
+	}
+	for i, c := range sr.Congestion {
+		if i == 10 {
+			break
+		}
+		fmt.Fprintf(&b, "  %v: %v at %v\n", c.Username, c.Peak, c.At)
+	}
+	return b.String()
+}
+
+func writeLevelCounts(b *strings.Builder, counts map[int64]int) {
+	if len(counts) == 0 {
+		fmt.Fprintf(b, "  none\n")
+		return
+	}
+	levels := make([]int64, 0, len(counts))
+	for l := range counts {
+		levels = append(levels, l)
+	}
+	sort.Slice(levels, func(i, j int) bool { return levels[i] < levels[j] })
+	for _, l := range levels {
+		fmt.Fprintf(b, "  level %v: %v players\n", l, counts[l])
+	}
+}
+
+// runSimulate is the simulate subcommand. It plays the game with bots and prints the SimReport.
+func runSimulate(args []string, out io.Writer) int {
+	fs := flag.NewFlagSet("simulate", flag.ContinueOnError)
+	fs.SetOutput(out)
+	gf := addGameFlags(fs, "simulate")
+	players := fs.Int("players", 0, "how many players to simulate, from the top of the roster (default: everyone)")
+	accuracy := fs.Float64("accuracy", 0.8, "the chance that a scan is of a right answer")
+	interval := fs.Duration("scan-interval", 90*time.Second, "the average time between two scans by one player")
+	yes := fs.Float64("survey-yes", 0.5, "the chance that a player answers yes to each survey question")
+	maxDuration := fs.Duration("max-duration", 4*time.Hour, "when to stop the game")
+	seed := fs.Int64("seed", 1, "the random seed, so runs can be repeated")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	def, ok := gf.load(out)
+	if !ok {
+		return 2
+	}
+	if vr := ValidateGame(def); len(vr.Errors) > 0 {
+		fmt.Fprintf(out, "warning: the game has %v errors, run validate to see them\n", len(vr.Errors))
+	}
+
+	env, err := newSimEnv(def)
+	if err != nil {
+		fmt.Fprintf(out, "could not set up the simulation: %v\n", err)
+		return 2
+	}
+	defer env.db.Close()
+	opts := SimOptions{
+		Players:      *players,
+		Accuracy:     *accuracy,
+		ScanInterval: *interval,
+		SurveyYes:    *yes,
+		MaxDuration:  *maxDuration,
+		Seed:         *seed,
+	}
+	if opts.Players == 0 {
+		opts.Players = len(def.Roster.GetQrMappings())
+	}
+	report, err := env.Simulate(opts)
+	if err != nil {
+		fmt.Fprintf(out, "could not simulate the game: %v\n", err)
+		return 2
+	}
+	fmt.Fprint(out, report)
+	return 0
+}
@@ -0,0 +1,129 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/encoding/prototext"
+)
+
+func simulateSynthetic(t *testing.T, opts SimOptions) *SimReport {
+	env, err := createEnv(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer env.db.Close()
+	setupGlobals(env.cgo)
+	def, err := env.CurrentGameDefinition()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim, err := newSimEnv(def)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sim.db.Close()
+	report, err := sim.Simulate(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return report
+}
+
+func TestSimulate(t *testing.T) {
+	opts := SimOptions{Players: 30, Accuracy: 1, ScanInterval: time.Minute, SurveyYes: 0.5, MaxDuration: 4 * time.Hour, Seed: 3}
+	report := simulateSynthetic(t, opts)
+	if len(report.DeathsByLevel) != 0 {
+		t.Errorf("expected perfect players not to die. got: %v", report.DeathsByLevel)
+	}
+	reached := 0
+	for _, n := range report.TokensAtEndgame {
+		reached += n
+	}
+	if reached != 30 {
+		t.Errorf("expected every perfect player to reach the metal grab. got: %v", report.TokensAtEndgame)
+	}
+	stuck := 0
+	for _, n := range report.StillPlaying {
+		stuck += n
+	}
+	if len(report.Finished)+stuck != 30 {
+		t.Errorf("expected every player to finish or still be playing. got: %v finished, %v playing", len(report.Finished), stuck)
+	}
+	for i := 1; i < len(report.Finished); i++ {
+		if report.Finished[i] < report.Finished[i-1] {
+			t.Errorf("expected completion times in order. got: %v", report.Finished)
+		}
+	}
+	if len(report.Congestion) == 0 || report.Congestion[0].Peak < 2 {
+		t.Errorf("expected some players to be needed by several bots at once. got: %v", report.Congestion)
+	}
+
+	again := simulateSynthetic(t, opts)
+	if len(again.Finished) != len(report.Finished) || (len(again.Finished) > 0 && again.Finished[0] != report.Finished[0]) {
+		t.Errorf("expected the same seed to give the same game. got: %v and %v", report.Finished, again.Finished)
+	}
+
+	opts.Accuracy = 0
+	report = simulateSynthetic(t, opts)
+	if report.DeathsByLevel[1] != 30 || len(report.Finished) != 0 {
+		t.Errorf("expected players who never get it right to die on the first question. got: %v", report)
+	}
+}
+
+func TestSimulateCommand(t *testing.T) {
+	env, err := createEnv(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer env.db.Close()
+	setupGlobals(env.cgo)
+	def, _ := env.CurrentGameDefinition()
+	dir := t.TempDir()
+	write := func(name string, b []byte) string {
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, b, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	var out bytes.Buffer
+	code := runSimulate([]string{"-db", filepath.Join(dir, "datastore.db"), "-players", "10",
+		"-questions", write("q.textproto", []byte(prototext.Format(def.Questions))),
+		"-survey", write("s.textproto", []byte(prototext.Format(def.Survey))),
+		"-config", write("c.textproto", []byte(prototext.Format(def.Config))),
+		"-roster", write("r.textproto", []byte(prototext.Format(def.Roster)))}, &out)
+	if code != 0 {
+		t.Fatalf("expected the simulation to run. got: %v %v", code, out.String())
+	}
+	for _, want := range []string{"Simulated 10 players", "Completion times", "Deaths by level", "Tokens on reaching the metal grab", "Most needed players"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected %q in the report. got: %v", want, out.String())
+		}
+	}
+
+	out.Reset()
+	if code := runSimulate([]string{"-db", filepath.Join(dir, "datastore.db"), "-players", "1000",
+		"-questions", filepath.Join(dir, "q.textproto"), "-survey", filepath.Join(dir, "s.textproto"),
+		"-config", filepath.Join(dir, "c.textproto"), "-roster", filepath.Join(dir, "r.textproto")}, &out); code != 2 {
+		t.Errorf("expected an error for more players than the roster. got: %v %v", code, out.String())
+	}
+}
@@ -88,9 +88,11 @@ func NewMoveResponse() MoveResponse {
 type StepResponse struct {
 	newState     *qrpb.GameState
 	actionString string
-	levelClue    string
 	scannedClue  string
 	actionResult qrpb.ActionLog_ActionResult
+	// applyTx, if set, makes the step's changes outside the player's own row. It runs in the
+	// same transaction that saves the move.
+	applyTx func(tx *sql.Tx) error
 }
 
 func NewStepResponse() StepResponse {
@@ -246,7 +248,7 @@ func AddUser(db *sql.DB, sr *StateRow) error {
 }
 
 // UpdateUserDetails updates the info associated with the sr.Sub passed in
-func UpdateUserDetails(db *sql.DB, sr *StateRow) error {
+func UpdateUserDetails(db queryer, sr *StateRow) error {
 	const updStmt = `UPDATE userstate SET username=?, userinfo=?, state=? WHERE cookie=?`
 	userinfo, err := proto.Marshal(sr.UserInfo)
 	if err != nil {
@@ -282,6 +284,66 @@ func UpdateUserDetailsWithProto(db *sql.DB, info *qrpb.GUser, state *qrpb.GameSt
 	return err
 }
 
+// PATCH_STATE_RETRIES is how many times PatchUserState re-reads a player whose state keeps changing
+// under it before giving up.
+const PATCH_STATE_RETRIES = 5
+
+// PatchUserState changes part of a player's saved state without losing a move saved at the same
+// time. It reads the state, lets f change it, and only writes it back if the row still holds what
+// was read, starting over otherwise. f returns false to leave the player alone. It returns whether
+// the state was written.
+func PatchUserState(db *sql.DB, username string, f func(gs *qrpb.GameState) bool) (bool, error) {
+	const getStmt = `SELECT state FROM userstate WHERE username=?`
+	const updStmt = `UPDATE userstate SET state=? WHERE username=? AND state=?`
+	for i := 0; i < PATCH_STATE_RETRIES; i++ {
+		var old []byte
+		err := db.QueryRow(getStmt, username).Scan(&old)
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		gs := &qrpb.GameState{}
+		if err := proto.Unmarshal(old, gs); err != nil {
+			return false, err
+		}
+		if !f(gs) {
+			return false, nil
+		}
+		b, err := proto.Marshal(gs)
+		if err != nil {
+			return false, err
+		}
+		res, err := db.Exec(updStmt, b, username, old)
+		if err != nil {
+			return false, err
+		}
+		if n, err := res.RowsAffected(); err != nil || n > 0 {
+			return err == nil, err
+		}
+	}
+	return false, fmt.Errorf("the state of %v kept changing, try again", username)
+}
+
+// CopyChangedFields sets every field of dst that differs between before and after to its value in
+// after, so the changes made to one copy of a state can be applied to another.
+func CopyChangedFields(dst, before, after *qrpb.GameState) {
+	d, b, a := dst.ProtoReflect(), before.ProtoReflect(), after.ProtoReflect()
+	fields := a.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if b.Has(fd) == a.Has(fd) && (!a.Has(fd) || b.Get(fd).Equal(a.Get(fd))) {
+			continue
+		}
+		if a.Has(fd) {
+			d.Set(fd, a.Get(fd))
+		} else {
+			d.Clear(fd)
+		}
+	}
+}
+
 // DeleteCookieEntry removes a cookie
 func DeleteCookieEntry(db *sql.DB, sr *StateRow) error {
 	const delCookie = `DELETE FROM userstate WHERE cookie=?`
@@ -296,7 +358,7 @@ func DeleteCookieEntry(db *sql.DB, sr *StateRow) error {
 }
 
 // AddActionLog records a new gamelog for a user
-func AddActionLog(db *sql.DB, lr *LogRow) error {
+func AddActionLog(db queryer, lr *LogRow) error {
 	const insData = `INSERT INTO gamelogs VALUES(?,?,?)`
 	gamelog, err := proto.Marshal(lr.GameLog)
 	if err != nil {
@@ -357,6 +419,30 @@ func AdminGetAllUserStates(db *sql.DB) ([]StateRow, error) {
 	return reply, nil
 }
 
+// GetAllActionLogs gets every log in the database, oldest first.
+func GetAllActionLogs(db *sql.DB) ([]LogRow, error) {
+	const getData = `SELECT username, updated, gamelog FROM gamelogs ORDER BY updated`
+	rows, err := db.Query(getData)
+	if err != nil {
+		log.Printf("Db-err: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+	reply := make([]LogRow, 0)
+	for rows.Next() {
+		var s nullableLogRow
+		if err = rows.Scan(&s.Username, &s.Updated, &s.GameLog); err != nil {
+			return nil, err
+		}
+		sr, err := s.toLogRow()
+		if err != nil {
+			return nil, err
+		}
+		reply = append(reply, *sr)
+	}
+	return reply, nil
+}
+
 // AdminGetAllUserLogs gets an admin view of all the logs
 func AdminGetAllUserLogs(db *sql.DB) ([]LogRow, error) {
 	const getData = `SELECT username, updated, gamelog FROM gamelogs ORDER BY updated DESC LIMIT 1000`
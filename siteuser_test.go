@@ -123,6 +123,37 @@ func TestUpdateUserDetails(t *testing.T) {
 	}
 }
 
+func TestPatchUserState(t *testing.T) {
+	db, _ := DbInit(":memory:")
+	defer db.Close()
+	sr := GetStateRow()
+	AddUser(db, &sr)
+
+	// A move saved while the patch is being worked out is kept, and the patch is redone on top.
+	calls := 0
+	ok, err := PatchUserState(db, sr.Username, func(gs *qrpb.GameState) bool {
+		calls++
+		if calls == 1 {
+			moved := proto.Clone(gs).(*qrpb.GameState)
+			moved.UserLevel = proto.Int64(gs.GetUserLevel() + 1)
+			UpdateUserDetailsWithProto(db, sr.UserInfo, moved)
+		}
+		gs.HintLevel = proto.Int64(1)
+		return true
+	})
+	if err != nil || !ok {
+		t.Fatalf("expected the patch to be saved. got: %v %v", ok, err)
+	}
+	got, _ := GetUserStateByCookie(db, sr.Cookie)
+	if calls != 2 || got.State.GetUserLevel() != sr.State.GetUserLevel()+1 || got.State.GetHintLevel() != 1 {
+		t.Errorf("expected both the move and the patch to be saved. got: %v calls, %v", calls, got.State)
+	}
+
+	if ok, err := PatchUserState(db, sr.Username, func(gs *qrpb.GameState) bool { return false }); ok || err != nil {
+		t.Errorf("expected a declined patch not to be saved. got: %v %v", ok, err)
+	}
+}
+
 func TestDeleteCookieEntry(t *testing.T) {
 	db, _ := DbInit(":memory:")
 	defer db.Close()
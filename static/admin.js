@@ -278,6 +278,11 @@ function generateConsolidatedObject(headers, words) {
   let qrCodeIndex = -1;
   let cardSuiteIndex = -1;
   let cardRankIndex = -1;
+  let extraLivesIndex = -1;
+  let noPenaltyIndex = -1;
+  let helperIndex = -1;
+  let helperScansIndex = -1;
+  let digitalIndex = -1;
   for (let i = 0; i < headers.length; i++) {
     if (headers[i].toLowerCase() === 'name') {
       nameIndex = i;
@@ -289,6 +294,16 @@ function generateConsolidatedObject(headers, words) {
       cardSuiteIndex = i;
     } else if (headers[i].toLowerCase() === 'cardrank') {
       cardRankIndex = i;
+    } else if (headers[i].toLowerCase() === 'extralives') {
+      extraLivesIndex = i;
+    } else if (headers[i].toLowerCase() === 'nopenalty') {
+      noPenaltyIndex = i;
+    } else if (headers[i].toLowerCase() === 'helper') {
+      helperIndex = i;
+    } else if (headers[i].toLowerCase() === 'helperscans') {
+      helperScansIndex = i;
+    } else if (headers[i].toLowerCase() === 'digital') {
+      digitalIndex = i;
     }
   }
 
@@ -306,8 +321,52 @@ function generateConsolidatedObject(headers, words) {
     po["qrcode"] = pl[qrCodeIndex];
     po["card_suit"] = pl[cardSuiteIndex];
     po["card_rank"] = pl[cardRankIndex];
+    const handicap = generateHandicap(pl, extraLivesIndex, noPenaltyIndex, helperIndex, helperScansIndex);
+    if (handicap != null) {
+      po["handicap"] = handicap;
+    }
+    if (digitalIndex in pl && isYes(pl[digitalIndex])) {
+      po["digital_only"] = true;
+    }
     obj.qr_mappings.push(po);
   }
 
   return obj;
+}
+
+/**
+ * @param {string} cell - the text of a yes/no column.
+ * @returns {boolean} true if the cell says yes.
+ */
+function isYes(cell) {
+  return ['true', 'yes', 'y', '1'].includes(cell.trim().toLowerCase());
+}
+
+/**
+ * generates a PlayerHandicap object from the optional handicap columns of a row.
+ * @param {Array<string>} pl - the cells of the row.
+ * @param {number} extraLivesIndex - column of extralives, or -1.
+ * @param {number} noPenaltyIndex - column of nopenalty, or -1.
+ * @param {number} helperIndex - column of helper, or -1.
+ * @param {number} helperScansIndex - column of helperscans, or -1.
+ * @returns {Object} of type PlayerHandicap from gamedata.proto, or null if the player has none.
+ */
+function generateHandicap(pl, extraLivesIndex, noPenaltyIndex, helperIndex, helperScansIndex) {
+  const cell = (i) => (i in pl) ? pl[i].trim() : '';
+  let h = {};
+  const extraLives = parseInt(cell(extraLivesIndex), 10);
+  if (extraLives) {
+    h["extra_lives"] = extraLives;
+  }
+  if (isYes(cell(noPenaltyIndex))) {
+    h["no_penalty"] = true;
+  }
+  if (cell(helperIndex) !== '') {
+    h["helper"] = cell(helperIndex);
+  }
+  const helperScans = parseInt(cell(helperScansIndex), 10);
+  if (helperScans) {
+    h["helper_scans"] = helperScans;
+  }
+  return Object.keys(h).length > 0 ? h : null;
 }
\ No newline at end of file
@@ -16,6 +16,16 @@
 
 let SITE_URL_PREFIX = "https://" + window.location.hostname + "/";
 
+// The page says where to send moves on the script tag, since inline scripts are blocked by the
+// Content-Security-Policy.
+const GameScript = document.currentScript;
+var PostEndpoint = GameScript.dataset.postEndpoint;
+var TextAnswerEndpoint = GameScript.dataset.textAnswerEndpoint;
+var PhotoEndpoint = GameScript.dataset.photoEndpoint;
+var PokerSwapEndpoint = GameScript.dataset.pokerSwapEndpoint;
+var ClueEndpoint = GameScript.dataset.clueEndpoint;
+var HelpScanEndpoint = GameScript.dataset.helpScanEndpoint;
+
 function tabchange() {
     if (document.getElementById("tab-1").checked) {
         stopCamera();
@@ -81,12 +91,13 @@ function flash_scan_foreign() {
 function flash_action_msg(str) {
     const sc = document.getElementById("actionlog");
     sc.className = "msgvisible";
-    sc.innerHTML = str
+    sc.textContent = str
     window.setTimeout(function () { sc.className = "msghidden"; }, 2000);
 }
 
 function set_life(gs) {
-    for (let i = 1; i <= 5; i++) {
+    // The page has as many hearts as the game config asks for.
+    for (let i = 1; document.getElementById("heart" + i); i++) {
         if (gs.life >= i) {
             document.getElementById("heart" + i).classList.replace("heart-dead", "heart-active");
         } else {
@@ -116,6 +127,18 @@ function set_life(gs) {
     }
 }
 
+function show_answer_form(id, show) {
+    const el = document.getElementById(id);
+    if (!el) {
+        return;
+    }
+    if (show) {
+        el.classList.replace("hidden", "visible");
+    } else {
+        el.classList.replace("visible", "hidden");
+    }
+}
+
 function processResponse(data) {
     console.log(data);
     if (data.hasOwnProperty("GameArtifacts") && data.GameArtifacts.hasOwnProperty("redirectUrl")) {
@@ -125,14 +148,26 @@ function processResponse(data) {
     if (data.hasOwnProperty("PortHTML")) {
         document.getElementById("cluecontent").innerHTML = data["PortHTML"];
     } else {
-        document.getElementById("errormsg").innerHTML = "did not get any clue content as a result";
+        document.getElementById("errormsg").textContent = "did not get any clue content as a result";
+    }
+    let answerType = "";
+    if (data.hasOwnProperty("GameArtifacts") && data.GameArtifacts.hasOwnProperty("answerType")) {
+        answerType = data.GameArtifacts["answerType"];
+    }
+    show_answer_form("textanswerform", answerType == "text");
+    show_answer_form("photoanswerform", answerType == "photo");
+    show_answer_form("photopending", answerType == "photopending");
+    if (data.hasOwnProperty("GameArtifacts") && data.GameArtifacts.hasOwnProperty("events")) {
+        const eb = document.getElementById("eventbanner");
+        eb.textContent = data.GameArtifacts["events"];
+        show_answer_form("eventbanner", data.GameArtifacts["events"].length > 0);
     }
     if (data.hasOwnProperty("GameArtifacts")) {
         if (data["GameArtifacts"].hasOwnProperty("action")) {
             const msf = data["GameArtifacts"]["action"];
             flash_action_msg(msf);
             // On a correct clue, or on game over, we switch to the first tab
-            if (msf == "Correct!" || msf == "Dead!" || msf == "Grabbed Metal!") {
+            if (msf == "Correct!" || msf == "Skipped!" || msf == "Dead!" || msf == "Grabbed Metal!" || msf == "Square Filled!" || msf == "Bingo!" || msf == "Tagged!" || msf == "Card Collected!" || msf == "Swap a Card?") {
                 window.setTimeout(function () {
                     document.getElementById("tab-1").checked = "checked";
                     // programmatically triggering the input does not trigger the event,
@@ -157,7 +192,14 @@ async function cluescansim_click() {
     if (scanned.length > 0) {
         flash_scan_success();
         const postData = new URLSearchParams({ "answer": scanned });
-        fetch(PostEndpoint, { method: 'post', body: postData })
+        // A helper can scan for a player they help; the scan then goes to that player's game.
+        const scanFor = document.getElementById("scanfor");
+        let endpoint = PostEndpoint;
+        if (scanFor && scanFor.value.length > 0) {
+            postData.append("for", scanFor.value);
+            endpoint = HelpScanEndpoint;
+        }
+        fetch(endpoint, { method: 'post', body: postData })
             .then(response => {
                 if (!response.ok) {
                     response.text().then(p => { document.getElementById('errormsg').textContent = 'Error: ' + p });
@@ -170,6 +212,58 @@ async function cluescansim_click() {
     }
 }
 
+function submitTextAnswer(e) {
+    e.preventDefault();
+    const ta = document.getElementById("textanswer");
+    if (ta.value.trim().length <= 0) {
+        return;
+    }
+    const postData = new URLSearchParams({ "answer": ta.value });
+    fetch(TextAnswerEndpoint, { method: 'post', body: postData })
+        .then(response => {
+            if (!response.ok) {
+                response.text().then(p => { document.getElementById('errormsg').textContent = 'Error: ' + p });
+            } else {
+                ta.value = "";
+                response.json().then(p => {
+                    processResponse(p);
+                    // the action log lives on the scan tab, so also show the result next to the form.
+                    if (p.hasOwnProperty("GameArtifacts") && p.GameArtifacts.hasOwnProperty("action")) {
+                        document.getElementById('errormsg').textContent = p.GameArtifacts["action"];
+                    }
+                });
+            }
+        }).catch((error) => {
+            document.getElementById('errormsg').textContent = 'Error: ' + error;
+        });
+}
+
+function submitPhoto(e) {
+    e.preventDefault();
+    const pa = document.getElementById("photoanswer");
+    if (pa.files.length <= 0) {
+        return;
+    }
+    const postData = new FormData();
+    postData.append("photo", pa.files[0]);
+    fetch(PhotoEndpoint, { method: 'post', body: postData })
+        .then(response => {
+            if (!response.ok) {
+                response.text().then(p => { document.getElementById('errormsg').textContent = 'Error: ' + p });
+            } else {
+                pa.value = "";
+                response.json().then(p => {
+                    processResponse(p);
+                    if (p.hasOwnProperty("GameArtifacts") && p.GameArtifacts.hasOwnProperty("action")) {
+                        document.getElementById('errormsg').textContent = p.GameArtifacts["action"];
+                    }
+                });
+            }
+        }).catch((error) => {
+            document.getElementById('errormsg').textContent = 'Error: ' + error;
+        });
+}
+
 var video = document.createElement("video");
 var canvasElement = document.getElementById("canvas");
 var canvas = canvasElement.getContext("2d");
@@ -227,4 +321,50 @@ function tick() {
         }
     }
     requestAnimationFrame(tick);
-}
\ No newline at end of file
+}
+
+// refreshClue picks up hints revealed while the page is open. It leaves the page alone if anything
+// goes wrong, since the next refresh or move will bring it up to date.
+function refreshClue() {
+    if (document.hidden) {
+        return;
+    }
+    fetch(ClueEndpoint)
+        .then(response => {
+            if (response.ok) {
+                response.json().then(p => processResponse(p));
+            }
+        }).catch(() => { });
+}
+
+function pokerSwap(discard) {
+    const postData = new URLSearchParams({ "discard": discard });
+    fetch(PokerSwapEndpoint, { method: 'post', body: postData })
+        .then(response => {
+            if (!response.ok) {
+                response.text().then(p => { document.getElementById('errormsg').textContent = 'Error: ' + p });
+            } else {
+                response.json().then(p => processResponse(p));
+            }
+        }).catch((error) => {
+            document.getElementById('errormsg').textContent = 'Error: ' + error;
+        });
+}
+
+document.getElementById("tab-1").addEventListener("change", tabchange);
+document.getElementById("tab-2").addEventListener("change", tabchange);
+document.getElementById("resumeScanning").addEventListener("click", resumeScanning);
+// The poker buttons come and go with the clue, so one listener handles them all.
+document.getElementById("cluecontent").addEventListener("click", function (e) {
+    const b = e.target.closest("[data-poker-swap]");
+    if (b) {
+        pokerSwap(b.dataset.pokerSwap);
+    }
+});
+if (ClueEndpoint && GameScript.dataset.clueRefreshSec > 0) {
+    window.setInterval(refreshClue, GameScript.dataset.clueRefreshSec * 1000);
+}
+if (document.getElementById("textanswerform")) {
+    document.getElementById("textanswerform").addEventListener("submit", submitTextAnswer);
+    document.getElementById("photoanswerform").addEventListener("submit", submitPhoto);
+}
@@ -0,0 +1,31 @@
+/**
+ * Copyright 2022 Google LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+function toggleFullscreen() {
+    const el = document.getElementById('mybadge');
+    if (document.fullscreenElement) {
+        document.exitFullscreen();
+    } else if (el.requestFullscreen) {
+        el.requestFullscreen();
+    } else {
+        // Browsers without the fullscreen API still get the bright, full-page badge.
+        el.classList.toggle('mybadge-full');
+    }
+}
+
+if (document.getElementById('mybadge')) {
+    document.getElementById('mybadge').addEventListener('click', toggleFullscreen);
+}
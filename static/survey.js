@@ -0,0 +1,32 @@
+/**
+ * Copyright 2022 Google LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+function dqsubmit(e) {
+    e.preventDefault();
+    const formElement = document.getElementById('dqform');
+    const data = new URLSearchParams(new FormData(formElement));
+    fetch('/submitsurvey', { method: 'post', body: data })
+        .then(response => {
+            if (!response.ok) {
+                document.getElementById('errormsg').textContent =
+                    'Could not submit your data. ' + response;
+            } else {
+                window.location.assign('/game');
+            }
+        });
+}
+
+document.getElementById('dqform').addEventListener('submit', dqsubmit);
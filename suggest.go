@@ -0,0 +1,259 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/sushovande/qr-mixer-game/common"
+	"github.com/sushovande/qr-mixer-game/qrpb"
+
+	"google.golang.org/protobuf/encoding/prototext"
+	proto "google.golang.org/protobuf/proto"
+)
+
+// SUGGEST_PER_KIND is the most suggestions of each kind that are made.
+const SUGGEST_PER_KIND = 5
+
+// SUGGEST_MIN_SHARE is the smallest fraction of players that can be the answer to a suggested
+// SURVEY_ANS question. Fewer, and players are likely to get stuck on it.
+const SUGGEST_MIN_SHARE = 0.1
+
+// SUGGEST_MAX_CARD_HOLDERS is the most players that can share a card or rank for it to be a clue.
+const SUGGEST_MAX_CARD_HOLDERS = 3
+
+// QuestionSuggestion is a candidate game question worked out from the registered players.
+type QuestionSuggestion struct {
+	// Reason says why the question works, for the organizer.
+	Reason string
+	// Answers are the players who are a right answer now.
+	Answers  []string
+	Question *qrpb.GameQuestion
+}
+
+// suggestionKey identifies what a question asks for, so suggestions already in the game are skipped.
+func suggestionKey(q *qrpb.GameQuestion) string {
+	if q.GetType() == qrpb.GQType_SURVEY_ANS {
+		return fmt.Sprintf("survey %v %v", q.GetSurveyId(), q.GetSurveyTrueIsCorrect())
+	}
+	a := append([]string{}, q.GetAnsUsernames()...)
+	sort.Strings(a)
+	return "users " + strings.Join(a, ",")
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+// SuggestQuestions proposes game questions that the registered players can answer: survey questions
+// with a useful split of yes and no, cards or ranks that only a few players hold, and pairs of
+// survey answers that exactly one player gave. Questions already in sqs are left out.
+func SuggestQuestions(srs []StateRow, ss *qrpb.SurveySet, roster *qrpb.QRMappingSet, sqs *qrpb.GameQSet) []QuestionSuggestion {
+	have := make(map[string]bool)
+	for _, q := range sqs.GetGameQuestions() {
+		have[suggestionKey(q)] = true
+	}
+	players := make([]StateRow, len(srs))
+	copy(players, srs)
+	sort.Slice(players, func(i, j int) bool { return players[i].Username < players[j].Username })
+
+	sugs := make([]QuestionSuggestion, 0)
+	add := func(s QuestionSuggestion, count *int) {
+		if *count >= SUGGEST_PER_KIND || have[suggestionKey(s.Question)] {
+			return
+		}
+		have[suggestionKey(s.Question)] = true
+		s.Question.QuestionId = proto.Int64(0)
+		sugs = append(sugs, s)
+		*count++
+	}
+	// who returns the players whose answer to survey question id is want.
+	who := func(id int64, want bool) []string {
+		us := make([]string, 0)
+		for _, sr := range players {
+			if getSurveyResponse(sr.UserInfo, id) == want {
+				us = append(us, sr.Username)
+			}
+		}
+		return us
+	}
+
+	n := 0
+	for _, sq := range ss.GetSurveyQuestions() {
+		yes := who(sq.GetQuestionId(), true)
+		want, answers := true, yes
+		if len(yes)*2 > len(players) {
+			want, answers = false, who(sq.GetQuestionId(), false)
+		}
+		if len(answers) < 2 || float64(len(answers)) < SUGGEST_MIN_SHARE*float64(len(players)) {
+			continue
+		}
+		add(QuestionSuggestion{
+			Reason:  fmt.Sprintf("%v of %v players said %v to %q", len(answers), len(players), yesNo(want), sq.GetQuestionText()),
+			Answers: answers,
+			Question: &qrpb.GameQuestion{
+				Type:                qrpb.GQType_SURVEY_ANS.Enum(),
+				QuestionHtml:        proto.String(fmt.Sprintf("Find someone who said %v to “%v”.", yesNo(want), sq.GetQuestionText())),
+				SurveyId:            proto.Int64(sq.GetQuestionId()),
+				SurveyTrueIsCorrect: proto.Bool(want),
+			},
+		}, &n)
+	}
+
+	cards := make(map[string][]string)
+	ranks := make(map[string][]string)
+	cardOrder := make([]string, 0)
+	rankOrder := make([]string, 0)
+	byUser := make(map[string]*qrpb.QRMapping)
+	for _, m := range roster.GetQrMappings() {
+		byUser[m.GetUsername()] = m
+	}
+	for _, sr := range players {
+		m := byUser[sr.Username]
+		if m == nil || m.GetCardRank() == 0 {
+			continue
+		}
+		card := PokerCardName(&qrpb.PokerCard{Suit: m.CardSuit, Rank: m.CardRank})
+		rank := strings.TrimRight(card, "♠♥♣♦")
+		if _, ok := cards[card]; !ok {
+			cardOrder = append(cardOrder, card)
+		}
+		if _, ok := ranks[rank]; !ok {
+			rankOrder = append(rankOrder, rank)
+		}
+		cards[card] = append(cards[card], sr.Username)
+		ranks[rank] = append(ranks[rank], sr.Username)
+	}
+	n = 0
+	for _, card := range cardOrder {
+		if len(cards[card]) > SUGGEST_MAX_CARD_HOLDERS {
+			continue
+		}
+		html := fmt.Sprintf("Find someone whose badge shows the %v.", card)
+		if len(cards[card]) == 1 {
+			html = fmt.Sprintf("Find the one person whose badge shows the %v.", card)
+		}
+		add(QuestionSuggestion{
+			Reason:  fmt.Sprintf("%v of %v players have the %v", len(cards[card]), len(players), card),
+			Answers: cards[card],
+			Question: &qrpb.GameQuestion{
+				Type:         qrpb.GQType_USERNAME_LIST.Enum(),
+				QuestionHtml: proto.String(html),
+				AnsUsernames: cards[card],
+			},
+		}, &n)
+	}
+	for _, rank := range rankOrder {
+		if len(ranks[rank]) < 2 || len(ranks[rank]) > SUGGEST_MAX_CARD_HOLDERS {
+			continue
+		}
+		add(QuestionSuggestion{
+			Reason:  fmt.Sprintf("%v of %v players have a %v", len(ranks[rank]), len(players), rank),
+			Answers: ranks[rank],
+			Question: &qrpb.GameQuestion{
+				Type:         qrpb.GQType_USERNAME_LIST.Enum(),
+				QuestionHtml: proto.String(fmt.Sprintf("Find someone whose badge shows a %v.", rank)),
+				AnsUsernames: ranks[rank],
+			},
+		}, &n)
+	}
+
+	n = 0
+	qs := ss.GetSurveyQuestions()
+	for i := 0; i < len(qs); i++ {
+		for j := i + 1; j < len(qs); j++ {
+			for _, wa := range []bool{true, false} {
+				for _, wb := range []bool{true, false} {
+					both := make([]string, 0)
+					for _, sr := range players {
+						if getSurveyResponse(sr.UserInfo, qs[i].GetQuestionId()) == wa && getSurveyResponse(sr.UserInfo, qs[j].GetQuestionId()) == wb {
+							both = append(both, sr.Username)
+						}
+					}
+					if len(both) != 1 {
+						continue
+					}
+					add(QuestionSuggestion{
+						Reason: fmt.Sprintf("only %v said %v to %q and %v to %q",
+							both[0], yesNo(wa), qs[i].GetQuestionText(), yesNo(wb), qs[j].GetQuestionText()),
+						Answers: both,
+						Question: &qrpb.GameQuestion{
+							Type: qrpb.GQType_USERNAME_LIST.Enum(),
+							QuestionHtml: proto.String(fmt.Sprintf("Find the one person who said %v to “%v” and %v to “%v”.",
+								yesNo(wa), qs[i].GetQuestionText(), yesNo(wb), qs[j].GetQuestionText())),
+							AnsUsernames: both,
+						},
+					}, &n)
+				}
+			}
+		}
+	}
+	return sugs
+}
+
+// SuggestionsTextproto writes the suggestions as game_questions entries that can be pasted into
+// the questions box, each after a comment saying why it works.
+func SuggestionsTextproto(sugs []QuestionSuggestion) string {
+	var b strings.Builder
+	for _, s := range sugs {
+		fmt.Fprintf(&b, "# %v: %v\n", s.Reason, strings.Join(s.Answers, ", "))
+		b.WriteString("game_questions: {\n")
+		for _, line := range strings.Split(strings.TrimSpace(prototext.Format(s.Question)), "\n") {
+			b.WriteString("  " + line + "\n")
+		}
+		b.WriteString("}\n")
+	}
+	return b.String()
+}
+
+// adminSuggestQuestions returns suggested game questions as text, for the questions page.
+func (env *Env) adminSuggestQuestions(w http.ResponseWriter, r *http.Request) {
+	log.Println("Req: ", r.URL)
+	srs, err := AdminGetAllUserStates(env.db)
+	if common.Should500(err, w, "could not fetch the players") {
+		return
+	}
+	ss, err := env.cgo.GetSurveySet()
+	if common.Should500(err, w, "could not read the survey") {
+		return
+	}
+	qrm, err := env.cgo.GetQRMappings()
+	if common.Should500(err, w, "could not fetch the qr code mappings") {
+		return
+	}
+	sqs, err := env.cgo.GetGameQSet()
+	if common.Should500(err, w, "could not read the game questions") {
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if len(srs) == 0 {
+		fmt.Fprint(w, "# Nobody has registered yet, so there is nothing to suggest.\n")
+		return
+	}
+	sugs := SuggestQuestions(srs, ss, qrm.mappings, sqs)
+	if len(sugs) == 0 {
+		fmt.Fprint(w, "# No new questions to suggest from the players who have registered so far.\n")
+		return
+	}
+	fmt.Fprint(w, SuggestionsTextproto(sugs))
+}
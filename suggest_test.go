@@ -0,0 +1,88 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/sushovande/qr-mixer-game/qrpb"
+
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSuggestQuestions(t *testing.T) {
+	env, err := createEnv(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer env.db.Close()
+	setupSynthetic(env.cgo, 10)
+
+	admin := "/9283e316-beaa-4182-b3a6-0937046251ee/suggestQuestions"
+	f := callController("GET", admin, "", nil, env.adminSuggestQuestions)
+	if !strings.Contains(f.resptext, "Nobody has registered yet") {
+		t.Errorf("expected nothing to suggest before anyone registered. got: %v", f.resptext)
+	}
+
+	// Three players said yes to the first survey question, and four to the second. Only
+	// username-7 said yes to both.
+	for i := 1; i <= 10; i++ {
+		sr := GetSyntheticStateRow(i, 1)
+		sr.UserInfo.SurveyAnswers = []*qrpb.SurveyAnswer{
+			{QuestionId: proto.Int64(1), IsTrue: proto.Bool(i == 7 || i == 2 || i == 3)},
+			{QuestionId: proto.Int64(2), IsTrue: proto.Bool(i >= 4 && i <= 7)},
+		}
+		AddUser(env.db, sr)
+	}
+
+	f = callController("GET", admin, "", nil, env.adminSuggestQuestions)
+	if f.statuscode != http.StatusOK {
+		t.Fatalf("expected suggestions. got: %v %v", f.statuscode, f.resptext)
+	}
+	var sqs qrpb.GameQSet
+	if err := prototext.Unmarshal([]byte(f.resptext), &sqs); err != nil {
+		t.Fatalf("expected suggestions that can be pasted into the questions box. got: %v\n%v", err, f.resptext)
+	}
+
+	survey, cards, pairs := 0, 0, 0
+	for _, q := range sqs.GetGameQuestions() {
+		switch {
+		case q.GetType() == qrpb.GQType_SURVEY_ANS:
+			survey++
+			if q.GetSurveyId() != 2 || !q.GetSurveyTrueIsCorrect() {
+				t.Errorf("expected the yes side of the second survey question, since Q3 already asks for yes to the first. got: %v", q)
+			}
+		case strings.Contains(q.GetQuestionHtml(), "badge shows"):
+			cards++
+		default:
+			pairs++
+			if len(q.GetAnsUsernames()) != 1 || q.GetAnsUsernames()[0] != "username-7" {
+				t.Errorf("expected username-7 to be the one who said yes to both. got: %v", q)
+			}
+		}
+		if q.GetQuestionId() != 0 {
+			t.Errorf("expected the organizer to number the suggestions. got: %v", q)
+		}
+	}
+	if survey != 1 || cards != SUGGEST_PER_KIND || pairs != 1 {
+		t.Errorf("expected 1 survey, %v card and 1 pair suggestion. got: %v, %v, %v\n%v", SUGGEST_PER_KIND, survey, cards, pairs, f.resptext)
+	}
+	if !strings.Contains(f.resptext, `# 4 of 10 players said yes to "dq-2": username-4, username-5, username-6, username-7`) {
+		t.Errorf("expected a comment with the reason and the answers. got: %v", f.resptext)
+	}
+}
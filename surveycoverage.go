@@ -0,0 +1,170 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/sushovande/qr-mixer-game/qrpb"
+
+	proto "google.golang.org/protobuf/proto"
+)
+
+// Defaults for the settings SurveyCoverage leaves unset.
+const (
+	DEFAULT_COVERAGE_MIN_PLAYERS    int64 = 1
+	DEFAULT_COVERAGE_MIN_REGISTERED int64 = 10
+)
+
+// CoverageMinPlayers returns how many players must be a right answer to a SURVEY_ANS or
+// USERNAME_LIST question.
+func CoverageMinPlayers(gc *qrpb.GameConfig) int64 {
+	sc := gc.GetSurveyCoverage()
+	if sc != nil && sc.MinPlayers != nil {
+		return sc.GetMinPlayers()
+	}
+	return DEFAULT_COVERAGE_MIN_PLAYERS
+}
+
+// CoverageMinRegistered returns how many players must register before backups are swapped in.
+func CoverageMinRegistered(gc *qrpb.GameConfig) int64 {
+	sc := gc.GetSurveyCoverage()
+	if sc != nil && sc.MinRegistered != nil {
+		return sc.GetMinRegistered()
+	}
+	return DEFAULT_COVERAGE_MIN_REGISTERED
+}
+
+// SurveyCoverageRow is how many registered players are a right answer to one SURVEY_ANS or
+// USERNAME_LIST question. Named is how many players a USERNAME_LIST question names, and 0 for a
+// SURVEY_ANS question.
+type SurveyCoverageRow struct {
+	QuestionId   int64
+	Variant      string
+	SurveyText   string
+	WantTrue     bool
+	Named        int
+	Players      int
+	Low          bool
+	HasBackup    bool
+	BackupActive bool
+}
+
+// SurveyCoverage counts the registered players who are a right answer to each SURVEY_ANS and
+// USERNAME_LIST question, in question order. Questions whose backup is already in use are listed
+// too, but retired variants are not. CHECKPOINT_ROUTE questions are left out: their checkpoints
+// are props, which never register, so counting registrations says nothing about them.
+func SurveyCoverage(srs []StateRow, sqs *qrpb.GameQSet, ss *qrpb.SurveySet, gc *qrpb.GameConfig) []SurveyCoverageRow {
+	registered := make(map[string]bool)
+	for _, sr := range srs {
+		registered[sr.Username] = true
+	}
+	rows := make([]SurveyCoverageRow, 0)
+	for _, q := range sqs.GetGameQuestions() {
+		if q.GetRetired() {
+			continue
+		}
+		row := SurveyCoverageRow{
+			QuestionId:   q.GetQuestionId(),
+			Variant:      q.GetVariant(),
+			HasBackup:    q.Backup != nil,
+			BackupActive: q.GetBackupActive(),
+		}
+		switch q.GetType() {
+		case qrpb.GQType_SURVEY_ANS:
+			for _, sr := range srs {
+				if getSurveyResponse(sr.UserInfo, q.GetSurveyId()) == q.GetSurveyTrueIsCorrect() {
+					row.Players++
+				}
+			}
+			row.SurveyText = GetSurveyQuestionByIndex(ss, q.GetSurveyId()).GetQuestionText()
+			row.WantTrue = q.GetSurveyTrueIsCorrect()
+		case qrpb.GQType_USERNAME_LIST:
+			row.Named = len(q.GetAnsUsernames())
+			for _, u := range q.GetAnsUsernames() {
+				if registered[u] {
+					row.Players++
+				}
+			}
+		default:
+			continue
+		}
+		row.Low = int64(row.Players) < CoverageMinPlayers(gc)
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// CheckSurveyCoverage swaps in the backup of every SURVEY_ANS or USERNAME_LIST question that too
+// few registered players can answer, if the organizer turned that on. It returns the ids of the questions it
+// swapped.
+func (env *Env) CheckSurveyCoverage() ([]int64, error) {
+	gc, err := env.cgo.GetGameConfig()
+	if err != nil {
+		return nil, err
+	}
+	if !gc.GetSurveyCoverage().GetAutoBackup() {
+		return nil, nil
+	}
+	srs, err := AdminGetAllUserStates(env.db)
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(srs)) < CoverageMinRegistered(gc) {
+		return nil, nil
+	}
+	ss, err := env.cgo.GetSurveySet()
+	if err != nil {
+		return nil, err
+	}
+
+	// The rows are worked out on the latest questions, so a save or a retired variant made at the
+	// same time is kept.
+	swapped := make([]int64, 0)
+	err = env.cgo.UpdateGameQSet(func(updated *qrpb.GameQSet) (bool, error) {
+		swapped = swapped[:0]
+		for _, row := range SurveyCoverage(srs, updated, ss, gc) {
+			if !row.Low || !row.HasBackup || row.BackupActive {
+				continue
+			}
+			for _, q := range QuestionVariants(updated, row.QuestionId) {
+				if q.GetVariant() == row.Variant {
+					q.BackupActive = proto.Bool(true)
+				}
+			}
+			swapped = append(swapped, row.QuestionId)
+		}
+		return len(swapped) > 0, nil
+	})
+	if err != nil || len(swapped) == 0 {
+		return nil, err
+	}
+	for _, id := range swapped {
+		log.Printf("too few players can answer question %v, swapped in its backup", id)
+	}
+	return swapped, nil
+}
+
+// CheckSurveyCoverageSettings returns an error if the survey coverage settings make no sense.
+func CheckSurveyCoverageSettings(gc *qrpb.GameConfig) error {
+	if CoverageMinPlayers(gc) < 0 {
+		return fmt.Errorf("survey_coverage.min_players cannot be negative, got %v", CoverageMinPlayers(gc))
+	}
+	if CoverageMinRegistered(gc) < 0 {
+		return fmt.Errorf("survey_coverage.min_registered cannot be negative, got %v", CoverageMinRegistered(gc))
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/sushovande/qr-mixer-game/qrpb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSurveyCoverage(t *testing.T) {
+	env, err := createEnv(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer env.db.Close()
+	setupSynthetic(env.cgo, 10)
+	sqs, _ := env.cgo.GetGameQSet()
+	// Q3 wants people who said yes to the first survey question.
+	sqs.GameQuestions[2].Backup = &qrpb.GameQuestion{
+		Type:         qrpb.GQType_ANY_PERSON.Enum(),
+		QuestionHtml: proto.String("backup-3"),
+	}
+	env.cgo.SetGameQSet(sqs)
+	env.cgo.SetGameConfig(&qrpb.GameConfig{SurveyCoverage: &qrpb.SurveyCoverage{
+		AutoBackup:    proto.Bool(true),
+		MinRegistered: proto.Int64(3),
+	}})
+
+	register := func(i int) {
+		ck := http.Cookie{Name: "sid", Value: fmt.Sprintf("cookie-%v", i)}
+		callController("POST", "/submitsurvey", fmt.Sprintf("qr=qrcode-%v&dqans1=false&dqans2=false", i), &ck, env.submitSurvey)
+	}
+	register(1)
+	register(2)
+	sqs, _ = env.cgo.GetGameQSet()
+	if GetQuestionByIndex(sqs, 3).GetQuestionHtml() != "qHtml-3" {
+		t.Errorf("expected no backup before enough players registered. got: %v", GetQuestionByIndex(sqs, 3))
+	}
+
+	register(3)
+	sqs, _ = env.cgo.GetGameQSet()
+	q3 := GetQuestionByIndex(sqs, 3)
+	if q3.GetQuestionHtml() != "backup-3" || q3.GetQuestionId() != 3 {
+		t.Errorf("expected the backup to be swapped in. got: %v", q3)
+	}
+	if GetQuestionByIndex(sqs, 5).GetBackupActive() {
+		t.Errorf("expected Q5 to be left alone, everyone said no")
+	}
+
+	u1, _ := GetUserStateByUsername(env.db, "username-1")
+	mr, _ := env.Step(GetSyntheticStateRow(1, 3).State, "qrcode-2")
+	if mr.actionString != "Correct!" {
+		t.Errorf("expected the backup to be asked. got: %v", mr.actionString)
+	}
+	u1.State.UserLevel = proto.Int64(3)
+	UpdateUserDetails(env.db, u1)
+	ck := http.Cookie{Name: "sid", Value: "cookie-1"}
+	f := callController("GET", "/game", "", &ck, env.gameHandler)
+	if !strings.Contains(f.resptext, "backup-3") {
+		t.Errorf("expected the backup on the game page. got: %v", f.resptext)
+	}
+
+	f = callController("GET", "/allUsers", "", nil, env.adminAllUsers)
+	if !strings.Contains(f.resptext, "Backup in use") {
+		t.Errorf("expected the coverage table to show the backup. got: %v", f.resptext)
+	}
+}
+
+func TestSurveyCoverageRows(t *testing.T) {
+	sqs := &qrpb.GameQSet{GameQuestions: []*qrpb.GameQuestion{
+		{QuestionId: proto.Int64(1), Type: qrpb.GQType_SURVEY_ANS.Enum(), SurveyId: proto.Int64(1), SurveyTrueIsCorrect: proto.Bool(true)},
+		{QuestionId: proto.Int64(2), Type: qrpb.GQType_ANY_PERSON.Enum()},
+		{QuestionId: proto.Int64(3), Type: qrpb.GQType_SURVEY_ANS.Enum(), SurveyId: proto.Int64(1), SurveyTrueIsCorrect: proto.Bool(false)},
+		{QuestionId: proto.Int64(4), Type: qrpb.GQType_USERNAME_LIST.Enum(), AnsUsernames: []string{"username-1", "username-7"}},
+		{QuestionId: proto.Int64(5), Type: qrpb.GQType_CHECKPOINT_ROUTE.Enum(), RouteUsernames: []string{"zspare01"}},
+	}}
+	ss := &qrpb.SurveySet{SurveyQuestions: []*qrpb.SurveyQuestion{{QuestionId: proto.Int64(1), QuestionText: proto.String("Tea?")}}}
+	srs := make([]StateRow, 0)
+	for i, yes := range []bool{true, false, false} {
+		sr := GetSyntheticStateRow(i+1, 1)
+		sr.UserInfo.SurveyAnswers = []*qrpb.SurveyAnswer{{QuestionId: proto.Int64(1), IsTrue: proto.Bool(yes)}}
+		srs = append(srs, *sr)
+	}
+	gc := &qrpb.GameConfig{SurveyCoverage: &qrpb.SurveyCoverage{MinPlayers: proto.Int64(2)}}
+
+	rows := SurveyCoverage(srs, sqs, ss, gc)
+	if len(rows) != 3 {
+		t.Fatalf("expected a row for each survey and username list question. got: %v", rows)
+	}
+	if rows[0].QuestionId != 1 || rows[0].Players != 1 || !rows[0].Low || rows[0].SurveyText != "Tea?" {
+		t.Errorf("expected one yes, too few. got: %+v", rows[0])
+	}
+	if rows[1].QuestionId != 3 || rows[1].Players != 2 || rows[1].Low {
+		t.Errorf("expected two no, enough. got: %+v", rows[1])
+	}
+	if rows[2].QuestionId != 4 || rows[2].Named != 2 || rows[2].Players != 1 || !rows[2].Low {
+		t.Errorf("expected one of the two named players, too few. got: %+v", rows[2])
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/sushovande/qr-mixer-game/qrpb"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+	proto "google.golang.org/protobuf/proto"
+)
+
+// StepText returns the GameState resulting from the player typing an answer at the current GameState.
+func (env *Env) StepText(old *qrpb.GameState, typed string) (StepResponse, error) {
+	return env.StepTextWithHandicap(old, typed, nil)
+}
+
+// StepTextWithHandicap is StepText for a player with the given handicap, which may be nil.
+func (env *Env) StepTextWithHandicap(old *qrpb.GameState, typed string, h *qrpb.PlayerHandicap) (StepResponse, error) {
+	result := NewStepResponse()
+	result.actionString = "Lost a Life!"
+	result.actionResult = *qrpb.ActionLog_RESULT_LOST_LIFE.Enum()
+	result.scannedClue = typed
+	result.newState = proto.Clone(old).(*qrpb.GameState)
+
+	if StepTerminal(&result, old) {
+		return result, nil
+	}
+
+	sqs, err := env.cgo.GetGameQSet()
+	if err != nil {
+		return StepResponse{}, err
+	}
+	ss, err := env.cgo.GetSurveySet()
+	if err != nil {
+		return StepResponse{}, err
+	}
+
+	sq := GetQuestionForPlayer(sqs, old)
+	if StepMisconfigured(&result, sq, ss, old.GetUserLevel()) {
+		return result, nil
+	}
+	if StepLocked(&result, sq, time.Now()) {
+		return result, nil
+	}
+	if sq.GetType() != qrpb.GQType_TEXT_ANSWER {
+		result.actionString = "This clue needs a scan!"
+		result.actionResult = *qrpb.ActionLog_RESULT_IGNORED.Enum()
+		return result, nil
+	}
+
+	gc, err := env.cgo.GetGameConfig()
+	if err != nil {
+		return StepResponse{}, err
+	}
+	fx, err := env.CurrentEventEffects()
+	if err != nil {
+		return StepResponse{}, err
+	}
+	rules := ApplyHandicap(fx.Apply(RulesFor(sq, gc)), h)
+
+	if TextAnswerMatches(sq.TextAnswers, typed) {
+		AnswerCorrect(&result, rules)
+	} else {
+		AnswerWrong(&result, rules)
+	}
+
+	MaybeGrantMetal(&result, fx.DoubleTokenChance, env.rnd)
+
+	if result.newState.GetLife() <= 0 {
+		result.actionString = "Dead!"
+		result.actionResult = *qrpb.ActionLog_RESULT_ALREADY_DEAD.Enum()
+		result.newState.UserLevel = proto.Int64(-1)
+	}
+
+	return result, nil
+}
+
+// TextAnswerMatches returns true if the typed answer matches any of the accepted answers.
+func TextAnswerMatches(accepted []string, typed string) bool {
+	nt := NormalizeTextAnswer(typed)
+	if len(nt) == 0 {
+		return false
+	}
+	for _, a := range accepted {
+		if NormalizeTextAnswer(a) == nt {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeTextAnswer lowercases the answer, strips diacritics, and collapses
+// all runs of whitespace into a single space.
+func NormalizeTextAnswer(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	stripped, _, err := transform.String(t, s)
+	if err != nil {
+		stripped = s
+	}
+	return strings.Join(strings.Fields(strings.ToLower(stripped)), " ")
+}
@@ -0,0 +1,123 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sushovande/qr-mixer-game/qrpb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestNormalizeTextAnswer(t *testing.T) {
+	cases := map[string]string{
+		"Crème Brûlée":      "creme brulee",
+		"  the   BIG\tcat ": "the big cat",
+		"Ångström":          "angstrom",
+		"":                  "",
+	}
+	for in, want := range cases {
+		if got := NormalizeTextAnswer(in); got != want {
+			t.Errorf("NormalizeTextAnswer(%q): got %q, want %q", in, got, want)
+		}
+	}
+
+	if !TextAnswerMatches([]string{"pineapple", "Ananas"}, " ANANÁS ") {
+		t.Errorf("expected the alternate answer to match")
+	}
+	if TextAnswerMatches([]string{"pineapple"}, "   ") {
+		t.Errorf("expected a blank answer not to match")
+	}
+}
+
+func TestTextAnswerQuestion(t *testing.T) {
+	env, err := createEnv(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer env.db.Close()
+	setupSynthetic(env.cgo, 10)
+
+	// Switch Q2 to a typed answer
+	sqs, _ := env.cgo.GetGameQSet()
+	sqs.GameQuestions[1].Type = qrpb.GQType_TEXT_ANSWER.Enum()
+	sqs.GameQuestions[1].AnsUsernames = []string{}
+	sqs.GameQuestions[1].TextAnswers = []string{"Eiffel Tower", "tour eiffel"}
+	env.cgo.SetGameQSet(sqs)
+
+	ck1 := http.Cookie{Name: "sid", Value: "cookie-1", Expires: time.Now().Add(24 * 30 * time.Hour)}
+	callController("POST", "/submitsurvey", "qr=qrcode-1&dqans1=true&dqans2=false", &ck1, env.submitSurvey)
+
+	f := callController("POST", "/makemove", "answer=qrcode-1", &ck1, env.makeMove)
+	var mr MoveResponse
+	if err := json.Unmarshal([]byte(f.resptext), &mr); err != nil {
+		t.Fatal(err)
+	}
+	if mr.GameArtifacts["answerType"] != "text" {
+		t.Errorf("expected level 2 to ask for a typed answer. got: %v", mr.GameArtifacts)
+	}
+
+	// Scanning on a typed question is not penalised.
+	f = callController("POST", "/makemove", "answer=qrcode-3", &ck1, env.makeMove)
+	if err := json.Unmarshal([]byte(f.resptext), &mr); err != nil {
+		t.Fatal(err)
+	}
+	if mr.State.GetLife() != STARTING_LIFE || mr.State.GetUserLevel() != 2 {
+		t.Errorf("expected the scan to be ignored. got life %v, level %v", mr.State.GetLife(), mr.State.GetUserLevel())
+	}
+
+	f = callController("POST", "/submitanswer", "answer=louvre", &ck1, env.submitTextAnswer)
+	if err := json.Unmarshal([]byte(f.resptext), &mr); err != nil {
+		t.Fatal(err)
+	}
+	if mr.GameArtifacts["action"] != "Lost a Life!" {
+		t.Errorf("expected game action to be Lost a Life!. got: %v", mr.GameArtifacts["action"])
+	}
+
+	f = callController("POST", "/submitanswer", "answer=Tour+Eiffel", &ck1, env.submitTextAnswer)
+	mr = MoveResponse{}
+	if err := json.Unmarshal([]byte(f.resptext), &mr); err != nil {
+		t.Fatal(err)
+	}
+	if mr.GameArtifacts["action"] != "Correct!" {
+		t.Errorf("expected game action to be Correct!. got: %v", mr.GameArtifacts["action"])
+	}
+	if mr.State.GetUserLevel() != 3 {
+		t.Errorf("did not move to next level. want: 3. got: %v", mr.State.GetUserLevel())
+	}
+	if _, ok := mr.GameArtifacts["answerType"]; ok {
+		t.Errorf("expected level 3 to be a scanned question")
+	}
+
+	// Typing on a scanned question is not penalised either.
+	f = callController("POST", "/submitanswer", "answer=eiffel+tower", &ck1, env.submitTextAnswer)
+	if err := json.Unmarshal([]byte(f.resptext), &mr); err != nil {
+		t.Fatal(err)
+	}
+	if mr.State.GetLife() != STARTING_LIFE-1 || mr.State.GetUserLevel() != 3 {
+		t.Errorf("expected the typed answer to be ignored. got life %v, level %v", mr.State.GetLife(), mr.State.GetUserLevel())
+	}
+
+	// Other game modes have no typed answers.
+	env.cgo.SetGameConfig(&qrpb.GameConfig{GameMode: proto.String(GAME_MODE_BINGO)})
+	f = callController("POST", "/submitanswer", "answer=eiffel+tower", &ck1, env.submitTextAnswer)
+	if f.statuscode != http.StatusNotFound {
+		t.Errorf("expected typed answers to be turned away in bingo. got: %v %v", f.statuscode, f.resptext)
+	}
+}
@@ -0,0 +1,493 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/sushovande/qr-mixer-game/qrpb"
+
+	"google.golang.org/protobuf/encoding/prototext"
+	proto "google.golang.org/protobuf/proto"
+)
+
+// The parts of a game definition, which are saved separately.
+const (
+	PART_QUESTIONS = "questions"
+	PART_SURVEY    = "survey"
+	PART_CONFIG    = "config"
+	PART_ROSTER    = "roster"
+)
+
+// HUNT_VICTORY_LEVEL is the level a player is on once they have won the treasure hunt.
+const HUNT_VICTORY_LEVEL int64 = 22
+
+// GameDefinition is everything the organizer sets up before the game.
+type GameDefinition struct {
+	Questions *qrpb.GameQSet
+	Survey    *qrpb.SurveySet
+	Config    *qrpb.GameConfig
+	Roster    *qrpb.QRMappingSet
+}
+
+// ValidationIssue is one problem found in a game definition.
+type ValidationIssue struct {
+	// Part is where the problem is, one of the PART_ constants.
+	Part    string
+	Message string
+}
+
+func (vi ValidationIssue) String() string {
+	return vi.Part + ": " + vi.Message
+}
+
+// ValidationReport lists what is wrong with a game definition. Errors break the game, warnings are
+// probably mistakes but the game still works.
+type ValidationReport struct {
+	Errors   []ValidationIssue
+	Warnings []ValidationIssue
+}
+
+func (vr *ValidationReport) errorf(part string, format string, a ...interface{}) {
+	vr.Errors = append(vr.Errors, ValidationIssue{Part: part, Message: fmt.Sprintf(format, a...)})
+}
+
+func (vr *ValidationReport) warnf(part string, format string, a ...interface{}) {
+	vr.Warnings = append(vr.Warnings, ValidationIssue{Part: part, Message: fmt.Sprintf(format, a...)})
+}
+
+// HasErrorsIn reports whether any of the errors are in one of the given parts.
+func (vr *ValidationReport) HasErrorsIn(parts ...string) bool {
+	for _, e := range vr.Errors {
+		for _, p := range parts {
+			if e.Part == p {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// String lists the errors, then the warnings, one per line.
+func (vr *ValidationReport) String() string {
+	var sb strings.Builder
+	for _, e := range vr.Errors {
+		fmt.Fprintf(&sb, "error: %v\n", e)
+	}
+	for _, w := range vr.Warnings {
+		fmt.Fprintf(&sb, "warning: %v\n", w)
+	}
+	return sb.String()
+}
+
+// CurrentGameDefinition returns the game definition that is saved now.
+func (env *Env) CurrentGameDefinition() (GameDefinition, error) {
+	return loadGameDefinition(env.cgo)
+}
+
+func loadGameDefinition(cgo *CachedGameOptions) (GameDefinition, error) {
+	var def GameDefinition
+	var err error
+	if def.Questions, err = cgo.GetGameQSet(); err != nil {
+		return def, err
+	}
+	if def.Survey, err = cgo.GetSurveySet(); err != nil {
+		return def, err
+	}
+	if def.Config, err = cgo.GetGameConfig(); err != nil {
+		return def, err
+	}
+	qrm, err := cgo.GetQRMappings()
+	if err != nil {
+		return def, err
+	}
+	def.Roster = qrm.mappings
+	return def, nil
+}
+
+// ValidateGame checks that the parts of the game definition make sense, on their own and together.
+func ValidateGame(def GameDefinition) *ValidationReport {
+	vr := &ValidationReport{}
+	validateConfig(vr, def.Config)
+	validateSurvey(vr, def.Survey, def.Questions)
+	validateRoster(vr, def.Roster)
+	validateQuestions(vr, def)
+	validateHTML(vr, def)
+	return vr
+}
+
+// validateHTML warns about html that saving on the questions page would strip.
+func validateHTML(vr *ValidationReport, def GameDefinition) {
+	if def.Survey != nil {
+		for _, l := range SanitizeSurvey(proto.Clone(def.Survey).(*qrpb.SurveySet)) {
+			vr.warnf(PART_SURVEY, "unsafe html in %v", l)
+		}
+	}
+	if def.Questions != nil {
+		for _, l := range SanitizeQuestions(proto.Clone(def.Questions).(*qrpb.GameQSet)) {
+			vr.warnf(PART_QUESTIONS, "unsafe html in %v", l)
+		}
+	}
+}
+
+func validateConfig(vr *ValidationReport, gc *qrpb.GameConfig) {
+	if _, ok := LookupGameMode(gc.GetGameMode()); !ok {
+		vr.errorf(PART_CONFIG, "unknown game mode %q, expected one of %v", gc.GetGameMode(), GameModeNames())
+	}
+	if d := gc.GetQuestionDefaults(); d != nil {
+		if err := CheckAnswerRules("question_defaults", d.WrongPenalty, d.Reward, d.MaxAttempts); err != nil {
+			vr.errorf(PART_CONFIG, "%v", err)
+		}
+	}
+	if err := CheckGameSettings(gc); err != nil {
+		vr.errorf(PART_CONFIG, "%v", err)
+	}
+	if err := CheckSurveyCoverageSettings(gc); err != nil {
+		vr.errorf(PART_CONFIG, "%v", err)
+	}
+}
+
+func validateSurvey(vr *ValidationReport, ss *qrpb.SurveySet, gqset *qrpb.GameQSet) {
+	used := make(map[int64]bool)
+	for _, q := range gqset.GetGameQuestions() {
+		for _, aq := range []*qrpb.GameQuestion{q, q.GetBackup()} {
+			if aq.GetType() == qrpb.GQType_SURVEY_ANS {
+				used[aq.GetSurveyId()] = true
+			}
+		}
+	}
+	seen := make(map[int64]bool)
+	for _, s := range ss.GetSurveyQuestions() {
+		id := s.GetQuestionId()
+		if seen[id] {
+			vr.errorf(PART_SURVEY, "survey question %v appears more than once", id)
+		}
+		seen[id] = true
+		if len(strings.TrimSpace(s.GetQuestionText())) == 0 {
+			vr.errorf(PART_SURVEY, "survey question %v has no question_text", id)
+		}
+		if s.GetType() != qrpb.SurveyType_BOOLEAN {
+			vr.warnf(PART_SURVEY, "survey question %v has type %v, only BOOLEAN is supported", id, s.GetType())
+		}
+		if !used[id] {
+			vr.warnf(PART_SURVEY, "survey question %v is not used by any game question", id)
+		}
+	}
+}
+
+func validateRoster(vr *ValidationReport, qrset *qrpb.QRMappingSet) {
+	usernames := make(map[string]bool)
+	qrcodes := make(map[string]string)
+	for _, m := range qrset.GetQrMappings() {
+		u := m.GetUsername()
+		if len(u) == 0 {
+			vr.errorf(PART_ROSTER, "%q has no username", m.GetDisplayName())
+			continue
+		}
+		if usernames[u] {
+			vr.errorf(PART_ROSTER, "username %v appears more than once", u)
+		}
+		usernames[u] = true
+		if len(m.GetQrcode()) == 0 {
+			vr.errorf(PART_ROSTER, "%v has no qrcode", u)
+		} else if other, dup := qrcodes[m.GetQrcode()]; dup {
+			vr.errorf(PART_ROSTER, "%v and %v have the same qrcode", other, u)
+		} else {
+			qrcodes[m.GetQrcode()] = u
+		}
+		if len(m.GetDisplayName()) == 0 {
+			vr.warnf(PART_ROSTER, "%v has no display_name", u)
+		}
+		if m.GetCardSuit() == qrpb.CardSuit_CARD_SUIT_UNSPECIFIED || m.GetCardRank() < 1 || m.GetCardRank() > 13 {
+			vr.warnf(PART_ROSTER, "%v has no valid card, their badge will not show one", u)
+		}
+	}
+	if err := CheckHandicaps(qrset); err != nil {
+		vr.errorf(PART_ROSTER, "%v", err)
+	}
+}
+
+func validateQuestions(vr *ValidationReport, def GameDefinition) {
+	inRoster := make(map[string]bool)
+	for _, m := range def.Roster.GetQrMappings() {
+		inRoster[m.GetUsername()] = true
+	}
+	surveys := make(map[int64]bool)
+	for _, s := range def.Survey.GetSurveyQuestions() {
+		surveys[s.GetQuestionId()] = true
+	}
+
+	ids := make(map[int64]bool)
+	for _, q := range def.Questions.GetGameQuestions() {
+		id := q.GetQuestionId()
+		what := fmt.Sprintf("question %v", id)
+		if len(q.GetVariant()) > 0 {
+			what = fmt.Sprintf("question %v (variant %q)", id, q.GetVariant())
+		}
+		if id < 1 {
+			vr.errorf(PART_QUESTIONS, "%v: question_id must be at least 1", what)
+		}
+		ids[id] = true
+		validateQuestion(vr, what, q, inRoster, surveys)
+		if b := q.GetBackup(); b != nil {
+			if b.Backup != nil || b.BackupActive != nil {
+				vr.errorf(PART_QUESTIONS, "%v: a backup cannot have a backup of its own", what)
+			}
+			if b.Variant != nil || b.Retired != nil {
+				vr.errorf(PART_QUESTIONS, "%v: a backup takes its variant from the question it replaces", what)
+			}
+			validateQuestion(vr, what+" backup", b, inRoster, surveys)
+		} else if q.GetBackupActive() {
+			vr.errorf(PART_QUESTIONS, "%v: backup_active is set, but there is no backup", what)
+		}
+	}
+	validateVariants(vr, def.Questions, ids)
+
+	// The other game modes do not step through the questions.
+	if len(def.Config.GetGameMode()) > 0 && def.Config.GetGameMode() != GAME_MODE_HUNT {
+		return
+	}
+	missing := make([]string, 0)
+	for l := StartingLevel(def.Config); l <= HUNT_VICTORY_LEVEL; l++ {
+		if !ids[l] {
+			missing = append(missing, fmt.Sprint(l))
+		}
+	}
+	if len(missing) > 0 {
+		vr.errorf(PART_QUESTIONS, "the treasure hunt goes from question %v to %v, but these are missing: %v",
+			StartingLevel(def.Config), HUNT_VICTORY_LEVEL, strings.Join(missing, ", "))
+	}
+	unreachable := make([]int64, 0)
+	for id := range ids {
+		if id > HUNT_VICTORY_LEVEL {
+			unreachable = append(unreachable, id)
+		}
+	}
+	sort.Slice(unreachable, func(i, j int) bool { return unreachable[i] < unreachable[j] })
+	for _, id := range unreachable {
+		vr.warnf(PART_QUESTIONS, "question %v comes after question %v, where the treasure hunt is won, so nobody will see it", id, HUNT_VICTORY_LEVEL)
+	}
+}
+
+// validateVariants checks the levels that have several questions: each needs its own variant name,
+// and at least one of them must still be in use.
+func validateVariants(vr *ValidationReport, sqs *qrpb.GameQSet, ids map[int64]bool) {
+	levels := make([]int64, 0, len(ids))
+	for id := range ids {
+		levels = append(levels, id)
+	}
+	sort.Slice(levels, func(i, j int) bool { return levels[i] < levels[j] })
+	for _, id := range levels {
+		vs := QuestionVariants(sqs, id)
+		if len(vs) > 1 {
+			names := make(map[string]bool)
+			for _, q := range vs {
+				if len(q.GetVariant()) == 0 {
+					vr.errorf(PART_QUESTIONS, "question %v appears more than once, so each copy needs its own variant name", id)
+					break
+				}
+				if names[q.GetVariant()] {
+					vr.errorf(PART_QUESTIONS, "question %v has two variants called %q", id, q.GetVariant())
+				}
+				names[q.GetVariant()] = true
+			}
+		}
+		if len(ActiveVariants(sqs, id)) == 0 {
+			vr.errorf(PART_QUESTIONS, "question %v is retired, and has no other variant to give players", id)
+		}
+	}
+}
+
+func validateQuestion(vr *ValidationReport, what string, q *qrpb.GameQuestion, inRoster map[string]bool, surveys map[int64]bool) {
+	t := q.GetType()
+	if len(strings.TrimSpace(q.GetQuestionHtml())) == 0 {
+		vr.warnf(PART_QUESTIONS, "%v has no question_html", what)
+	}
+	for _, name := range UnknownQuestionVars(q.GetQuestionHtml()) {
+		vr.errorf(PART_QUESTIONS, "%v: question_html uses {{%v}}, which is not one of %v", what, name, QuestionVarList())
+	}
+	if q.Difficulty != nil && (q.GetDifficulty() < 1 || q.GetDifficulty() > 5) {
+		vr.errorf(PART_QUESTIONS, "%v: difficulty must be from 1 to 5, got %v", what, q.GetDifficulty())
+	}
+
+	switch t {
+	case qrpb.GQType_GQTYPE_UNSPECIFIED:
+		vr.errorf(PART_QUESTIONS, "%v has no type", what)
+	case qrpb.GQType_USERNAME_LIST:
+		if len(q.GetAnsUsernames()) == 0 {
+			vr.errorf(PART_QUESTIONS, "%v has no ans_usernames, so nobody can answer it", what)
+		}
+	case qrpb.GQType_SURVEY_ANS:
+		if q.SurveyId == nil {
+			vr.errorf(PART_QUESTIONS, "%v has no survey_id", what)
+		} else if !surveys[q.GetSurveyId()] {
+			vr.errorf(PART_QUESTIONS, "%v: survey_id %v is not a survey question", what, q.GetSurveyId())
+		}
+	case qrpb.GQType_TEXT_ANSWER:
+		if len(q.GetTextAnswers()) == 0 {
+			vr.errorf(PART_QUESTIONS, "%v has no text_answers, so nobody can answer it", what)
+		}
+	case qrpb.GQType_CHECKPOINT_ROUTE:
+		if len(q.GetRouteUsernames()) == 0 {
+			vr.errorf(PART_QUESTIONS, "%v has no route_usernames", what)
+		}
+	}
+
+	// Fields that only one type reads are silently ignored on the others.
+	if t != qrpb.GQType_USERNAME_LIST && len(q.GetAnsUsernames()) > 0 {
+		vr.warnf(PART_QUESTIONS, "%v is a %v question, ans_usernames is ignored", what, t)
+	}
+	if t != qrpb.GQType_SURVEY_ANS && (q.SurveyId != nil || q.SurveyTrueIsCorrect != nil) {
+		vr.warnf(PART_QUESTIONS, "%v is a %v question, survey_id is ignored", what, t)
+	}
+	if t != qrpb.GQType_TEXT_ANSWER && len(q.GetTextAnswers()) > 0 {
+		vr.warnf(PART_QUESTIONS, "%v is a %v question, text_answers is ignored", what, t)
+	}
+	if t != qrpb.GQType_CHECKPOINT_ROUTE && (len(q.GetRouteUsernames()) > 0 || q.RouteMistakeCostsLife != nil) {
+		vr.warnf(PART_QUESTIONS, "%v is a %v question, the route is ignored", what, t)
+	}
+
+	if t == qrpb.GQType_USERNAME_LIST {
+		seen := make(map[string]bool)
+		for _, u := range q.GetAnsUsernames() {
+			if !inRoster[u] {
+				vr.errorf(PART_QUESTIONS, "%v: %v in ans_usernames is not in the roster", what, u)
+			}
+			if seen[u] {
+				vr.warnf(PART_QUESTIONS, "%v: %v is in ans_usernames more than once", what, u)
+			}
+			seen[u] = true
+		}
+	}
+	for _, u := range q.GetRouteUsernames() {
+		if !inRoster[u] {
+			vr.errorf(PART_QUESTIONS, "%v: %v in route_usernames is not in the roster", what, u)
+		}
+	}
+
+	if err := CheckAnswerRules(what, q.WrongPenalty, q.Reward, q.MaxAttempts); err != nil {
+		vr.errorf(PART_QUESTIONS, "%v", err)
+	}
+	if err := CheckUnlockTime(what, q.GetUnlockTime()); err != nil {
+		vr.errorf(PART_QUESTIONS, "%v", err)
+	}
+}
+
+// gameFlags pick the game a subcommand works on: the one saved in a database, with any parts
+// replaced by textproto files.
+type gameFlags struct {
+	db, questions, survey, config, roster *string
+}
+
+// addGameFlags adds the gameFlags to fs. verb is what the subcommand does with the game.
+func addGameFlags(fs *flag.FlagSet, verb string) gameFlags {
+	return gameFlags{
+		db:        fs.String("db", "datastore.db", "the database with the game to "+verb),
+		questions: fs.String("questions", "", "a GameQSet textproto to "+verb+" instead of the saved questions"),
+		survey:    fs.String("survey", "", "a SurveySet textproto to "+verb+" instead of the saved survey"),
+		config:    fs.String("config", "", "a GameConfig textproto to "+verb+" instead of the saved config"),
+		roster:    fs.String("roster", "", "a QRMappingSet textproto to "+verb+" instead of the saved roster"),
+	}
+}
+
+// load reads the game the flags pick. The database is only opened for the parts not given as
+// files, and only read, so it must already exist. It writes what went wrong to out and returns
+// false if it could not.
+func (gf gameFlags) load(out io.Writer) (GameDefinition, bool) {
+	var def GameDefinition
+	if len(*gf.questions) == 0 || len(*gf.survey) == 0 || len(*gf.config) == 0 || len(*gf.roster) == 0 {
+		if _, err := os.Stat(*gf.db); err != nil {
+			fmt.Fprintf(out, "could not open %v: %v\n", *gf.db, err)
+			return GameDefinition{}, false
+		}
+		db, err := sql.Open("sqlite3", "file:"+*gf.db+"?mode=ro")
+		if err == nil {
+			err = db.Ping()
+		}
+		if err != nil {
+			fmt.Fprintf(out, "could not open %v: %v\n", *gf.db, err)
+			return GameDefinition{}, false
+		}
+		defer db.Close()
+		def, err = loadGameDefinition(CreateCachedGameOptions(db))
+		if err != nil {
+			fmt.Fprintf(out, "could not read the game from %v: %v\n", *gf.db, err)
+			return GameDefinition{}, false
+		}
+	}
+
+	files := []struct {
+		path string
+		msg  proto.Message
+	}{
+		{*gf.questions, &qrpb.GameQSet{}},
+		{*gf.survey, &qrpb.SurveySet{}},
+		{*gf.config, &qrpb.GameConfig{}},
+		{*gf.roster, &qrpb.QRMappingSet{}},
+	}
+	for _, f := range files {
+		if len(f.path) == 0 {
+			continue
+		}
+		b, err := os.ReadFile(f.path)
+		if err == nil {
+			err = prototext.Unmarshal(b, f.msg)
+		}
+		if err != nil {
+			fmt.Fprintf(out, "could not read %v: %v\n", f.path, err)
+			return GameDefinition{}, false
+		}
+		switch m := f.msg.(type) {
+		case *qrpb.GameQSet:
+			def.Questions = m
+		case *qrpb.SurveySet:
+			def.Survey = m
+		case *qrpb.GameConfig:
+			def.Config = m
+		case *qrpb.QRMappingSet:
+			def.Roster = m
+		}
+	}
+	return def, true
+}
+
+// runValidate is the validate subcommand. It checks the game in the database, with any parts given
+// as textproto files checked in place of the saved ones, and returns the exit status.
+func runValidate(args []string, out io.Writer) int {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	fs.SetOutput(out)
+	gf := addGameFlags(fs, "check")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	def, ok := gf.load(out)
+	if !ok {
+		return 2
+	}
+
+	vr := ValidateGame(def)
+	fmt.Fprint(out, vr)
+	fmt.Fprintf(out, "%v errors, %v warnings\n", len(vr.Errors), len(vr.Warnings))
+	if len(vr.Errors) > 0 {
+		return 1
+	}
+	return 0
+}
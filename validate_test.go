@@ -0,0 +1,216 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sushovande/qr-mixer-game/qrpb"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+)
+
+func hasIssue(issues []ValidationIssue, part string, text string) bool {
+	for _, vi := range issues {
+		if vi.Part == part && strings.Contains(vi.Message, text) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestValidateGame(t *testing.T) {
+	env, err := createEnv(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer env.db.Close()
+	setupGlobals(env.cgo)
+
+	def, err := env.CurrentGameDefinition()
+	if err != nil {
+		t.Fatal(err)
+	}
+	vr := ValidateGame(def)
+	if len(vr.Errors) != 0 {
+		t.Errorf("expected the synthetic game to be valid. got: %v", vr)
+	}
+	if !hasIssue(vr.Warnings, PART_QUESTIONS, "question 23 comes after question 22") {
+		t.Errorf("expected a warning about the unreachable question. got: %v", vr)
+	}
+
+	def.Questions = proto.Clone(def.Questions).(*qrpb.GameQSet)
+	qs := def.Questions.GetGameQuestions()
+	def.Questions.GameQuestions = append(qs[:4], qs[5:]...)
+	qs[0].AnsUsernames = append(qs[0].AnsUsernames, "nobody")
+	qs[1].Type = qrpb.GQType_SURVEY_ANS.Enum()
+	qs[1].SurveyId = proto.Int64(7)
+	qs[2].Type = qrpb.GQType_TEXT_ANSWER.Enum()
+	qs[3].BackupActive = proto.Bool(true)
+	vr = ValidateGame(def)
+	for _, want := range []string{
+		"question 4: backup_active is set, but there is no backup",
+		"question 1: nobody in ans_usernames is not in the roster",
+		"question 2: survey_id 7 is not a survey question",
+		"question 3 has no text_answers",
+		"these are missing: 5",
+	} {
+		if !hasIssue(vr.Errors, PART_QUESTIONS, want) {
+			t.Errorf("expected the error %q. got: %v", want, vr)
+		}
+	}
+	if !hasIssue(vr.Warnings, PART_QUESTIONS, "question 2 is a SURVEY_ANS question, ans_usernames is ignored") {
+		t.Errorf("expected a warning about the leftover ans_usernames. got: %v", vr)
+	}
+	if vr.HasErrorsIn(PART_ROSTER, PART_CONFIG) {
+		t.Errorf("expected only the questions to have errors. got: %v", vr)
+	}
+
+	def.Roster = proto.Clone(def.Roster).(*qrpb.QRMappingSet)
+	def.Roster.QrMappings[1].Qrcode = proto.String("qrcode-1")
+	def.Config = &qrpb.GameConfig{GameMode: proto.String("bingo"), StartingLife: proto.Int64(0)}
+	vr = ValidateGame(def)
+	if !hasIssue(vr.Errors, PART_ROSTER, "username-1 and username-2 have the same qrcode") {
+		t.Errorf("expected a duplicate qrcode error. got: %v", vr)
+	}
+	if !hasIssue(vr.Errors, PART_CONFIG, "starting_life must be at least 1") {
+		t.Errorf("expected a config error. got: %v", vr)
+	}
+	if hasIssue(vr.Errors, PART_QUESTIONS, "these are missing") {
+		t.Errorf("expected bingo not to need every question. got: %v", vr)
+	}
+}
+
+func TestValidateOnSave(t *testing.T) {
+	env, err := createEnv(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer env.db.Close()
+	setupSynthetic(env.cgo, 10)
+
+	// The roster is too small for the questions, but saving the config alone is fine.
+	f := callController("POST", "/saveQuestions", "gameconfig="+url.QueryEscape(`starting_life: 4`), nil, env.adminSaveQuestions)
+	if f.statuscode != http.StatusOK {
+		t.Errorf("expected the config to be saved. got: %v %v", f.statuscode, f.resptext)
+	}
+	f = callController("POST", "/saveQuestions", "gameq="+url.QueryEscape(`game_questions: { question_id: 1 type: SURVEY_ANS survey_id: 9 }`), nil, env.adminSaveQuestions)
+	if f.statuscode != http.StatusInternalServerError || !strings.Contains(f.resptext, "survey_id 9 is not a survey question") {
+		t.Errorf("expected the questions to be rejected with the report. got: %v %v", f.statuscode, f.resptext)
+	}
+	sqs, _ := env.cgo.GetGameQSet()
+	if len(sqs.GetGameQuestions()) != 23 {
+		t.Errorf("expected the rejected questions not to be saved. got: %v", sqs)
+	}
+
+	// A roster missing people the questions need is saved, and the problems reported.
+	qrm, _ := env.cgo.GetQRMappings()
+	roster := proto.Clone(qrm.mappings).(*qrpb.QRMappingSet)
+	roster.QrMappings = roster.QrMappings[:5]
+	js, _ := protojson.Marshal(roster)
+	f = callController("POST", "/saveUserQrMapping", "users="+url.QueryEscape(string(js)), nil, env.adminSaveUserQrMapping)
+	if f.statuscode != http.StatusOK || !strings.Contains(f.resptext, "username-7 in ans_usernames is not in the roster") {
+		t.Errorf("expected the roster to be saved with a report. got: %v %v", f.statuscode, f.resptext)
+	}
+	roster.QrMappings = append(roster.QrMappings, roster.QrMappings[0])
+	js, _ = protojson.Marshal(roster)
+	f = callController("POST", "/saveUserQrMapping", "users="+url.QueryEscape(string(js)), nil, env.adminSaveUserQrMapping)
+	if f.statuscode != http.StatusInternalServerError || !strings.Contains(f.resptext, "username-1 appears more than once") {
+		t.Errorf("expected a duplicate player to be rejected. got: %v %v", f.statuscode, f.resptext)
+	}
+}
+
+func TestValidateCommand(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "datastore.db")
+	var out bytes.Buffer
+	if code := runValidate([]string{"-db", dbPath}, &out); code != 2 || !strings.Contains(out.String(), "no such file") {
+		t.Errorf("expected a missing database to be an error. got: %v %v", code, out.String())
+	}
+	if _, err := os.Stat(dbPath); err == nil {
+		t.Errorf("expected validate not to create the database")
+	}
+
+	db, err := DbInit(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+	out.Reset()
+	if code := runValidate([]string{"-db", dbPath}, &out); code != 0 {
+		t.Errorf("expected the default game to pass. got: %v %v", code, out.String())
+	}
+
+	env, err := createEnv(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer env.db.Close()
+	setupGlobals(env.cgo)
+	def, _ := env.CurrentGameDefinition()
+	write := func(name string, m proto.Message) string {
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, []byte(prototext.Format(m)), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	out.Reset()
+	code := runValidate([]string{"-db", dbPath,
+		"-questions", write("q.textproto", def.Questions),
+		"-survey", write("s.textproto", def.Survey),
+		"-roster", write("r.textproto", def.Roster)}, &out)
+	if code != 0 || !strings.Contains(out.String(), "0 errors, 3 warnings") {
+		t.Errorf("expected the files to pass. got: %v %v", code, out.String())
+	}
+
+	// With every part given as a file, no database is needed.
+	out.Reset()
+	missing := filepath.Join(dir, "missing.db")
+	code = runValidate([]string{"-db", missing,
+		"-questions", filepath.Join(dir, "q.textproto"),
+		"-survey", filepath.Join(dir, "s.textproto"),
+		"-config", write("c.textproto", def.Config),
+		"-roster", filepath.Join(dir, "r.textproto")}, &out)
+	if code != 0 {
+		t.Errorf("expected the files alone to pass. got: %v %v", code, out.String())
+	}
+	if _, err := os.Stat(missing); err == nil {
+		t.Errorf("expected validate not to create a database when every part is a file")
+	}
+}
+
+func TestDefaultGameIsValid(t *testing.T) {
+	env, err := createEnv(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer env.db.Close()
+	def, err := env.CurrentGameDefinition()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vr := ValidateGame(def); len(vr.Errors) != 0 {
+		t.Errorf("expected the game in default-data to be valid. got: %v", vr)
+	}
+}